@@ -2,21 +2,29 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/nielwyn/inventory-system/config"
 	"github.com/nielwyn/inventory-system/internal/database"
+	"github.com/nielwyn/inventory-system/internal/export"
 	"github.com/nielwyn/inventory-system/internal/handlers"
+	"github.com/nielwyn/inventory-system/internal/maintenance"
 	"github.com/nielwyn/inventory-system/internal/middleware"
+	"github.com/nielwyn/inventory-system/internal/models"
 	"github.com/nielwyn/inventory-system/internal/repository"
 	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/internal/storage"
+	"github.com/nielwyn/inventory-system/internal/worker"
 	"github.com/nielwyn/inventory-system/pkg/logger"
+	"github.com/nielwyn/inventory-system/pkg/response"
 	"github.com/nielwyn/inventory-system/pkg/validator"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
@@ -43,35 +51,179 @@ func main() {
 	gin.SetMode(cfg.Server.Mode)
 
 	// Initialize database
-	db, err := database.New(cfg.Database.GetDSN())
+	db, err := database.New(cfg.Database.GetDSN(), cfg.Database.Timezone)
 	if err != nil {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
 	defer db.Close()
 
 	// Run database migrations
-	if err := db.AutoMigrate(); err != nil {
+	if err := db.AutoMigrate(cfg.Database.MigrationLockTimeout); err != nil {
 		logger.Fatal("Failed to run database migrations", zap.Error(err))
 	}
 
+	if cfg.Database.BreakerFailures > 0 {
+		breaker := repository.NewCircuitBreaker(repository.CircuitBreakerOptions{
+			Failures: cfg.Database.BreakerFailures,
+			Cooldown: cfg.Database.BreakerCooldown,
+			Probes:   cfg.Database.BreakerProbes,
+		})
+		if err := db.DB.Use(breaker); err != nil {
+			logger.Fatal("Failed to install database circuit breaker", zap.Error(err))
+		}
+	}
+
 	// Register custom validators
-	validator.RegisterCustomValidations()
+	validator.RegisterCustomValidations(validator.Limits{
+		MaxBulkSize: cfg.Inventory.MaxBulkSize,
+		MaxQuantity: cfg.Inventory.MaxQuantity,
+		MaxPrice:    models.MaxPrice,
+	})
 
 	// Initialize repositories
-	userRepo := repository.NewUserRepository(db.DB)
-	inventoryRepo := repository.NewInventoryRepository(db.DB)
+	deleteMode, err := repository.ParseDeleteMode(cfg.Database.DeleteMode)
+	if err != nil {
+		logger.Fatal("Invalid delete mode", zap.Error(err))
+	}
+	userRepo := repository.NewUserRepository(db.DB, deleteMode)
+	inventoryRepo := repository.NewInventoryRepository(db.DB, deleteMode)
+	priceRepo := repository.NewPriceRepository(db.DB)
+	activityRepo := repository.NewActivityRepository(db.DB)
+	preferencesRepo := repository.NewPreferencesRepository(db.DB)
+	tagRepo := repository.NewTagRepository(db.DB)
+	locationRepo := repository.NewLocationRepository(db.DB)
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db.DB)
+	bundleRepo := repository.NewBundleRepository(db.DB)
+	summaryRepo := repository.NewSummaryRepository(db.DB)
+	sessionRepo := repository.NewSessionRepository(db.DB)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, cfg.JWT.Secret, cfg.JWT.ExpiryHours)
-	inventoryService := service.NewInventoryService(inventoryRepo)
+	claimsHook, err := service.UserClaims(cfg.JWT.UserClaims)
+	if err != nil {
+		logger.Fatal("Invalid JWT_USER_CLAIMS", zap.Error(err))
+	}
+	authService := service.NewAuthService(userRepo, sessionRepo, service.AuthOptions{
+		JWTSecret:       cfg.JWT.Secret,
+		JWTExpiryHours:  cfg.JWT.ExpiryHours,
+		JWTLeeway:       cfg.JWT.Leeway,
+		JWTKeyID:        cfg.JWT.KeyID,
+		JWTPreviousKeys: cfg.JWT.PreviousKeys,
+		ExtraClaims:     cfg.JWT.ExtraClaims,
+		ClaimsHook:      claimsHook,
+		LoginUserDetail: cfg.Auth.LoginUserDetail,
+		PasswordHistory: cfg.Auth.PasswordHistory,
+
+		ImpersonationTTL: cfg.Auth.ImpersonationTTL,
+	})
+	if err := service.ValidateExtraClaims(cfg.JWT.ExtraClaims); err != nil {
+		logger.Fatal("Invalid JWT_EXTRA_CLAIMS", zap.Error(err))
+	}
+	if err := handlers.ValidatePublicRoutes(cfg.Auth.PublicRoutes); err != nil {
+		logger.Fatal("Invalid AUTH_PUBLIC_ROUTES", zap.Error(err))
+	}
+	if err := service.ValidateImmutableFields(cfg.Inventory.ImmutableFields); err != nil {
+		logger.Fatal("Invalid INVENTORY_IMMUTABLE_FIELDS", zap.Error(err))
+	}
+	defaultSort, err := repository.ParseSort(cfg.Inventory.DefaultSort)
+	if err != nil {
+		logger.Fatal("Invalid INVENTORY_DEFAULT_SORT", zap.Error(err))
+	}
+	inventoryService := service.NewInventoryService(inventoryRepo, service.InventoryPolicy{
+		RequireCategory: cfg.Inventory.RequireCategory,
+		MinPrice:        cfg.Inventory.MinPrice,
+		AutoGenerateSKU: cfg.Inventory.AutoGenerateSKU,
+		SKUFormat:       cfg.Inventory.SKUFormat,
+		MaxBatchGetIDs:  cfg.Inventory.MaxBatchGetIDs,
+		SKUCase:         cfg.Inventory.SKUCase,
+		ImmutableFields: cfg.Inventory.ImmutableFields,
+		Categories:      cfg.Inventory.Categories,
+
+		MaxXLSXExportRows:  cfg.Inventory.MaxXLSXExportRows,
+		AllowNegativeStock: cfg.Inventory.AllowNegativeStock,
+		DefaultSort:        defaultSort,
+
+		SKUGenerationAttempts: cfg.Inventory.SKUGenerationAttempts,
+	})
+	pricingService := service.NewPricingService(priceRepo, inventoryRepo)
+	activityService := service.NewActivityService(activityRepo)
+	preferencesService := service.NewPreferencesService(preferencesRepo)
+	userService := service.NewUserService(userRepo)
+	tagService := service.NewTagService(tagRepo)
+	locationService := service.NewLocationService(locationRepo)
+	purchaseOrderService := service.NewPurchaseOrderService(purchaseOrderRepo, inventoryRepo, cfg.Inventory.SKUCase, cfg.Inventory.AllowOverReceipt)
+	bundleService := service.NewBundleService(bundleRepo, inventoryRepo, cfg.Inventory.SKUCase, cfg.Inventory.AllowNegativeStock)
+	summaryService := service.NewSummaryService(summaryRepo, service.SummaryOptions{
+		LowStockThreshold: cfg.Inventory.LowStockThreshold,
+		ActivityWindow:    cfg.Admin.SummaryActivityWindow,
+		CacheTTL:          cfg.Admin.SummaryCacheTTL,
+	})
+	categoryService := service.NewCategoryService(inventoryRepo, cfg.Inventory.CategoryDeleteBehavior)
 
 	// Initialize handlers
-	healthHandler := handlers.NewHealthHandler(db)
+	healthHandler := handlers.NewHealthHandler(db, handlers.HealthOptions{
+		LivenessTimeout:  cfg.Health.LivenessTimeout,
+		ReadinessTimeout: cfg.Health.ReadinessTimeout,
+	})
 	authHandler := handlers.NewAuthHandler(authService)
-	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService, preferencesService, export.LabelSize{
+		Width:  cfg.Inventory.LabelWidth,
+		Height: cfg.Inventory.LabelHeight,
+	})
+	pricingHandler := handlers.NewPricingHandler(pricingService)
+	activityHandler := handlers.NewActivityHandler(activityService, preferencesService)
+	preferencesHandler := handlers.NewPreferencesHandler(preferencesService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	userHandler := handlers.NewUserHandler(userService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	locationHandler := handlers.NewLocationHandler(locationService, preferencesService)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseOrderService)
+	bundleHandler := handlers.NewBundleHandler(bundleService)
+	summaryHandler := handlers.NewSummaryHandler(summaryService, cfg.Admin.SummaryCacheTTL)
+	schemaHandler := handlers.NewSchemaHandler()
+	maintenanceMode := maintenance.New(cfg.Maintenance.Enabled, cfg.Maintenance.RetryAfter)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceMode)
+
+	// Start background jobs
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	worker.Start(workerCtx, &workers, worker.NewPriceScheduler(pricingService), cfg.Worker.PriceSchedulerInterval)
+	// The inventory value is seeded now so /metrics reports it before the first interval ends
+	inventoryValueJob := worker.NewInventoryValue(summaryService)
+	if err := inventoryValueJob.Run(workerCtx); err != nil {
+		logger.Warn("Failed to compute initial inventory value", zap.Error(err))
+	}
+	worker.Start(workerCtx, &workers, inventoryValueJob, cfg.Worker.InventoryValueInterval)
+	if cfg.Retention.StockMovements > 0 || cfg.Retention.PriceHistory > 0 {
+		retentionService := service.NewRetentionService(repository.NewRetentionRepository(db.DB), service.RetentionOptions{
+			Periods: map[string]time.Duration{
+				repository.RetentionStockMovements: cfg.Retention.StockMovements,
+				repository.RetentionPriceHistory:   cfg.Retention.PriceHistory,
+			},
+			BatchSize: cfg.Retention.BatchSize,
+		})
+		worker.Start(workerCtx, &workers, worker.NewRetention(retentionService), cfg.Retention.Interval)
+	}
+	var store storage.Storage
+	if cfg.Snapshot.Enabled {
+		store, err = newSnapshotStorage(workerCtx, cfg.Snapshot)
+		if err != nil {
+			logger.Fatal("Failed to initialize snapshot storage", zap.Error(err))
+		}
+		snapshotService := service.NewSnapshotService(inventoryRepo, store, service.SnapshotOptions{
+			Prefix:    cfg.Snapshot.Prefix,
+			Retention: cfg.Snapshot.Retention,
+		})
+		worker.Start(workerCtx, &workers, worker.NewInventorySnapshot(snapshotService), cfg.Snapshot.Interval)
+	}
+
+	selfTestHandler := handlers.NewSelfTestHandler(service.NewSelfTestService(inventoryRepo, store))
+
+	if cfg.Warmup.Enabled {
+		warmup(db, inventoryService, cfg.Warmup)
+	}
 
 	// Setup router
-	router := setupRouter(healthHandler, authHandler, inventoryHandler, authService)
+	router := setupRouter(healthHandler, authHandler, inventoryHandler, pricingHandler, activityHandler, preferencesHandler, categoryHandler, userHandler, tagHandler, locationHandler, purchaseOrderHandler, bundleHandler, schemaHandler, maintenanceHandler, selfTestHandler, summaryHandler, authService, maintenanceMode, db, cfg)
 
 	// Create HTTP server
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -81,12 +233,19 @@ func main() {
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
 		MaxHeaderBytes: 1 << 20, // 1 MB
+		TLSConfig:      &tls.Config{MinVersion: cfg.Server.TLSMinVersion},
 	}
 
 	// Start server in a goroutine
 	go func() {
-		logger.Info("Server starting", zap.String("address", addr))
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Info("Server starting", zap.String("address", addr), zap.Bool("tls", cfg.Server.TLSEnabled))
+		var err error
+		if cfg.Server.TLSEnabled {
+			err = srv.ListenAndServeTLS(cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			logger.Fatal("Failed to start server", zap.Error(err))
 		}
 	}()
@@ -106,22 +265,114 @@ func main() {
 		logger.Error("Server forced to shutdown", zap.Error(err))
 	}
 
+	// Stop background jobs and wait for any in-flight run to finish
+	stopWorkers()
+	workers.Wait()
+
 	logger.Info("Server stopped")
 }
 
+// warmup opens database connections and runs the category summary, a query the first
+// requests commonly make, so Postgres has the pages cached. It gives up at cfg.Timeout;
+// a failed warmup is logged and startup carries on.
+func warmup(db *database.Database, inventoryService service.InventoryService, cfg config.WarmupConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := db.Warm(ctx, cfg.Connections); err != nil {
+		logger.Warn("Warmup could not open database connections", zap.Error(err))
+		return
+	}
+	if _, err := inventoryService.GetCategorySummary(ctx); err != nil {
+		logger.Warn("Warmup could not load the category summary", zap.Error(err))
+		return
+	}
+	logger.Info("Warmup completed", zap.Int("connections", cfg.Connections), zap.Duration("duration", time.Since(start)))
+}
+
+// newSnapshotStorage creates the object storage configured for inventory snapshots
+func newSnapshotStorage(ctx context.Context, cfg config.SnapshotConfig) (storage.Storage, error) {
+	if cfg.Storage == "local" {
+		return storage.NewLocalStorage(cfg.LocalDir)
+	}
+	return storage.NewS3Storage(ctx, cfg.S3Bucket, cfg.S3Endpoint)
+}
+
 // setupRouter configures all routes and middleware
 func setupRouter(
 	healthHandler *handlers.HealthHandler,
 	authHandler *handlers.AuthHandler,
 	inventoryHandler *handlers.InventoryHandler,
+	pricingHandler *handlers.PricingHandler,
+	activityHandler *handlers.ActivityHandler,
+	preferencesHandler *handlers.PreferencesHandler,
+	categoryHandler *handlers.CategoryHandler,
+	userHandler *handlers.UserHandler,
+	tagHandler *handlers.TagHandler,
+	locationHandler *handlers.LocationHandler,
+	purchaseOrderHandler *handlers.PurchaseOrderHandler,
+	bundleHandler *handlers.BundleHandler,
+	schemaHandler *handlers.SchemaHandler,
+	maintenanceHandler *handlers.MaintenanceHandler,
+	selfTestHandler *handlers.SelfTestHandler,
+	summaryHandler *handlers.SummaryHandler,
 	authService service.AuthService,
+	maintenanceMode *maintenance.Mode,
+	db *database.Database,
+	cfg *config.Config,
 ) *gin.Engine {
 	router := gin.New()
+	// Only trusted proxies may name the client IP; without any, ClientIP is the connecting address
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Fatal("Invalid TRUSTED_PROXIES", zap.Error(err))
+	}
+	router.RemoteIPHeaders = cfg.Server.ClientIPHeaders
+	// Answer a known path with an unsupported method with 405 rather than 404
+	router.HandleMethodNotAllowed = true
+	router.NoMethod(handlers.MethodNotAllowed(router))
+	requireAuth := middleware.Auth(authService, cfg.Auth.HeaderSchemes)
+	adminIPFilter, err := middleware.IPFilter(cfg.Admin.IPAllowlist, cfg.Admin.IPDenylist)
+	if err != nil {
+		logger.Fatal("Invalid admin IP filter", zap.Error(err))
+	}
 
 	// Global middleware
-	router.Use(gin.Recovery())
-	router.Use(middleware.Logger())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Logger(middleware.LoggerOptions{
+		ExcludePaths:      cfg.Log.ExcludePaths,
+		SuccessSampleRate: cfg.Log.SuccessSampleRate,
+	}))
+	router.Use(middleware.Recovery())
 	router.Use(middleware.CORS())
+	router.Use(middleware.SecurityHeaders(cfg.Server.TLSEnabled))
+	if cfg.Server.CompressionEnabled {
+		router.Use(middleware.Compression(cfg.Server.CompressionLevel))
+	}
+	router.Use(middleware.Timeout(middleware.TimeoutOptions{
+		Default: cfg.Server.RequestTimeout,
+		Routes:  cfg.Server.RouteTimeouts,
+	}))
+	if cfg.RateLimit.Enabled {
+		router.Use(middleware.RateLimit(middleware.RateLimitOptions{
+			Requests: cfg.RateLimit.Requests,
+			Window:   cfg.RateLimit.Window,
+		}))
+	}
+	if cfg.Log.Bodies {
+		router.Use(middleware.BodyLogger(middleware.BodyLogOptions{
+			MaxBytes:     cfg.Log.BodyMaxBytes,
+			RedactFields: cfg.Log.RedactFields,
+		}))
+	}
+	response.SetKeyCase(cfg.Server.JSONKeyCase)
+	response.SetDeleteNoContent(cfg.Server.DeleteNoContent)
+	if cfg.Server.JSONKeyCase == response.KeyCaseCamel {
+		router.Use(middleware.SnakeCaseRequests([]string{
+			"/api/v1/inventory/items/bulk",
+			"/api/v1/inventory/items/sync",
+		}))
+	}
 
 	// Health check endpoints (no authentication required)
 	router.GET("/health", healthHandler.Health)
@@ -130,25 +381,127 @@ func setupRouter(
 	// Metrics endpoint (Prometheus)
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// JSON Schema for request models (public)
+	router.GET("/schema", schemaHandler.ListSchemas)
+	router.GET("/schema/:model", schemaHandler.GetSchema)
+
+	// Profiling endpoints (admin only, off unless PPROF_ENABLED is set)
+	if cfg.Server.PprofEnabled {
+		pprofGroup := router.Group("/debug/pprof")
+		pprofGroup.Use(adminIPFilter)
+		pprofGroup.Use(requireAuth)
+		pprofGroup.Use(middleware.RequireRole(models.RoleAdmin))
+		handlers.RegisterPprofRoutes(pprofGroup)
+	}
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
 		// Auth endpoints (public)
 		auth := v1.Group("/auth")
 		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
+			auth.POST("/register", middleware.Maintenance(maintenanceMode), authHandler.Register)
+			auth.POST("/login", middleware.Maintenance(maintenanceMode), authHandler.Login)
+			if cfg.Auth.IntrospectionSecret != "" {
+				auth.POST("/introspect", middleware.ClientSecret(cfg.Auth.IntrospectionSecret), authHandler.Introspect)
+			}
+			auth.GET("/me/activity", requireAuth, activityHandler.GetMyActivity)
+			auth.GET("/me/preferences", requireAuth, preferencesHandler.GetMyPreferences)
+			auth.PUT("/me/preferences", requireAuth, middleware.Maintenance(maintenanceMode), preferencesHandler.UpdateMyPreferences)
+			auth.PUT("/me/password", requireAuth, middleware.Maintenance(maintenanceMode), middleware.Transaction(db.DB), authHandler.ChangePassword)
+			auth.GET("/me/sessions", requireAuth, authHandler.ListSessions)
+			auth.DELETE("/me/sessions/:id", requireAuth, middleware.Maintenance(maintenanceMode), authHandler.RevokeSession)
 		}
 
-		// Inventory endpoints (protected)
+		// Purging a category is destructive, so it is limited even when general rate limiting is off
+		bulkDeleteLimit := middleware.RateLimit(middleware.RateLimitOptions{
+			Requests: cfg.RateLimit.BulkDeleteRequests,
+			Window:   cfg.RateLimit.Window,
+		})
+
+		// Inventory endpoints (protected, except reads listed in AUTH_PUBLIC_ROUTES)
 		inventory := v1.Group("/inventory")
-		inventory.Use(middleware.Auth(authService))
+		inventory.Use(middleware.RouteAuth(authService, cfg.Auth.HeaderSchemes, cfg.Auth.PublicRoutes))
+		inventory.Use(middleware.Maintenance(maintenanceMode))
+		inventory.Use(middleware.Transaction(db.DB))
 		{
 			inventory.POST("/items", inventoryHandler.CreateItem)
+			inventory.POST("/items/bulk", inventoryHandler.BulkCreateItems)
+			inventory.PUT("/items/sync", inventoryHandler.SyncItems)
+			inventory.POST("/items/import", inventoryHandler.ImportItems)
 			inventory.GET("/items", inventoryHandler.GetAllItems)
+			inventory.GET("/items/export", inventoryHandler.ExportItems)
 			inventory.GET("/items/:id", inventoryHandler.GetItemByID)
+			inventory.POST("/items/batch-get", inventoryHandler.BatchGetItems)
 			inventory.PUT("/items/:id", inventoryHandler.UpdateItem)
+			inventory.PATCH("/items/:id", inventoryHandler.UpdateItem)
+			inventory.DELETE("/items", adminIPFilter, middleware.RequireRole(models.RoleAdmin), bulkDeleteLimit, inventoryHandler.DeleteItems)
 			inventory.DELETE("/items/:id", inventoryHandler.DeleteItem)
+			inventory.POST("/items/:id/adjust", inventoryHandler.AdjustStock)
+			inventory.POST("/items/:id/clone", inventoryHandler.CloneItem)
+			inventory.GET("/items/:id/label.pdf", inventoryHandler.GetItemLabel)
+			inventory.POST("/items/merge", adminIPFilter, middleware.RequireRole(models.RoleAdmin), inventoryHandler.MergeItems)
+			inventory.POST("/items/by-sku/:sku/adjust", inventoryHandler.AdjustStockBySKU)
+			inventory.POST("/reconcile", inventoryHandler.Reconcile)
+
+			inventory.POST("/items/:id/scheduled-prices", pricingHandler.SchedulePrice)
+			inventory.GET("/items/:id/scheduled-prices", pricingHandler.GetPendingPrices)
+			inventory.DELETE("/scheduled-prices/:id", pricingHandler.CancelScheduledPrice)
+			inventory.GET("/items/:id/price-history", pricingHandler.GetPriceHistory)
+
+			inventory.GET("/categories/summary", inventoryHandler.GetCategorySummary)
+			inventory.DELETE("/categories/:name", adminIPFilter, middleware.RequireRole(models.RoleAdmin), categoryHandler.DeleteCategory)
+			inventory.GET("/facets", inventoryHandler.GetFacets)
+			inventory.POST("/tags/:tag/assign", tagHandler.AssignTag)
+			inventory.POST("/tags/:tag/unassign", tagHandler.UnassignTag)
+			inventory.POST("/prices/bulk-update", inventoryHandler.BulkUpdatePrices)
+			inventory.GET("/reports/margins", adminIPFilter, middleware.RequireRole(models.RoleAdmin), inventoryHandler.GetMarginReport)
+		}
+
+		// Location endpoints (protected)
+		locations := v1.Group("/locations")
+		locations.Use(requireAuth)
+		{
+			locations.GET("/:id/stock", locationHandler.GetStock)
+		}
+
+		// Purchase order endpoints (protected)
+		purchaseOrders := v1.Group("/purchase-orders")
+		purchaseOrders.Use(requireAuth)
+		purchaseOrders.Use(middleware.Maintenance(maintenanceMode))
+		purchaseOrders.Use(middleware.Transaction(db.DB))
+		{
+			purchaseOrders.POST("", purchaseOrderHandler.CreatePurchaseOrder)
+			purchaseOrders.GET("/:id", purchaseOrderHandler.GetPurchaseOrder)
+			purchaseOrders.POST("/:id/receive", purchaseOrderHandler.Receive)
+		}
+
+		// Bundle endpoints (protected)
+		bundles := v1.Group("/bundles")
+		bundles.Use(requireAuth)
+		bundles.Use(middleware.Maintenance(maintenanceMode))
+		bundles.Use(middleware.Transaction(db.DB))
+		{
+			bundles.POST("", bundleHandler.CreateBundle)
+			bundles.GET("", bundleHandler.ListBundles)
+			bundles.GET("/:id", bundleHandler.GetBundle)
+			bundles.POST("/:id/consume", bundleHandler.Consume)
+		}
+
+		// Admin endpoints (protected, admin role only)
+		admin := v1.Group("/admin")
+		admin.Use(adminIPFilter)
+		admin.Use(requireAuth)
+		admin.Use(middleware.RequireRole(models.RoleAdmin))
+		{
+			admin.GET("/maintenance", maintenanceHandler.GetStatus)
+			admin.PUT("/maintenance", maintenanceHandler.SetStatus)
+			admin.POST("/selftest", selfTestHandler.Run)
+			admin.GET("/summary", summaryHandler.GetAdminSummary)
+			admin.GET("/report", summaryHandler.GetBusinessReport)
+			admin.GET("/users", userHandler.SearchUsers)
+			admin.POST("/users/:id/impersonate", authHandler.Impersonate)
+			admin.GET("/audit/export", activityHandler.ExportAudit)
 		}
 	}
 
@@ -1,19 +1,34 @@
 package config
 
 import (
+	"compress/gzip"
+	"crypto/tls"
 	"fmt"
+	"net/netip"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Log      LogConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	JWT         JWTConfig
+	Auth        AuthConfig
+	Log         LogConfig
+	Inventory   InventoryConfig
+	Worker      WorkerConfig
+	Snapshot    SnapshotConfig
+	Retention   RetentionConfig
+	Maintenance MaintenanceConfig
+	Health      HealthConfig
+	Warmup      WarmupConfig
+	RateLimit   RateLimitConfig
+	Admin       AdminConfig
 }
 
 // ServerConfig holds server configuration
@@ -21,6 +36,39 @@ type ServerConfig struct {
 	Host string
 	Port string
 	Mode string
+
+	// PprofEnabled mounts the admin-only /debug/pprof endpoints
+	PprofEnabled bool
+
+	// RequestTimeout caps the time spent handling a request (0 disables the cap);
+	// RouteTimeouts overrides it for single routes, keyed by full path
+	RequestTimeout time.Duration
+	RouteTimeouts  map[string]time.Duration
+
+	// TrustedProxies lists the proxies (CIDR ranges or addresses) whose
+	// ClientIPHeaders are believed when resolving the client IP; empty trusts none
+	TrustedProxies  []string
+	ClientIPHeaders []string
+
+	// JSONKeyCase is "snake" or "camel", the casing of response keys; camel also
+	// accepts camelCase request keys
+	JSONKeyCase string
+
+	// DeleteNoContent answers successful deletes with 204 and no body instead of 200
+	// with a message
+	DeleteNoContent bool
+
+	// CompressionEnabled gzips responses for clients that accept it, at CompressionLevel
+	// (1 fastest to 9 smallest, or -1 for the gzip default)
+	CompressionEnabled bool
+	CompressionLevel   int
+
+	// TLSEnabled serves HTTPS directly with TLSCertFile and TLSKeyFile instead of
+	// plaintext, accepting nothing older than TLSMinVersion
+	TLSEnabled    bool
+	TLSCertFile   string
+	TLSKeyFile    string
+	TLSMinVersion uint16
 }
 
 // DatabaseConfig holds database configuration
@@ -31,18 +79,209 @@ type DatabaseConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+
+	// DeleteMode is "soft" (keep rows with deleted_at set) or "hard" (remove rows permanently)
+	DeleteMode string
+
+	// Timezone is the zone timestamps are written and read in
+	Timezone *time.Location
+
+	// MigrationLockTimeout is how long startup waits for another replica's migrations (0 waits indefinitely)
+	MigrationLockTimeout time.Duration
+
+	// BreakerFailures is how many consecutive failed queries open the circuit breaker (0 disables it).
+	// The breaker fails queries fast for BreakerCooldown, then lets BreakerProbes queries through.
+	BreakerFailures int
+	BreakerCooldown time.Duration
+	BreakerProbes   int
 }
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
 	Secret      string
 	ExpiryHours int
+
+	// KeyID names Secret in the kid header of issued tokens; empty issues tokens without one.
+	// PreviousKeys maps the IDs of retired secrets to the secrets, which still validate tokens.
+	KeyID        string
+	PreviousKeys map[string]string
+
+	// Leeway is the clock skew tolerated when checking exp and iat
+	Leeway time.Duration
+
+	// ExtraClaims are added to every issued token, by claim name
+	ExtraClaims map[string]string
+
+	// UserClaims names the user attributes (username, email) added to the user's tokens
+	UserClaims []string
+}
+
+// AuthConfig holds authentication behaviour configuration
+type AuthConfig struct {
+	// LoginUserDetail is "full" (the whole user object) or "summary" (id, username, role)
+	LoginUserDetail string
+
+	// HeaderSchemes are the Authorization header schemes accepted, such as "Bearer"
+	HeaderSchemes []string
+
+	// IntrospectionSecret enables POST /auth/introspect for callers presenting it;
+	// the endpoint is not served while it is empty
+	IntrospectionSecret string
+
+	// PasswordHistory is how many of a user's latest passwords a new one must differ from
+	PasswordHistory int
+
+	// ImpersonationTTL is how long a token issued to impersonate a user is valid
+	ImpersonationTTL time.Duration
+
+	// PublicRoutes are the read routes, as registered, served without a token;
+	// a token that is sent is still checked
+	PublicRoutes []string
 }
 
 // LogConfig holds logging configuration
 type LogConfig struct {
 	Level    string
 	Encoding string
+
+	// Bodies logs request and response bodies; it only takes effect at debug level
+	Bodies       bool
+	BodyMaxBytes int
+	RedactFields []string
+
+	// ExcludePaths are paths whose successful requests are not logged, and SuccessSampleRate
+	// is the fraction of other successful requests logged; errors are always logged
+	ExcludePaths      []string
+	SuccessSampleRate float64
+}
+
+// InventoryConfig holds inventory business rules that vary by deployment
+type InventoryConfig struct {
+	RequireCategory bool
+	MinPrice        float64
+
+	// Categories, when set, are the only categories items may be given
+	Categories []string
+
+	// MaxQuantity caps item quantities and quantity changes sent by clients
+	MaxQuantity float64
+
+	// LowStockThreshold is the quantity at or below which an item counts as low on stock
+	LowStockThreshold float64
+
+	AutoGenerateSKU bool
+	SKUFormat       string
+	MaxBatchGetIDs  int
+
+	// SKUGenerationAttempts is how many generated SKUs a create tries when they are taken
+	SKUGenerationAttempts int
+
+	// MaxBulkSize caps the entries of every bulk request: items, IDs, counts or order lines.
+	// A JSON sync is uncapped and is written in batches of this size instead.
+	MaxBulkSize int
+
+	// SKUCase is "upper" or "lower" to normalize SKU casing, or empty to keep SKUs as sent
+	SKUCase string
+
+	// CategoryDeleteBehavior is "block", "reassign" or "cascade" and decides what
+	// happens to the items of a deleted category
+	CategoryDeleteBehavior string
+
+	// ImmutableFields lists item fields (JSON names such as "sku") that updates may not change
+	ImmutableFields []string
+
+	// MaxXLSXExportRows caps XLSX exports, which are built in memory (0 means no limit)
+	MaxXLSXExportRows int
+
+	// LabelWidth and LabelHeight are the page size of item labels in millimetres
+	LabelWidth  float64
+	LabelHeight float64
+
+	// AllowNegativeStock permits backorders: adjustments may take quantity below zero
+	AllowNegativeStock bool
+
+	// AllowOverReceipt permits receiving more against a purchase order line than was ordered
+	AllowOverReceipt bool
+
+	// DefaultSort is the item list sort used when a request gives none, in ?sort= syntax
+	DefaultSort string
+}
+
+// WorkerConfig holds background job configuration
+type WorkerConfig struct {
+	PriceSchedulerInterval time.Duration
+
+	// InventoryValueInterval is how often the inventory_value metric is recomputed
+	InventoryValueInterval time.Duration
+}
+
+// HealthConfig holds how long health checks wait for the database
+type HealthConfig struct {
+	LivenessTimeout  time.Duration
+	ReadinessTimeout time.Duration
+}
+
+// WarmupConfig holds the optional startup warmup run before the server accepts requests
+type WarmupConfig struct {
+	Enabled bool
+	// Connections is how many database connections are opened ahead of the first requests
+	Connections int
+	Timeout     time.Duration
+}
+
+// AdminConfig restricts which client IPs may reach the admin endpoints.
+// Entries are CIDR ranges or single addresses; an empty allowlist admits everyone not denied.
+type AdminConfig struct {
+	IPAllowlist []string
+	IPDenylist  []string
+
+	// SummaryActivityWindow is how far back the dashboard summary counts recent activity;
+	// SummaryCacheTTL is how long a computed summary or report is reused (0 disables caching)
+	SummaryActivityWindow time.Duration
+	SummaryCacheTTL       time.Duration
+}
+
+// RateLimitConfig holds the per-client request limit
+type RateLimitConfig struct {
+	Enabled  bool
+	Requests int
+	Window   time.Duration
+
+	// BulkDeleteRequests limits deletes of whole categories of items per Window;
+	// it applies even when Enabled is false
+	BulkDeleteRequests int
+}
+
+// MaintenanceConfig holds the maintenance mode settings.
+// Enabled is only the initial state; admins can switch it at runtime.
+type MaintenanceConfig struct {
+	Enabled    bool
+	RetryAfter time.Duration
+}
+
+// SnapshotConfig holds the scheduled inventory export configuration
+type SnapshotConfig struct {
+	Enabled   bool
+	Interval  time.Duration
+	Retention int
+	Prefix    string
+
+	// Storage is "s3" or "local"
+	Storage    string
+	S3Bucket   string
+	S3Endpoint string
+	LocalDir   string
+}
+
+// RetentionConfig holds how long history is kept before the purge job deletes it.
+// A zero period keeps a table's rows forever.
+type RetentionConfig struct {
+	StockMovements time.Duration
+	PriceHistory   time.Duration
+
+	// BatchSize is how many rows each delete statement removes, and Interval how often the job runs
+	BatchSize int
+	Interval  time.Duration
 }
 
 // Load loads configuration from environment variables
@@ -55,6 +294,20 @@ func Load() (*Config, error) {
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
 			Port: getEnv("SERVER_PORT", "8080"),
 			Mode: getEnv("GIN_MODE", "debug"),
+
+			PprofEnabled: getEnvBool("PPROF_ENABLED", false),
+
+			RequestTimeout:  getEnvDuration("REQUEST_TIMEOUT", 10*time.Second),
+			TrustedProxies:  getEnvList("TRUSTED_PROXIES", nil),
+			ClientIPHeaders: getEnvList("CLIENT_IP_HEADERS", []string{"X-Forwarded-For", "X-Real-IP"}),
+			JSONKeyCase:     getEnv("JSON_KEY_CASE", "snake"),
+			DeleteNoContent: getEnvBool("DELETE_NO_CONTENT", false),
+
+			CompressionEnabled: getEnvBool("COMPRESSION_ENABLED", false),
+
+			TLSEnabled:  getEnvBool("TLS_ENABLED", false),
+			TLSCertFile: getEnv("TLS_CERT_FILE", ""),
+			TLSKeyFile:  getEnv("TLS_KEY_FILE", ""),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -63,14 +316,115 @@ func Load() (*Config, error) {
 			Password: getEnv("DB_PASSWORD", "postgres"),
 			Name:     getEnv("DB_NAME", "inventory_db"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			DeleteMode: getEnv("DB_DELETE_MODE", "soft"),
+
+			MigrationLockTimeout: getEnvDuration("DB_MIGRATION_LOCK_TIMEOUT", 2*time.Minute),
+
+			BreakerFailures: getEnvInt("DB_BREAKER_FAILURES", 5),
+			BreakerCooldown: getEnvDuration("DB_BREAKER_COOLDOWN", 30*time.Second),
+			BreakerProbes:   getEnvInt("DB_BREAKER_PROBES", 1),
 		},
 		JWT: JWTConfig{
 			Secret:      getEnv("JWT_SECRET", "your-super-secret-jwt-key"),
 			ExpiryHours: getEnvInt("JWT_EXPIRY_HOURS", 24),
+			Leeway:      getEnvDuration("JWT_LEEWAY", 5*time.Second),
+			KeyID:       getEnv("JWT_KEY_ID", ""),
+			UserClaims:  getEnvList("JWT_USER_CLAIMS", nil),
+		},
+		Auth: AuthConfig{
+			LoginUserDetail: getEnv("AUTH_LOGIN_USER_DETAIL", "full"),
+			HeaderSchemes:   getEnvList("AUTH_HEADER_SCHEMES", []string{"Bearer"}),
+
+			IntrospectionSecret: getEnv("AUTH_INTROSPECTION_SECRET", ""),
+			PasswordHistory:     getEnvInt("AUTH_PASSWORD_HISTORY", 5),
+			ImpersonationTTL:    getEnvDuration("AUTH_IMPERSONATION_TTL", 15*time.Minute),
+			PublicRoutes:        getEnvList("AUTH_PUBLIC_ROUTES", nil),
 		},
 		Log: LogConfig{
 			Level:    getEnv("LOG_LEVEL", "debug"),
 			Encoding: getEnv("LOG_ENCODING", "json"),
+
+			Bodies:       getEnvBool("LOG_BODIES", false),
+			BodyMaxBytes: getEnvInt("LOG_BODY_MAX_BYTES", 4096),
+			RedactFields: getEnvList("LOG_REDACT_FIELDS", []string{"password", "token", "secret"}),
+
+			ExcludePaths:      getEnvList("LOG_EXCLUDE_PATHS", nil),
+			SuccessSampleRate: getEnvFloat("LOG_SUCCESS_SAMPLE_RATE", 1),
+		},
+		Inventory: InventoryConfig{
+			RequireCategory: getEnvBool("INVENTORY_REQUIRE_CATEGORY", false),
+			MinPrice:        getEnvFloat("INVENTORY_MIN_PRICE", 0),
+			MaxQuantity:     getEnvFloat("INVENTORY_MAX_QUANTITY", 999999999),
+
+			LowStockThreshold: getEnvFloat("INVENTORY_LOW_STOCK_THRESHOLD", 10),
+
+			AutoGenerateSKU:       getEnvBool("INVENTORY_SKU_AUTOGENERATE", false),
+			SKUFormat:             getEnv("INVENTORY_SKU_FORMAT", "{prefix}-{seq}"),
+			SKUGenerationAttempts: getEnvInt("INVENTORY_SKU_GENERATE_ATTEMPTS", 3),
+
+			MaxBatchGetIDs:  getEnvInt("INVENTORY_MAX_BATCH_GET_IDS", 100),
+			MaxBulkSize:     getEnvInt("INVENTORY_MAX_BULK_SIZE", 1000),
+			SKUCase:         getEnv("INVENTORY_SKU_CASE", ""),
+			ImmutableFields: getEnvList("INVENTORY_IMMUTABLE_FIELDS", nil),
+			Categories:      getEnvList("INVENTORY_CATEGORIES", nil),
+
+			CategoryDeleteBehavior: getEnv("INVENTORY_CATEGORY_DELETE_BEHAVIOR", "block"),
+
+			MaxXLSXExportRows:  getEnvInt("INVENTORY_EXPORT_XLSX_MAX_ROWS", 50000),
+			LabelWidth:         getEnvFloat("INVENTORY_LABEL_WIDTH_MM", 100),
+			LabelHeight:        getEnvFloat("INVENTORY_LABEL_HEIGHT_MM", 50),
+			AllowNegativeStock: getEnvBool("INVENTORY_ALLOW_NEGATIVE_STOCK", false),
+			AllowOverReceipt:   getEnvBool("INVENTORY_ALLOW_OVER_RECEIPT", false),
+			DefaultSort:        getEnv("INVENTORY_DEFAULT_SORT", "id"),
+		},
+		Worker: WorkerConfig{
+			PriceSchedulerInterval: getEnvDuration("WORKER_PRICE_SCHEDULER_INTERVAL", time.Minute),
+			InventoryValueInterval: getEnvDuration("WORKER_INVENTORY_VALUE_INTERVAL", time.Minute),
+		},
+		Snapshot: SnapshotConfig{
+			Enabled:    getEnvBool("SNAPSHOT_ENABLED", false),
+			Interval:   getEnvDuration("SNAPSHOT_INTERVAL", 24*time.Hour),
+			Retention:  getEnvInt("SNAPSHOT_RETENTION", 7),
+			Prefix:     getEnv("SNAPSHOT_PREFIX", "snapshots/"),
+			Storage:    getEnv("SNAPSHOT_STORAGE", "s3"),
+			S3Bucket:   getEnv("SNAPSHOT_S3_BUCKET", ""),
+			S3Endpoint: getEnv("SNAPSHOT_S3_ENDPOINT", ""),
+			LocalDir:   getEnv("SNAPSHOT_LOCAL_DIR", "./snapshots"),
+		},
+		Retention: RetentionConfig{
+			StockMovements: getEnvDuration("RETENTION_STOCK_MOVEMENTS", 0),
+			PriceHistory:   getEnvDuration("RETENTION_PRICE_HISTORY", 0),
+			BatchSize:      getEnvInt("RETENTION_BATCH_SIZE", 1000),
+			Interval:       getEnvDuration("RETENTION_INTERVAL", time.Hour),
+		},
+		Health: HealthConfig{
+			LivenessTimeout:  getEnvDuration("HEALTH_LIVENESS_TIMEOUT", time.Second),
+			ReadinessTimeout: getEnvDuration("HEALTH_READINESS_TIMEOUT", 5*time.Second),
+		},
+		Warmup: WarmupConfig{
+			Enabled:     getEnvBool("WARMUP_ENABLED", false),
+			Connections: getEnvInt("WARMUP_CONNECTIONS", 5),
+			Timeout:     getEnvDuration("WARMUP_TIMEOUT", 10*time.Second),
+		},
+		Admin: AdminConfig{
+			IPAllowlist: getEnvList("ADMIN_IP_ALLOWLIST", nil),
+			IPDenylist:  getEnvList("ADMIN_IP_DENYLIST", nil),
+
+			SummaryActivityWindow: getEnvDuration("ADMIN_SUMMARY_ACTIVITY_WINDOW", 24*time.Hour),
+			SummaryCacheTTL:       getEnvDuration("ADMIN_SUMMARY_CACHE_TTL", 30*time.Second),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:  getEnvBool("RATE_LIMIT_ENABLED", false),
+			Requests: getEnvInt("RATE_LIMIT_REQUESTS", 100),
+			Window:   getEnvDuration("RATE_LIMIT_WINDOW", time.Minute),
+
+			BulkDeleteRequests: getEnvInt("RATE_LIMIT_BULK_DELETE_REQUESTS", 5),
+		},
+
+		Maintenance: MaintenanceConfig{
+			Enabled:    getEnvBool("MAINTENANCE_MODE", false),
+			RetryAfter: getEnvDuration("MAINTENANCE_RETRY_AFTER", 5*time.Minute),
 		},
 	}
 
@@ -78,14 +432,264 @@ func Load() (*Config, error) {
 	if config.JWT.Secret == "your-super-secret-jwt-key" {
 		return nil, fmt.Errorf("JWT_SECRET must be set to a secure value")
 	}
+	if config.JWT.Leeway < 0 {
+		return nil, fmt.Errorf("JWT_LEEWAY must not be negative")
+	}
+	previousKeys, err := parseSigningKeys(getEnvList("JWT_PREVIOUS_KEYS", nil))
+	if err != nil {
+		return nil, fmt.Errorf("JWT_PREVIOUS_KEYS: %w", err)
+	}
+	if len(previousKeys) > 0 && config.JWT.KeyID == "" {
+		return nil, fmt.Errorf("JWT_KEY_ID must be set when JWT_PREVIOUS_KEYS is set")
+	}
+	if _, ok := previousKeys[config.JWT.KeyID]; ok {
+		return nil, fmt.Errorf("JWT_PREVIOUS_KEYS must not reuse the current JWT_KEY_ID %q", config.JWT.KeyID)
+	}
+	config.JWT.PreviousKeys = previousKeys
+	extraClaims, err := parseClaims(getEnvList("JWT_EXTRA_CLAIMS", nil))
+	if err != nil {
+		return nil, fmt.Errorf("JWT_EXTRA_CLAIMS: %w", err)
+	}
+	config.JWT.ExtraClaims = extraClaims
+	if config.Server.JSONKeyCase != "snake" && config.Server.JSONKeyCase != "camel" {
+		return nil, fmt.Errorf("JSON_KEY_CASE must be either \"snake\" or \"camel\"")
+	}
+	compressionLevel, err := parseCompressionLevel(getEnv("COMPRESSION_LEVEL", "default"))
+	if err != nil {
+		return nil, fmt.Errorf("COMPRESSION_LEVEL: %w", err)
+	}
+	config.Server.CompressionLevel = compressionLevel
+	if config.Server.TLSEnabled && (config.Server.TLSCertFile == "" || config.Server.TLSKeyFile == "") {
+		return nil, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set when TLS_ENABLED is true")
+	}
+	tlsMinVersion, err := parseTLSVersion(getEnv("TLS_MIN_VERSION", "1.2"))
+	if err != nil {
+		return nil, fmt.Errorf("TLS_MIN_VERSION: %w", err)
+	}
+	config.Server.TLSMinVersion = tlsMinVersion
+	if config.Auth.PasswordHistory < 0 {
+		return nil, fmt.Errorf("AUTH_PASSWORD_HISTORY must not be negative")
+	}
+	if config.Auth.ImpersonationTTL <= 0 {
+		return nil, fmt.Errorf("AUTH_IMPERSONATION_TTL must be positive")
+	}
+	if config.Auth.LoginUserDetail != "full" && config.Auth.LoginUserDetail != "summary" {
+		return nil, fmt.Errorf("AUTH_LOGIN_USER_DETAIL must be either \"full\" or \"summary\"")
+	}
+	if config.Database.DeleteMode != "soft" && config.Database.DeleteMode != "hard" {
+		return nil, fmt.Errorf("DB_DELETE_MODE must be either \"soft\" or \"hard\"")
+	}
+	timezone, err := time.LoadLocation(getEnv("DB_TIMEZONE", "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("DB_TIMEZONE: %w", err)
+	}
+	config.Database.Timezone = timezone
+	if config.Database.MigrationLockTimeout < 0 {
+		return nil, fmt.Errorf("DB_MIGRATION_LOCK_TIMEOUT must not be negative")
+	}
+	if config.Inventory.LabelWidth <= 0 || config.Inventory.LabelHeight <= 0 {
+		return nil, fmt.Errorf("INVENTORY_LABEL_WIDTH_MM and INVENTORY_LABEL_HEIGHT_MM must be positive")
+	}
+	if config.Log.SuccessSampleRate < 0 || config.Log.SuccessSampleRate > 1 {
+		return nil, fmt.Errorf("LOG_SUCCESS_SAMPLE_RATE must be between 0 and 1")
+	}
+	if config.Database.BreakerFailures < 0 {
+		return nil, fmt.Errorf("DB_BREAKER_FAILURES must not be negative")
+	}
+	if config.Database.BreakerFailures > 0 && config.Database.BreakerCooldown <= 0 {
+		return nil, fmt.Errorf("DB_BREAKER_COOLDOWN must be positive")
+	}
+	if config.Database.BreakerFailures > 0 && config.Database.BreakerProbes <= 0 {
+		return nil, fmt.Errorf("DB_BREAKER_PROBES must be positive")
+	}
+	if !strings.Contains(config.Inventory.SKUFormat, "{seq}") {
+		return nil, fmt.Errorf("INVENTORY_SKU_FORMAT must contain the {seq} placeholder")
+	}
+	if config.Inventory.SKUGenerationAttempts <= 0 {
+		return nil, fmt.Errorf("INVENTORY_SKU_GENERATE_ATTEMPTS must be positive")
+	}
+	if config.Worker.PriceSchedulerInterval <= 0 {
+		return nil, fmt.Errorf("WORKER_PRICE_SCHEDULER_INTERVAL must be positive")
+	}
+	if config.Worker.InventoryValueInterval <= 0 {
+		return nil, fmt.Errorf("WORKER_INVENTORY_VALUE_INTERVAL must be positive")
+	}
+	switch config.Inventory.SKUCase {
+	case "", "upper", "lower":
+	default:
+		return nil, fmt.Errorf("INVENTORY_SKU_CASE must be \"upper\", \"lower\" or empty")
+	}
+	switch config.Inventory.CategoryDeleteBehavior {
+	case "block", "reassign", "cascade":
+	default:
+		return nil, fmt.Errorf("INVENTORY_CATEGORY_DELETE_BEHAVIOR must be \"block\", \"reassign\" or \"cascade\"")
+	}
+	if config.Inventory.MaxBulkSize <= 0 {
+		return nil, fmt.Errorf("INVENTORY_MAX_BULK_SIZE must be positive")
+	}
+	// 999999999.999 is the most the DECIMAL(12,3) quantity columns hold
+	if config.Inventory.MaxQuantity <= 0 || config.Inventory.MaxQuantity > 999999999.999 {
+		return nil, fmt.Errorf("INVENTORY_MAX_QUANTITY must be positive and at most 999999999.999")
+	}
+	if config.Inventory.MinPrice < 0 {
+		return nil, fmt.Errorf("INVENTORY_MIN_PRICE must not be negative")
+	}
+	if err := validateAddresses(config.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+	}
+	if err := validateAddresses(config.Admin.IPAllowlist); err != nil {
+		return nil, fmt.Errorf("ADMIN_IP_ALLOWLIST: %w", err)
+	}
+	if err := validateAddresses(config.Admin.IPDenylist); err != nil {
+		return nil, fmt.Errorf("ADMIN_IP_DENYLIST: %w", err)
+	}
+	if config.Server.RequestTimeout < 0 {
+		return nil, fmt.Errorf("REQUEST_TIMEOUT must not be negative")
+	}
+	routeTimeouts, err := parseRouteTimeouts(getEnvList("REQUEST_TIMEOUT_ROUTES", []string{"/api/v1/inventory/items/import=2m", "/api/v1/admin/audit/export=10m"}))
+	if err != nil {
+		return nil, fmt.Errorf("REQUEST_TIMEOUT_ROUTES: %w", err)
+	}
+	config.Server.RouteTimeouts = routeTimeouts
+	if config.Health.LivenessTimeout <= 0 || config.Health.ReadinessTimeout <= 0 {
+		return nil, fmt.Errorf("HEALTH_LIVENESS_TIMEOUT and HEALTH_READINESS_TIMEOUT must be positive")
+	}
+	if config.Warmup.Enabled && (config.Warmup.Connections <= 0 || config.Warmup.Timeout <= 0) {
+		return nil, fmt.Errorf("WARMUP_CONNECTIONS and WARMUP_TIMEOUT must be positive")
+	}
+	if config.RateLimit.Enabled && (config.RateLimit.Requests <= 0 || config.RateLimit.Window <= 0) {
+		return nil, fmt.Errorf("RATE_LIMIT_REQUESTS and RATE_LIMIT_WINDOW must be positive")
+	}
+	if config.RateLimit.BulkDeleteRequests <= 0 || config.RateLimit.Window <= 0 {
+		return nil, fmt.Errorf("RATE_LIMIT_BULK_DELETE_REQUESTS and RATE_LIMIT_WINDOW must be positive")
+	}
+	if config.Admin.SummaryActivityWindow <= 0 || config.Admin.SummaryCacheTTL < 0 {
+		return nil, fmt.Errorf("ADMIN_SUMMARY_ACTIVITY_WINDOW must be positive and ADMIN_SUMMARY_CACHE_TTL not negative")
+	}
+	if config.Maintenance.RetryAfter < 0 {
+		return nil, fmt.Errorf("MAINTENANCE_RETRY_AFTER must not be negative")
+	}
+	if config.Retention.StockMovements < 0 || config.Retention.PriceHistory < 0 {
+		return nil, fmt.Errorf("RETENTION_STOCK_MOVEMENTS and RETENTION_PRICE_HISTORY must not be negative")
+	}
+	if config.Retention.BatchSize <= 0 || config.Retention.Interval <= 0 {
+		return nil, fmt.Errorf("RETENTION_BATCH_SIZE and RETENTION_INTERVAL must be positive")
+	}
+	if config.Snapshot.Enabled {
+		if config.Snapshot.Interval <= 0 {
+			return nil, fmt.Errorf("SNAPSHOT_INTERVAL must be positive")
+		}
+		if config.Snapshot.Retention < 0 {
+			return nil, fmt.Errorf("SNAPSHOT_RETENTION must not be negative")
+		}
+		switch config.Snapshot.Storage {
+		case "s3":
+			if config.Snapshot.S3Bucket == "" {
+				return nil, fmt.Errorf("SNAPSHOT_S3_BUCKET must be set when SNAPSHOT_STORAGE is \"s3\"")
+			}
+		case "local":
+		default:
+			return nil, fmt.Errorf("SNAPSHOT_STORAGE must be either \"s3\" or \"local\"")
+		}
+	}
 
 	return config, nil
 }
 
 // GetDSN returns the database connection string
 func (c *DatabaseConfig) GetDSN() string {
-	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=%s",
+		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode, c.Timezone)
+}
+
+// parseCompressionLevel parses a gzip level from 1 (fastest) to 9 (smallest), or
+// "default" for the gzip default
+func parseCompressionLevel(value string) (int, error) {
+	if value == "default" {
+		return gzip.DefaultCompression, nil
+	}
+	level, err := strconv.Atoi(value)
+	if err != nil || level < gzip.BestSpeed || level > gzip.BestCompression {
+		return 0, fmt.Errorf("must be 1 to 9 or \"default\", got %q", value)
+	}
+	return level, nil
+}
+
+// parseTLSVersion parses a minimum TLS version, "1.2" or "1.3"
+func parseTLSVersion(value string) (uint16, error) {
+	switch value {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("must be \"1.2\" or \"1.3\", got %q", value)
+}
+
+// validateAddresses checks that every entry is a CIDR range or a single IP address
+func validateAddresses(entries []string) error {
+	for _, entry := range entries {
+		if strings.Contains(entry, "/") {
+			if _, err := netip.ParsePrefix(entry); err != nil {
+				return fmt.Errorf("invalid CIDR range %q", entry)
+			}
+		} else if _, err := netip.ParseAddr(entry); err != nil {
+			return fmt.Errorf("invalid address %q", entry)
+		}
+	}
+	return nil
+}
+
+// parseRouteTimeouts parses "path=duration" entries into a map of route timeouts
+func parseRouteTimeouts(entries []string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		path, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("entry %q must have the form path=duration", entry)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("entry %q has an invalid duration", entry)
+		}
+		timeouts[strings.TrimSpace(path)] = timeout
+	}
+	return timeouts, nil
+}
+
+// parseSigningKeys parses entries of the form kid=secret into a map of secrets by key ID.
+// Secrets may contain "=" but not ",".
+func parseSigningKeys(entries []string) (map[string]string, error) {
+	keys := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		kid, secret, ok := strings.Cut(entry, "=")
+		kid = strings.TrimSpace(kid)
+		if !ok || kid == "" || secret == "" {
+			return nil, fmt.Errorf("entries must have the form kid=secret")
+		}
+		if _, ok := keys[kid]; ok {
+			return nil, fmt.Errorf("key ID %q is listed twice", kid)
+		}
+		keys[kid] = secret
+	}
+	return keys, nil
+}
+
+// parseClaims parses entries of the form name=value into a map of claim values by name
+func parseClaims(entries []string) (map[string]string, error) {
+	claims := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("entries must have the form name=value")
+		}
+		if _, ok := claims[name]; ok {
+			return nil, fmt.Errorf("claim %q is listed twice", name)
+		}
+		claims[name] = strings.TrimSpace(value)
+	}
+	return claims, nil
 }
 
 // getEnv gets an environment variable with a default value
@@ -105,3 +709,50 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvBool gets a boolean environment variable with a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvFloat gets a float environment variable with a default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDuration gets a duration environment variable (e.g. "30s", "5m") with a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durationValue, err := time.ParseDuration(value); err == nil {
+			return durationValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList gets a comma-separated environment variable as a list with a default value.
+// Items are trimmed and empty items dropped.
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
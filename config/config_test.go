@@ -0,0 +1,74 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string // a substring of the error, or empty for none
+	}{
+		{"defaults", nil, ""},
+		{"default JWT secret", map[string]string{"JWT_SECRET": "your-super-secret-jwt-key"}, "JWT_SECRET"},
+		{"bulk size at one", map[string]string{"INVENTORY_MAX_BULK_SIZE": "1"}, ""},
+		{"bulk size zero", map[string]string{"INVENTORY_MAX_BULK_SIZE": "0"}, "INVENTORY_MAX_BULK_SIZE"},
+		{"bulk size negative", map[string]string{"INVENTORY_MAX_BULK_SIZE": "-5"}, "INVENTORY_MAX_BULK_SIZE"},
+		{"quantity at the column limit", map[string]string{"INVENTORY_MAX_QUANTITY": "999999999.999"}, ""},
+		{"quantity over the column limit", map[string]string{"INVENTORY_MAX_QUANTITY": "1000000000"}, "INVENTORY_MAX_QUANTITY"},
+		{"negative minimum price", map[string]string{"INVENTORY_MIN_PRICE": "-1"}, "INVENTORY_MIN_PRICE"},
+		{"unknown delete mode", map[string]string{"DB_DELETE_MODE": "purge"}, "DB_DELETE_MODE"},
+		{"unknown SKU case", map[string]string{"INVENTORY_SKU_CASE": "title"}, "INVENTORY_SKU_CASE"},
+		{"SKU format without sequence", map[string]string{"INVENTORY_SKU_FORMAT": "{prefix}"}, "INVENTORY_SKU_FORMAT"},
+		{"unknown category delete behavior", map[string]string{"INVENTORY_CATEGORY_DELETE_BEHAVIOR": "orphan"}, "INVENTORY_CATEGORY_DELETE_BEHAVIOR"},
+		{"negative JWT leeway", map[string]string{"JWT_LEEWAY": "-1s"}, "JWT_LEEWAY"},
+		{"previous keys without a key ID", map[string]string{"JWT_PREVIOUS_KEYS": "old=secret"}, "JWT_KEY_ID"},
+		{"extra claims", map[string]string{"JWT_EXTRA_CLAIMS": "tenant=acme, region=eu"}, ""},
+		{"extra claim without a value", map[string]string{"JWT_EXTRA_CLAIMS": "tenant"}, "JWT_EXTRA_CLAIMS"},
+		{"extra claim listed twice", map[string]string{"JWT_EXTRA_CLAIMS": "tenant=a,tenant=b"}, "JWT_EXTRA_CLAIMS"},
+		{"unknown JSON key case", map[string]string{"JSON_KEY_CASE": "kebab"}, "JSON_KEY_CASE"},
+		{"TLS without files", map[string]string{"TLS_ENABLED": "true"}, "TLS_CERT_FILE"},
+		{"sample rate over one", map[string]string{"LOG_SUCCESS_SAMPLE_RATE": "1.5"}, "LOG_SUCCESS_SAMPLE_RATE"},
+		{"invalid trusted proxy", map[string]string{"TRUSTED_PROXIES": "not-an-ip"}, "TRUSTED_PROXIES"},
+		{"rate limit without a window", map[string]string{"RATE_LIMIT_ENABLED": "true", "RATE_LIMIT_WINDOW": "0s"}, "RATE_LIMIT_REQUESTS"},
+		{"retention batch size zero", map[string]string{"RETENTION_BATCH_SIZE": "0"}, "RETENTION_BATCH_SIZE"},
+		{"negative retention", map[string]string{"RETENTION_STOCK_MOVEMENTS": "-1h"}, "RETENTION_STOCK_MOVEMENTS"},
+		{"S3 snapshots without a bucket", map[string]string{"SNAPSHOT_ENABLED": "true", "SNAPSHOT_STORAGE": "s3"}, "SNAPSHOT_S3_BUCKET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_SECRET", "test-secret")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load: %v", err)
+				}
+				if cfg == nil {
+					t.Fatal("Load returned no config")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load error = %v, want one mentioning %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadMaxBulkSize(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("INVENTORY_MAX_BULK_SIZE", "250")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Inventory.MaxBulkSize != 250 {
+		t.Errorf("MaxBulkSize = %d, want 250", cfg.Inventory.MaxBulkSize)
+	}
+}
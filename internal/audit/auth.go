@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Auth event types
+const (
+	EventRegister        = "register"
+	EventLogin           = "login"
+	EventTokenValidation = "token_validation"
+	EventPasswordChange  = "password_change"
+	EventImpersonate     = "impersonate"
+)
+
+// Auth event outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// AuthEvent logs a structured security audit entry for an authentication event.
+// The client IP and user agent come from the request; reason carries the specific
+// failure cause, which is only logged and never returned to the client.
+// Passwords and tokens must never be passed to this function.
+func AuthEvent(c *gin.Context, event, username, outcome string, reason error) {
+	fields := []zap.Field{
+		zap.String("audit", "auth"),
+		zap.String("event", event),
+		zap.String("outcome", outcome),
+		zap.String("username", username),
+		zap.String("client_ip", c.ClientIP()),
+		zap.String("user_agent", c.Request.UserAgent()),
+	}
+	if userID, ok := c.Get("user_id"); ok {
+		fields = append(fields, zap.Any("user_id", userID))
+	}
+	fields = append(fields, impersonationFields(c)...)
+	if reason != nil {
+		fields = append(fields, zap.String("reason", reason.Error()))
+	}
+
+	if outcome == OutcomeFailure {
+		logger.Warn("Auth event", fields...)
+		return
+	}
+	logger.Info("Auth event", fields...)
+}
+
+// ImpersonatedRequest logs an audit entry for a request made with an impersonation token,
+// attributing it to the admin acting as the user. Reads are not recorded.
+func ImpersonatedRequest(c *gin.Context) {
+	if _, ok := c.Get("impersonator_id"); !ok || c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("audit", "impersonation"),
+		zap.String("method", c.Request.Method),
+		zap.String("path", c.Request.URL.Path),
+		zap.Int("status", c.Writer.Status()),
+		zap.String("client_ip", c.ClientIP()),
+	}
+	logger.Info("Impersonated request", append(fields, impersonationFields(c)...)...)
+}
+
+// impersonationFields describes who is acting for a request made with an impersonation
+// token, as "user 3 acting as user 7"; other requests get none
+func impersonationFields(c *gin.Context) []zap.Field {
+	actorID, ok := c.Get("impersonator_id")
+	if !ok {
+		return nil
+	}
+	return []zap.Field{
+		zap.Any("impersonator_id", actorID),
+		zap.String("actor", fmt.Sprintf("user %v acting as user %v", actorID, c.GetUint("user_id"))),
+	}
+}
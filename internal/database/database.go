@@ -2,11 +2,13 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/nielwyn/inventory-system/internal/models"
 	"github.com/nielwyn/inventory-system/pkg/logger"
+	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
@@ -17,13 +19,14 @@ type Database struct {
 	DB *gorm.DB
 }
 
-// New creates a new database connection
-func New(dsn string) (*Database, error) {
+// New creates a new database connection. Timestamps GORM sets, such as created_at
+// and updated_at, are taken in location.
+func New(dsn string, location *time.Location) (*Database, error) {
 	// Configure GORM logger
 	gormConfig := &gorm.Config{
 		Logger: gormLogger.Default.LogMode(gormLogger.Silent),
 		NowFunc: func() time.Time {
-			return time.Now().UTC()
+			return time.Now().In(location)
 		},
 	}
 
@@ -58,22 +61,120 @@ func (d *Database) Close() error {
 	return sqlDB.Close()
 }
 
-// AutoMigrate runs auto migration for the database models
-func (d *Database) AutoMigrate() error {
+// migrationLockName identifies the advisory lock held while migrating
+const migrationLockName = "inventory-system:migrations"
+
+// AutoMigrate runs auto migration for the database models. Replicas starting together
+// take turns: each holds a Postgres advisory lock while it migrates, and the others wait
+// for it. Waiting longer than lockTimeout is an error; 0 waits indefinitely.
+func (d *Database) AutoMigrate(lockTimeout time.Duration) error {
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database instance: %w", err)
+	}
+
+	// Session-level advisory locks belong to a connection, so pin one for the lock.
+	// If the process dies the connection closes and Postgres releases the lock.
+	ctx := context.Background()
+	lockConn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for migration lock: %w", err)
+	}
+	defer lockConn.Close()
+
+	lockCtx := ctx
+	if lockTimeout > 0 {
+		var cancel context.CancelFunc
+		lockCtx, cancel = context.WithTimeout(ctx, lockTimeout)
+		defer cancel()
+	}
+	logger.Info("Waiting for migration lock")
+	if _, err := lockConn.ExecContext(lockCtx, "SELECT pg_advisory_lock(hashtext($1))", migrationLockName); err != nil {
+		if lockCtx.Err() != nil {
+			return fmt.Errorf("timed out after %s waiting for migration lock", lockTimeout)
+		}
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := lockConn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", migrationLockName); err != nil {
+			logger.Warn("Failed to release migration lock", zap.Error(err))
+		}
+	}()
+
+	return d.migrate()
+}
+
+// migrate creates and updates the schema for the database models
+func (d *Database) migrate() error {
 	logger.Info("Running database migrations")
 
 	err := d.DB.AutoMigrate(
 		&models.User{},
+		&models.PasswordHistory{},
+		&models.Session{},
 		&models.Item{},
+		&models.StockMovement{},
+		&models.PriceHistory{},
+		&models.ScheduledPrice{},
+		&models.UserPreferences{},
+		&models.Tag{},
+		&models.ItemTag{},
+		&models.Location{},
+		&models.StockLevel{},
+		&models.PurchaseOrder{},
+		&models.PurchaseOrderLine{},
+		&models.Bundle{},
+		&models.BundleLine{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	// Sequence backing generated SKUs
+	if err := d.DB.Exec("CREATE SEQUENCE IF NOT EXISTS " + models.ItemSKUSequence).Error; err != nil {
+		return fmt.Errorf("failed to create SKU sequence: %w", err)
+	}
+
+	// SKU uniqueness only applies to active items (see idx_items_sku_active), so
+	// drop the legacy global index that kept soft-deleted SKUs reserved
+	if d.DB.Migrator().HasIndex(&models.Item{}, "idx_items_sku") {
+		if err := d.DB.Migrator().DropIndex(&models.Item{}, "idx_items_sku"); err != nil {
+			return fmt.Errorf("failed to drop legacy SKU index: %w", err)
+		}
+	}
+
 	logger.Info("Database migrations completed successfully")
 	return nil
 }
 
+// Warm opens up to n connections to the database at once and returns them to the pool,
+// so the first requests do not pay for connecting. The pool keeps at most 10 idle
+// connections, so larger values only test that the database accepts them.
+func (d *Database) Warm(ctx context.Context, n int) error {
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database instance: %w", err)
+	}
+
+	conns := make([]*sql.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	for i := 0; i < n; i++ {
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to open connection %d: %w", i+1, err)
+		}
+		conns = append(conns, conn)
+		if err := conn.PingContext(ctx); err != nil {
+			return fmt.Errorf("failed to ping connection %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
 // Ping checks if the database connection is alive
 func (d *Database) Ping() error {
 	sqlDB, err := d.DB.DB()
@@ -83,11 +184,8 @@ func (d *Database) Ping() error {
 	return sqlDB.Ping()
 }
 
-// Health checks the database health
-func (d *Database) Health() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
+// Health checks the database health, giving up when ctx is done
+func (d *Database) Health(ctx context.Context) error {
 	sqlDB, err := d.DB.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get database instance: %w", err)
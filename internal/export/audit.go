@@ -0,0 +1,116 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+// AuditWriter writes audit records in an export format, one at a time.
+// Close finishes the document.
+type AuditWriter interface {
+	Write(record *models.AuditRecord) error
+	Close() error
+}
+
+// NewAuditWriter creates a writer for format on w. Only JSON and CSV are supported,
+// as both can be streamed.
+func NewAuditWriter(format string, w io.Writer) (AuditWriter, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonAuditWriter{w: w, encoder: json.NewEncoder(w)}, nil
+	case FormatCSV:
+		return newCSVAuditWriter(w)
+	default:
+		return nil, fmt.Errorf("unknown audit export format %q", format)
+	}
+}
+
+// auditColumns are the exported audit column headers
+var auditColumns = []string{"id", "created_at", "item_id", "sku", "type", "delta", "quantity_after", "reason", "user_id", "username", "impersonator_id"}
+
+// jsonAuditWriter streams audit records as a JSON array
+type jsonAuditWriter struct {
+	w       io.Writer
+	encoder *json.Encoder
+	started bool
+}
+
+func (j *jsonAuditWriter) Write(record *models.AuditRecord) error {
+	sep := ","
+	if !j.started {
+		sep = "["
+		j.started = true
+	}
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	return j.encoder.Encode(record)
+}
+
+func (j *jsonAuditWriter) Close() error {
+	end := "]"
+	if !j.started {
+		end = "[]"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+// csvAuditWriter streams audit records as CSV rows under a header row
+type csvAuditWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+// csvAuditFlushRows is how many rows are buffered before they are flushed to the client
+const csvAuditFlushRows = 500
+
+func newCSVAuditWriter(w io.Writer) (*csvAuditWriter, error) {
+	c := &csvAuditWriter{w: csv.NewWriter(w)}
+	if err := c.w.Write(auditColumns); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *csvAuditWriter) Write(record *models.AuditRecord) error {
+	err := c.w.Write([]string{
+		strconv.FormatUint(uint64(record.ID), 10),
+		record.CreatedAt.Format(time.RFC3339),
+		strconv.FormatUint(uint64(record.ItemID), 10),
+		record.SKU,
+		record.Type,
+		strconv.FormatFloat(record.Delta, 'f', -1, 64),
+		strconv.FormatFloat(record.QuantityAfter, 'f', -1, 64),
+		record.Reason,
+		strconv.FormatUint(uint64(record.UserID), 10),
+		record.Username,
+		formatOptionalID(record.ImpersonatorID),
+	})
+	if err != nil {
+		return err
+	}
+	if c.rows++; c.rows%csvAuditFlushRows == 0 {
+		c.w.Flush()
+	}
+	return c.w.Error()
+}
+
+func (c *csvAuditWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// formatOptionalID formats id, or an empty cell when there is none
+func formatOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
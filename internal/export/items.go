@@ -0,0 +1,252 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+// Export formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ContentTypes maps each export format to its MIME type
+var ContentTypes = map[string]string{
+	FormatJSON: "application/json",
+	FormatCSV:  "text/csv; charset=utf-8",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// ItemWriter writes items in an export format.
+// Write may be called any number of times; Close finishes the document.
+type ItemWriter interface {
+	Write(items []models.Item) error
+	Close() error
+}
+
+// NewItemWriter creates a writer for format on w. Cost prices are only
+// included when includeCost is set.
+func NewItemWriter(format string, w io.Writer, includeCost bool) (ItemWriter, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonItemWriter{w: w, encoder: json.NewEncoder(w), includeCost: includeCost}, nil
+	case FormatCSV:
+		return newCSVItemWriter(w, includeCost)
+	case FormatXLSX:
+		return newXLSXItemWriter(w, includeCost)
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// columns returns the exported column headers
+func columns(includeCost bool) []string {
+	cols := []string{"id", "sku", "name", "description", "category", "quantity", "unit", "price"}
+	if includeCost {
+		cols = append(cols, "cost_price")
+	}
+	return append(cols, "created_at", "updated_at")
+}
+
+// jsonItemWriter streams items as a JSON array
+type jsonItemWriter struct {
+	w           io.Writer
+	encoder     *json.Encoder
+	includeCost bool
+	started     bool
+}
+
+func (j *jsonItemWriter) Write(items []models.Item) error {
+	for i := range items {
+		sep := ","
+		if !j.started {
+			sep = "["
+			j.started = true
+		}
+		if _, err := io.WriteString(j.w, sep); err != nil {
+			return err
+		}
+
+		var err error
+		if j.includeCost {
+			err = j.encoder.Encode(items[i].WithCost())
+		} else {
+			err = j.encoder.Encode(&items[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *jsonItemWriter) Close() error {
+	end := "]"
+	if !j.started {
+		end = "[]"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+// csvItemWriter streams items as CSV rows under a header row
+type csvItemWriter struct {
+	w           *csv.Writer
+	includeCost bool
+}
+
+func newCSVItemWriter(w io.Writer, includeCost bool) (*csvItemWriter, error) {
+	c := &csvItemWriter{w: csv.NewWriter(w), includeCost: includeCost}
+	if err := c.w.Write(columns(includeCost)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *csvItemWriter) Write(items []models.Item) error {
+	for _, item := range items {
+		row := []string{
+			strconv.FormatUint(uint64(item.ID), 10),
+			item.SKU,
+			item.Name,
+			item.Description,
+			item.Category,
+			strconv.FormatFloat(item.Quantity, 'f', -1, 64),
+			item.Unit,
+			strconv.FormatFloat(item.Price, 'f', 2, 64),
+		}
+		if c.includeCost {
+			row = append(row, strconv.FormatFloat(item.CostPrice, 'f', 2, 64))
+		}
+		row = append(row, item.CreatedAt.Format(time.RFC3339), item.UpdatedAt.Format(time.RFC3339))
+		if err := c.w.Write(row); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvItemWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// xlsxItemWriter builds a single-sheet workbook in memory and writes it on Close
+type xlsxItemWriter struct {
+	w           io.Writer
+	file        *excelize.File
+	stream      *excelize.StreamWriter
+	includeCost bool
+	row         int
+
+	priceStyle    int
+	quantityStyle int
+	dateStyle     int
+}
+
+// xlsxSheet is the name of the exported worksheet
+const xlsxSheet = "Items"
+
+func newXLSXItemWriter(w io.Writer, includeCost bool) (*xlsxItemWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", xlsxSheet); err != nil {
+		return nil, err
+	}
+
+	x := &xlsxItemWriter{w: w, file: file, includeCost: includeCost, row: 1}
+	styles := []struct {
+		id    *int
+		style *excelize.Style
+	}{
+		{&x.priceStyle, &excelize.Style{NumFmt: 4}}, // #,##0.00
+		{&x.quantityStyle, &excelize.Style{CustomNumFmt: ptr("#,##0.###")}},
+		{&x.dateStyle, &excelize.Style{CustomNumFmt: ptr("yyyy-mm-dd hh:mm:ss")}},
+	}
+	for _, s := range styles {
+		id, err := file.NewStyle(s.style)
+		if err != nil {
+			return nil, err
+		}
+		*s.id = id
+	}
+	headerStyle, err := file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"4472C4"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	x.stream, err = file.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		return nil, err
+	}
+	if err := x.stream.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return nil, err
+	}
+
+	cols := columns(includeCost)
+	header := make([]interface{}, len(cols))
+	for i, col := range cols {
+		header[i] = excelize.Cell{StyleID: headerStyle, Value: col}
+	}
+	if err := x.stream.SetRow("A1", header); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *xlsxItemWriter) Write(items []models.Item) error {
+	for _, item := range items {
+		x.row++
+		row := []interface{}{
+			item.ID,
+			item.SKU,
+			item.Name,
+			item.Description,
+			item.Category,
+			excelize.Cell{StyleID: x.quantityStyle, Value: item.Quantity},
+			item.Unit,
+			excelize.Cell{StyleID: x.priceStyle, Value: item.Price},
+		}
+		if x.includeCost {
+			row = append(row, excelize.Cell{StyleID: x.priceStyle, Value: item.CostPrice})
+		}
+		row = append(row,
+			excelize.Cell{StyleID: x.dateStyle, Value: item.CreatedAt},
+			excelize.Cell{StyleID: x.dateStyle, Value: item.UpdatedAt},
+		)
+
+		cell, err := excelize.CoordinatesToCellName(1, x.row)
+		if err != nil {
+			return err
+		}
+		if err := x.stream.SetRow(cell, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *xlsxItemWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.w)
+	return err
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+// ContentTypePDF is the MIME type of item labels
+const ContentTypePDF = "application/pdf"
+
+// LabelSize is the page size of an item label in millimetres
+type LabelSize struct {
+	Width  float64
+	Height float64
+}
+
+// labelQuietZone is the blank margin, in modules, Code 128 requires either side of a barcode
+const labelQuietZone = 10
+
+// WriteItemLabel writes a one-page PDF label for item to w: its name, SKU and price
+// above a Code 128 barcode of the SKU. Text and barcode are scaled to the label height.
+// It returns ErrUnencodable, and writes nothing, when the SKU cannot be put in a barcode.
+func WriteItemLabel(w io.Writer, item *models.Item, size LabelSize) error {
+	bars, err := code128(item.SKU)
+	if err != nil {
+		return err
+	}
+
+	margin := size.Height * 0.06
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		UnitStr: "mm",
+		Size:    fpdf.SizeType{Wd: size.Width, Ht: size.Height},
+	})
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	// Font sizes are in points; a point is about 0.35mm
+	lineHeight := size.Height * 0.16
+	contentWidth := size.Width - 2*margin
+
+	pdf.SetFont("Helvetica", "B", lineHeight/0.35*0.8)
+	pdf.CellFormat(contentWidth, lineHeight, fitText(pdf, tr(item.Name), contentWidth), "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", lineHeight/0.35*0.6)
+	pdf.CellFormat(contentWidth/2, lineHeight, tr("SKU: "+item.SKU), "", 0, "L", false, 0, "")
+	pdf.CellFormat(contentWidth/2, lineHeight, fmt.Sprintf("Price: %.2f", item.Price), "", 1, "R", false, 0, "")
+
+	// The barcode fills the rest of the label above a line of human-readable text
+	textHeight := lineHeight * 0.7
+	top := pdf.GetY() + margin/2
+	barHeight := size.Height - margin - textHeight - top
+	modules := 0
+	for _, width := range bars {
+		modules += int(width - '0')
+	}
+	module := contentWidth / float64(modules+2*labelQuietZone)
+	x := margin + labelQuietZone*module
+	for i, width := range bars {
+		barWidth := float64(width-'0') * module
+		if i%2 == 0 {
+			pdf.Rect(x, top, barWidth, barHeight, "F")
+		}
+		x += barWidth
+	}
+
+	pdf.SetXY(margin, top+barHeight)
+	pdf.SetFont("Courier", "", textHeight/0.35*0.8)
+	pdf.CellFormat(contentWidth, textHeight, item.SKU, "", 0, "C", false, 0, "")
+
+	return pdf.Output(w)
+}
+
+// fitText shortens text with an ellipsis until it fits in width at the current font.
+// Text has already been translated to the font's single-byte code page, so it is cut by byte.
+func fitText(pdf *fpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	for len(text) > 0 && pdf.GetStringWidth(text+"...") > width {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
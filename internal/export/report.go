@@ -0,0 +1,83 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+// FormatOpenMetrics is the OpenMetrics text exposition format
+const FormatOpenMetrics = "openmetrics"
+
+// ContentTypeOpenMetrics is the MIME type of the OpenMetrics text format
+const ContentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// labelEscaper escapes a label value as the OpenMetrics text format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteReportOpenMetrics writes report to w as gauges in the OpenMetrics text format.
+// The per-category and low-stock figures are labelled series; the samples carry no
+// timestamp, the generated_at gauge says when they were computed.
+func WriteReportOpenMetrics(w io.Writer, report *models.BusinessReport) error {
+	bw := bufio.NewWriter(w)
+	gauge := func(name, help string) {
+		fmt.Fprintf(bw, "# TYPE %s gauge\n# HELP %s %s\n", name, name, help)
+	}
+	sample := func(name string, value float64, labels ...string) {
+		bw.WriteString(name)
+		if len(labels) > 0 {
+			bw.WriteByte('{')
+			for i := 0; i < len(labels); i += 2 {
+				if i > 0 {
+					bw.WriteByte(',')
+				}
+				fmt.Fprintf(bw, `%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1]))
+			}
+			bw.WriteByte('}')
+		}
+		fmt.Fprintf(bw, " %s\n", strconv.FormatFloat(value, 'g', -1, 64))
+	}
+
+	gauge("inventory_report_generated_at_seconds", "When the report was computed, as a Unix time.")
+	sample("inventory_report_generated_at_seconds", float64(report.GeneratedAt.UnixMilli())/1000)
+	gauge("inventory_report_items", "Number of active items.")
+	sample("inventory_report_items", float64(report.TotalItems))
+	gauge("inventory_report_skus", "Number of distinct SKUs of active items.")
+	sample("inventory_report_skus", float64(report.TotalSKUs))
+	gauge("inventory_report_quantity", "Total quantity in stock by unit of measure.")
+	for _, quantity := range report.Quantities {
+		sample("inventory_report_quantity", quantity.Quantity, "unit", quantity.Unit)
+	}
+	gauge("inventory_report_value", "Total value of stock, the sum of price times quantity.")
+	sample("inventory_report_value", report.InventoryValue)
+
+	gauge("inventory_report_category_items", "Number of active items by category and unit.")
+	for _, category := range report.Categories {
+		sample("inventory_report_category_items", float64(category.Items), "category", category.Category, "unit", category.Unit)
+	}
+	gauge("inventory_report_category_quantity", "Quantity in stock by category and unit.")
+	for _, category := range report.Categories {
+		sample("inventory_report_category_quantity", category.Quantity, "category", category.Category, "unit", category.Unit)
+	}
+	gauge("inventory_report_category_value", "Value of stock by category and unit.")
+	for _, category := range report.Categories {
+		sample("inventory_report_category_value", category.InventoryValue, "category", category.Category, "unit", category.Unit)
+	}
+
+	gauge("inventory_report_low_stock_threshold", "Quantity at or below which an item is low on stock.")
+	sample("inventory_report_low_stock_threshold", report.LowStockThreshold)
+	gauge("inventory_report_low_stock_items", "Number of active items low on stock.")
+	sample("inventory_report_low_stock_items", float64(report.LowStockCount))
+	gauge("inventory_report_low_stock_quantity", "Quantity in stock of each item low on stock.")
+	for _, item := range report.LowStock {
+		sample("inventory_report_low_stock_quantity", item.Quantity,
+			"id", strconv.FormatUint(uint64(item.ID), 10), "sku", item.SKU, "name", item.Name, "category", item.Category, "unit", item.Unit)
+	}
+
+	bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
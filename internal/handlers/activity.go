@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/export"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// ActivityHandler handles user activity endpoints
+type ActivityHandler struct {
+	activityService    service.ActivityService
+	preferencesService service.PreferencesService
+}
+
+// NewActivityHandler creates a new activity handler
+func NewActivityHandler(activityService service.ActivityService, preferencesService service.PreferencesService) *ActivityHandler {
+	return &ActivityHandler{activityService: activityService, preferencesService: preferencesService}
+}
+
+// GetMyActivity handles retrieving a page of the authenticated user's recent changes.
+// The page size defaults to the user's preference.
+func (h *ActivityHandler) GetMyActivity(c *gin.Context) {
+	var query models.ActivityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+	if err := applyPreferences(c, h.preferencesService, &query.PageSize, nil); err != nil {
+		respondError(c, err, "Failed to retrieve activity")
+		return
+	}
+	query.Normalize()
+
+	activity, err := h.activityService.GetUserActivity(c.Request.Context(), c.GetUint("user_id"), &query)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve activity")
+		return
+	}
+
+	response.SuccessWithMeta(c, http.StatusOK, "Activity retrieved successfully", activity, response.Pagination{
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	})
+}
+
+// ExportAudit handles downloading every stock movement in a date range as CSV or JSON,
+// for compliance requests. Records are streamed as they are read.
+func (h *ActivityHandler) ExportAudit(c *gin.Context) {
+	var query models.AuditExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+	if query.Format == "" {
+		query.Format = export.FormatCSV
+	}
+
+	// The writer is created on the first record so that errors raised before any
+	// record is read can still be answered with a normal error response
+	var writer export.AuditWriter
+	start := func() error {
+		c.Header("Content-Type", export.ContentTypes[query.Format])
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="audit_%s_%s.%s"`,
+			query.From.Format("2006-01-02"), query.To.Format("2006-01-02"), query.Format))
+		var err error
+		writer, err = export.NewAuditWriter(query.Format, c.Writer)
+		return err
+	}
+
+	err := h.activityService.ExportAudit(c.Request.Context(), &query, func(record *models.AuditRecord) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return writer.Write(record)
+	})
+	if err == nil && writer == nil {
+		err = start()
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		if writer == nil {
+			respondError(c, err, "Failed to export audit log")
+			return
+		}
+		// Part of the body has been sent, so the status can no longer change
+		if contextErrorStatus(c.Request.Context(), err) != StatusClientClosedRequest {
+			logger.Error("Failed to export audit log", zap.Error(err))
+		}
+		c.Abort()
+	}
+}
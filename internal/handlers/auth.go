@@ -2,14 +2,14 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/audit"
 	"github.com/nielwyn/inventory-system/internal/models"
 	"github.com/nielwyn/inventory-system/internal/service"
-	"github.com/nielwyn/inventory-system/pkg/logger"
 	"github.com/nielwyn/inventory-system/pkg/response"
 	"github.com/nielwyn/inventory-system/pkg/validator"
-	"go.uber.org/zap"
 )
 
 // AuthHandler handles authentication endpoints
@@ -30,12 +30,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := h.authService.Register(&req)
+	user, err := h.authService.Register(c.Request.Context(), &req)
 	if err != nil {
-		logger.Error("Registration failed", zap.Error(err))
-		response.Error(c, http.StatusBadRequest, err.Error())
+		audit.AuthEvent(c, audit.EventRegister, req.Username, audit.OutcomeFailure, err)
+		respondError(c, err, "Failed to register user")
 		return
 	}
+	audit.AuthEvent(c, audit.EventRegister, user.Username, audit.OutcomeSuccess, nil)
 
 	response.Success(c, http.StatusCreated, "User registered successfully", gin.H{
 		"user": user,
@@ -50,12 +51,90 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	loginResponse, err := h.authService.Login(&req)
+	client := models.SessionClient{UserAgent: c.Request.UserAgent(), IPAddress: c.ClientIP()}
+	loginResponse, err := h.authService.Login(c.Request.Context(), &req, client)
 	if err != nil {
-		logger.Error("Login failed", zap.Error(err))
-		response.Error(c, http.StatusUnauthorized, err.Error())
+		audit.AuthEvent(c, audit.EventLogin, req.Username, audit.OutcomeFailure, err)
+		respondError(c, err, "Failed to log in")
 		return
 	}
+	audit.AuthEvent(c, audit.EventLogin, req.Username, audit.OutcomeSuccess, nil)
 
 	response.Success(c, http.StatusOK, "Login successful", loginResponse)
 }
+
+// ChangePassword handles the caller changing their own password
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	if err := h.authService.ChangePassword(c.Request.Context(), c.GetUint("user_id"), &req); err != nil {
+		audit.AuthEvent(c, audit.EventPasswordChange, "", audit.OutcomeFailure, err)
+		respondError(c, err, "Failed to change password")
+		return
+	}
+	audit.AuthEvent(c, audit.EventPasswordChange, "", audit.OutcomeSuccess, nil)
+
+	response.Success(c, http.StatusOK, "Password changed successfully", nil)
+}
+
+// ListSessions handles listing the caller's active sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.authService.ListSessions(c.Request.Context(), c.GetUint("user_id"), c.GetString("session_id"))
+	if err != nil {
+		respondError(c, err, "Failed to list sessions")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// RevokeSession handles the caller revoking one of their sessions, such as one on a lost device
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	if err := h.authService.RevokeSession(c.Request.Context(), c.GetUint("user_id"), c.Param("id")); err != nil {
+		respondError(c, err, "Failed to revoke session")
+		return
+	}
+
+	response.Deleted(c, "Session revoked successfully", nil)
+}
+
+// Introspect handles checking a token on behalf of another service. Invalid and
+// expired tokens are answered with 200 and "active": false.
+func (h *AuthHandler) Introspect(c *gin.Context) {
+	var req models.IntrospectRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	result, err := h.authService.Introspect(c.Request.Context(), req.Token)
+	if err != nil {
+		respondError(c, err, "Failed to introspect token")
+		return
+	}
+	response.Success(c, http.StatusOK, "Token introspected successfully", result)
+}
+
+// Impersonate handles issuing an admin a short-lived token to act as another user
+func (h *AuthHandler) Impersonate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	client := models.SessionClient{UserAgent: c.Request.UserAgent(), IPAddress: c.ClientIP()}
+	result, err := h.authService.Impersonate(c.Request.Context(), c.GetUint("user_id"), uint(id), client)
+	if err != nil {
+		audit.AuthEvent(c, audit.EventImpersonate, c.Param("id"), audit.OutcomeFailure, err)
+		respondError(c, err, "Failed to impersonate user")
+		return
+	}
+	audit.AuthEvent(c, audit.EventImpersonate, result.User.Username, audit.OutcomeSuccess, nil)
+
+	response.Success(c, http.StatusOK, "Impersonation token issued successfully", result)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+)
+
+// BundleHandler handles bundle endpoints
+type BundleHandler struct {
+	bundleService service.BundleService
+}
+
+// NewBundleHandler creates a new bundle handler
+func NewBundleHandler(bundleService service.BundleService) *BundleHandler {
+	return &BundleHandler{bundleService: bundleService}
+}
+
+// CreateBundle handles defining a bundle
+func (h *BundleHandler) CreateBundle(c *gin.Context) {
+	var req models.CreateBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	bundle, err := h.bundleService.CreateBundle(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to create bundle")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Bundle created successfully", bundle)
+}
+
+// ListBundles handles listing bundles
+func (h *BundleHandler) ListBundles(c *gin.Context) {
+	bundles, err := h.bundleService.ListBundles(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve bundles")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Bundles retrieved successfully", bundles)
+}
+
+// GetBundle handles retrieving a bundle
+func (h *BundleHandler) GetBundle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid bundle ID")
+		return
+	}
+
+	bundle, err := h.bundleService.GetBundle(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err, "Failed to retrieve bundle")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Bundle retrieved successfully", bundle)
+}
+
+// Consume handles taking the components of assembled or sold bundles out of stock
+func (h *BundleHandler) Consume(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid bundle ID")
+		return
+	}
+
+	var req models.ConsumeBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	bundle, err := h.bundleService.Consume(c.Request.Context(), uint(id), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to consume bundle")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Bundle stock consumed successfully", bundle)
+}
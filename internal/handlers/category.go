@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"go.uber.org/zap"
+)
+
+// CategoryHandler handles category endpoints
+type CategoryHandler struct {
+	categoryService service.CategoryService
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+// DeleteCategory handles deleting a category and dealing with its items
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	result, err := h.categoryService.DeleteCategory(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondError(c, err, "Failed to delete category")
+		return
+	}
+
+	logger.Info("Category deleted",
+		zap.String("category", result.Category),
+		zap.String("behavior", result.Behavior),
+		zap.Int64("items_affected", result.ItemsAffected),
+		zap.Uint("user_id", c.GetUint("user_id")),
+	)
+	response.Deleted(c, "Category deleted successfully", result)
+}
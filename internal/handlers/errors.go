@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"go.uber.org/zap"
+)
+
+// StatusClientClosedRequest is the non-standard status, borrowed from nginx, recorded
+// for requests the client abandoned before a response was written
+const StatusClientClosedRequest = 499
+
+// detailedError is implemented by errors that carry structured details for the client
+type detailedError interface {
+	Details() interface{}
+}
+
+// respondError maps a service error to its HTTP status and writes the error response.
+// Unknown errors are logged and reported as a generic 500 with fallbackMessage.
+func respondError(c *gin.Context, err error, fallbackMessage string) {
+	switch contextErrorStatus(c.Request.Context(), err) {
+	case StatusClientClosedRequest:
+		// The client is gone, so this is only for the access log and metrics
+		response.ErrorWithCode(c, StatusClientClosedRequest, "client_closed_request", "Client closed request")
+		return
+	case http.StatusServiceUnavailable:
+		response.ErrorWithCode(c, http.StatusServiceUnavailable, "timeout", "Request timed out")
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrInvalidCredentials):
+		// The wrapped reason is for the audit log only
+		response.Error(c, http.StatusUnauthorized, service.ErrInvalidCredentials.Error())
+	case errors.Is(err, service.ErrItemNotFound):
+		respondWithDetails(c, err, http.StatusNotFound, "item_not_found")
+	case errors.Is(err, service.ErrScheduledPriceNotFound):
+		respondWithDetails(c, err, http.StatusNotFound, "scheduled_price_not_found")
+	case errors.Is(err, service.ErrPreconditionFailed):
+		response.ErrorWithCode(c, http.StatusPreconditionFailed, "precondition_failed", err.Error())
+	case errors.Is(err, service.ErrCategoryNotFound):
+		respondWithDetails(c, err, http.StatusNotFound, "category_not_found")
+	case errors.Is(err, service.ErrLocationNotFound):
+		respondWithDetails(c, err, http.StatusNotFound, "location_not_found")
+	case errors.Is(err, service.ErrPurchaseOrderNotFound):
+		respondWithDetails(c, err, http.StatusNotFound, "purchase_order_not_found")
+	case errors.Is(err, service.ErrBundleNotFound):
+		respondWithDetails(c, err, http.StatusNotFound, "bundle_not_found")
+	case errors.Is(err, service.ErrUserNotFound):
+		respondWithDetails(c, err, http.StatusNotFound, "user_not_found")
+	case errors.Is(err, service.ErrSessionNotFound):
+		respondWithDetails(c, err, http.StatusNotFound, "session_not_found")
+	case errors.Is(err, service.ErrTagNotFound):
+		respondWithDetails(c, err, http.StatusNotFound, "tag_not_found")
+	case errors.Is(err, service.ErrCategoryNotEmpty):
+		respondWithDetails(c, err, http.StatusConflict, "category_not_empty")
+	case errors.Is(err, service.ErrSKUExists):
+		response.ErrorWithCode(c, http.StatusConflict, "sku_exists", err.Error())
+	case errors.Is(err, service.ErrSKUGeneration):
+		response.ErrorWithCode(c, http.StatusConflict, "sku_generation_failed", err.Error())
+	case errors.Is(err, service.ErrInsufficientStock):
+		response.ErrorWithCode(c, http.StatusConflict, "insufficient_stock", err.Error())
+	case errors.Is(err, service.ErrOverReceipt):
+		respondWithDetails(c, err, http.StatusConflict, "over_receipt")
+	case errors.Is(err, service.ErrUsernameExists):
+		response.ErrorWithCode(c, http.StatusConflict, "username_taken", err.Error())
+	case errors.Is(err, service.ErrEmailExists):
+		response.ErrorWithCode(c, http.StatusConflict, "email_taken", err.Error())
+	case errors.Is(err, service.ErrImpersonationForbidden):
+		response.ErrorWithCode(c, http.StatusForbidden, "impersonation_forbidden", "Admins and your own account cannot be impersonated")
+	case errors.Is(err, service.ErrPasswordReused):
+		response.ErrorWithCode(c, http.StatusBadRequest, "password_reused", "The new password must differ from recently used passwords")
+	case errors.Is(err, service.ErrValidation):
+		respondWithDetails(c, err, http.StatusBadRequest, "validation_failed")
+	case errors.Is(err, service.ErrDatabaseUnavailable):
+		response.ErrorWithCode(c, http.StatusServiceUnavailable, "database_unavailable", "Database is unavailable; try again later")
+	default:
+		logger.Error(fallbackMessage, zap.Error(err))
+		response.Error(c, http.StatusInternalServerError, fallbackMessage)
+	}
+}
+
+// respondWithDetails writes an error response with code, including the error's
+// structured details when it carries any
+func respondWithDetails(c *gin.Context, err error, status int, code string) {
+	var detailed detailedError
+	if errors.As(err, &detailed) {
+		response.ErrorWithDetails(c, status, code, err.Error(), detailed.Details())
+		return
+	}
+	response.ErrorWithCode(c, status, code, err.Error())
+}
+
+// contextErrorStatus classifies err by whether it was caused by the request context ending.
+// It returns StatusClientClosedRequest when the client went away, 503 when the request ran
+// out of time, and 0 for any other error. Drivers do not always wrap the context error, so
+// an error returned after ctx has ended is attributed to ctx as well.
+func contextErrorStatus(ctx context.Context, err error) int {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable
+	case errors.Is(ctx.Err(), context.Canceled):
+		return StatusClientClosedRequest
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return http.StatusServiceUnavailable
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/service"
+)
+
+func TestRespondErrorStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"item not found", service.ErrItemNotFound, http.StatusNotFound, "item_not_found"},
+		{"SKU exists", service.ErrSKUExists, http.StatusConflict, "sku_exists"},
+		{"wrapped SKU exists", fmt.Errorf("%w: 'W-1'", service.ErrSKUExists), http.StatusConflict, "sku_exists"},
+		{"validation", &service.ValidationError{Message: "Field 'Price' must be at least 1.00"}, http.StatusBadRequest, "validation_failed"},
+		{"username taken", service.ErrUsernameExists, http.StatusConflict, "username_taken"},
+		{"email taken", service.ErrEmailExists, http.StatusConflict, "email_taken"},
+		{"unknown", fmt.Errorf("connection reset"), http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) { respondError(c, tt.err, "Failed") })
+
+			w, resp := doRequest(t, router, http.MethodGet, "/", "")
+			if w.Code != tt.wantStatus || resp.Code != tt.wantCode {
+				t.Errorf("status, code = %d, %q; want %d, %q", w.Code, resp.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+// notFoundError stands in for the service's not-found errors, which carry details
+type notFoundError struct {
+	details map[string]interface{}
+}
+
+func (e *notFoundError) Error() string        { return service.ErrItemNotFound.Error() }
+func (e *notFoundError) Unwrap() error        { return service.ErrItemNotFound }
+func (e *notFoundError) Details() interface{} { return e.details }
+
+func TestRespondErrorNotFoundDetails(t *testing.T) {
+	router := gin.New()
+	router.GET("/items/:id", func(c *gin.Context) {
+		respondError(c, &notFoundError{details: map[string]interface{}{"resource": "item", "id": 42}}, "Failed")
+	})
+
+	w, resp := doRequest(t, router, http.MethodGet, "/items/42", "")
+	if w.Code != http.StatusNotFound || resp.Code != "item_not_found" {
+		t.Fatalf("status, code = %d, %q; want 404, item_not_found", w.Code, resp.Code)
+	}
+	var details struct {
+		Resource string `json:"resource"`
+		ID       uint   `json:"id"`
+	}
+	if err := json.Unmarshal(resp.Details, &details); err != nil || details.Resource != "item" || details.ID != 42 {
+		t.Errorf("details = %s, want the resource and the requested ID", resp.Details)
+	}
+}
@@ -1,40 +1,88 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/nielwyn/inventory-system/internal/database"
+	"github.com/nielwyn/inventory-system/internal/repository"
 	"github.com/nielwyn/inventory-system/pkg/response"
 )
 
+// HealthOptions holds how long each health check waits for the database
+type HealthOptions struct {
+	LivenessTimeout  time.Duration
+	ReadinessTimeout time.Duration
+}
+
 // HealthHandler handles health check endpoints
 type HealthHandler struct {
-	db *database.Database
+	db   *database.Database
+	opts HealthOptions
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(db *database.Database) *HealthHandler {
-	return &HealthHandler{db: db}
+func NewHealthHandler(db *database.Database, opts HealthOptions) *HealthHandler {
+	return &HealthHandler{db: db, opts: opts}
 }
 
-// Health handles basic health check
+// Health handles basic health check.
+// The database is pinged to report its latency, but an unreachable database does not
+// fail liveness: restarting the process would not fix it.
 func (h *HealthHandler) Health(c *gin.Context) {
+	latency, err := h.pingDatabase(c, h.opts.LivenessTimeout)
+	database := "connected"
+	if err != nil {
+		database = "unreachable"
+	}
+
 	response.Success(c, http.StatusOK, "Service is healthy", gin.H{
-		"status": "ok",
+		"status":              "ok",
+		"database":            database,
+		"database_latency_ms": latency.Milliseconds(),
 	})
 }
 
-// Ready handles readiness check with database ping
+// Ready handles readiness check with database ping.
+// While the database circuit breaker is open the service is not ready, so load balancers
+// stop sending it requests it would only fail.
 func (h *HealthHandler) Ready(c *gin.Context) {
+	breaker := repository.CircuitBreakerFor(h.db.DB)
+	if breaker != nil && breaker.Open() {
+		response.ErrorWithDetails(c, http.StatusServiceUnavailable, "database_not_ready", "Database circuit breaker is open", gin.H{
+			"circuit_breaker": breaker.State(),
+		})
+		return
+	}
+
 	// Check database connection
-	if err := h.db.Health(); err != nil {
-		response.Error(c, http.StatusServiceUnavailable, "Database is not ready")
+	latency, err := h.pingDatabase(c, h.opts.ReadinessTimeout)
+	if err != nil {
+		response.ErrorWithDetails(c, http.StatusServiceUnavailable, "database_not_ready", "Database is not ready", gin.H{
+			"database_latency_ms": latency.Milliseconds(),
+		})
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Service is ready", gin.H{
-		"status":   "ok",
-		"database": "connected",
-	})
+	data := gin.H{
+		"status":              "ok",
+		"database":            "connected",
+		"database_latency_ms": latency.Milliseconds(),
+	}
+	if breaker != nil {
+		data["circuit_breaker"] = breaker.State()
+	}
+	response.Success(c, http.StatusOK, "Service is ready", data)
+}
+
+// pingDatabase pings the database within timeout and returns how long it took
+func (h *HealthHandler) pingDatabase(c *gin.Context, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := h.db.Health(ctx)
+	return time.Since(start), err
 }
@@ -1,10 +1,18 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/nielwyn/inventory-system/internal/export"
+	"github.com/nielwyn/inventory-system/internal/importer"
 	"github.com/nielwyn/inventory-system/internal/models"
 	"github.com/nielwyn/inventory-system/internal/service"
 	"github.com/nielwyn/inventory-system/pkg/logger"
@@ -15,42 +23,321 @@ import (
 
 // InventoryHandler handles inventory endpoints
 type InventoryHandler struct {
-	inventoryService service.InventoryService
+	inventoryService   service.InventoryService
+	preferencesService service.PreferencesService
+	labelSize          export.LabelSize
 }
 
 // NewInventoryHandler creates a new inventory handler
-func NewInventoryHandler(inventoryService service.InventoryService) *InventoryHandler {
-	return &InventoryHandler{inventoryService: inventoryService}
+func NewInventoryHandler(inventoryService service.InventoryService, preferencesService service.PreferencesService, labelSize export.LabelSize) *InventoryHandler {
+	return &InventoryHandler{inventoryService: inventoryService, preferencesService: preferencesService, labelSize: labelSize}
 }
 
-// CreateItem handles creating a new inventory item
+// CreateItem handles creating a new inventory item. With ?on_conflict=ignore or update,
+// a SKU already in use answers 200 with the existing or updated item instead of 409.
 func (h *InventoryHandler) CreateItem(c *gin.Context) {
+	var query models.CreateItemQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
 	var req models.CreateItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
 		return
 	}
 
-	item, err := h.inventoryService.CreateItem(&req)
+	item, created, err := h.inventoryService.CreateItem(c.Request.Context(), c.GetUint("user_id"), &req, query.OnConflict)
+	if err != nil {
+		respondError(c, err, "Failed to create item")
+		return
+	}
+
+	switch {
+	case created:
+		response.Success(c, http.StatusCreated, "Item created successfully", itemView(c, item))
+	case query.OnConflict == models.OnConflictUpdate:
+		response.Success(c, http.StatusOK, "Item updated successfully", itemView(c, item))
+	default:
+		response.Success(c, http.StatusOK, "Item already exists", itemView(c, item))
+	}
+}
+
+// BulkCreateItems handles creating several inventory items in one request.
+// The body is decoded an entry at a time and refused as soon as it holds more than
+// INVENTORY_MAX_BULK_SIZE items, rather than after reading all of it.
+func (h *InventoryHandler) BulkCreateItems(c *gin.Context) {
+	array, err := newItemArray[models.CreateItemRequest](c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	entries, err := array.ReadAll(validator.MaxBulkSize())
 	if err != nil {
-		logger.Error("Failed to create item", zap.Error(err))
 		response.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	response.Success(c, http.StatusCreated, "Item created successfully", item)
+	req := models.BulkCreateItemsRequest{Items: entries}
+	items, err := h.inventoryService.BulkCreateItems(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, "Failed to create items")
+		return
+	}
+
+	views := make([]interface{}, len(items))
+	for i, item := range items {
+		views[i] = itemView(c, item)
+	}
+	response.Success(c, http.StatusCreated, "Items created successfully", views)
+}
+
+// SyncItems handles upserting a feed of items keyed by SKU. The body is decoded and
+// written INVENTORY_MAX_BULK_SIZE items at a time, so a feed of any length is synced
+// without holding it in memory; it is still one transaction.
+func (h *InventoryHandler) SyncItems(c *gin.Context) {
+	array, err := newItemArray[models.SyncItemRequest](c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	batchSize := validator.MaxBulkSize()
+	result, err := h.inventoryService.SyncItemStream(c.Request.Context(), c.GetUint("user_id"), func() ([]models.SyncItemRequest, error) {
+		batch, err := array.Next(batchSize)
+		if err != nil {
+			return nil, &service.ValidationError{Message: err.Error()}
+		}
+		return batch, nil
+	})
+	if err != nil {
+		respondError(c, err, "Failed to sync items")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Items synced successfully", result)
 }
 
-// GetAllItems handles retrieving all inventory items
+// newItemArray reads the "items" array of the request body. SnakeCaseRequests leaves
+// these bodies whole, so with camelCase keys each entry is converted as it is read.
+func newItemArray[T any](c *gin.Context) (*importer.ItemArray[T], error) {
+	array, err := importer.NewItemArray[T](c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.KeyCase() == response.KeyCaseCamel {
+		array.RewriteKeys(response.SnakeKeys)
+	}
+	return array, nil
+}
+
+// ImportItems handles upserting items by SKU from a CSV file, sent either as the "file"
+// field of a multipart form or as the raw request body. Columns are matched to item fields
+// by name, or through the optional ?map=column:field,... mapping.
+func (h *InventoryHandler) ImportItems(c *gin.Context) {
+	mapping, err := importer.ParseColumnMap(c.Query("map"))
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, "validation_failed", err.Error())
+		return
+	}
+
+	body := c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		file, err := c.FormFile("file")
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Field 'file' is required")
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Failed to read uploaded file")
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	items, err := importer.ReadItems(body, mapping)
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, "validation_failed", err.Error())
+		return
+	}
+
+	req := models.SyncItemsRequest{Items: items}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, "validation_failed", validator.FormatValidationError(err))
+		return
+	}
+
+	result, err := h.inventoryService.SyncItems(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to import items")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Items imported successfully", result)
+}
+
+// GetAllItems handles retrieving a page of inventory items.
+// The total number of matching items is returned in the X-Total-Count header when
+// requested with ?count=true or a "Prefer: count=exact" header. Page size and category
+// default to the user's preferences.
 func (h *InventoryHandler) GetAllItems(c *gin.Context) {
-	items, err := h.inventoryService.GetAllItems()
+	var query models.ListItemsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+	// Deleted items are for admins only; the flag is ignored for everyone else
+	query.IncludeDeleted = query.IncludeDeleted && isAdmin(c)
+	fields := models.SplitFields(query.Fields)
+	if err := validateItemFields(c, fields); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Sorting by quantity would reveal the stock levels anonymous callers are not shown
+	if isAnonymous(c) && sortsByQuantity(query.Sort) {
+		response.Error(c, http.StatusBadRequest, "Sorting by quantity requires authentication")
+		return
+	}
+	if err := applyPreferences(c, h.preferencesService, &query.PageSize, &query.Category); err != nil {
+		respondError(c, err, "Failed to retrieve items")
+		return
+	}
+	query.Normalize()
+
+	items, err := h.inventoryService.GetAllItems(c.Request.Context(), &query)
 	if err != nil {
-		logger.Error("Failed to retrieve items", zap.Error(err))
-		response.Error(c, http.StatusInternalServerError, "Failed to retrieve items")
+		respondError(c, err, "Failed to retrieve items")
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Items retrieved successfully", items)
+	if query.Count || wantsExactCount(c) {
+		total, err := h.inventoryService.CountItems(c.Request.Context(), &query)
+		if err != nil {
+			respondError(c, err, "Failed to retrieve items")
+			return
+		}
+		c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	}
+
+	var data interface{} = itemViews(c, items)
+	if len(fields) > 0 {
+		projected := make([]map[string]interface{}, len(items))
+		for i := range items {
+			projected[i] = items[i].Project(fields)
+		}
+		data = projected
+	}
+
+	response.SuccessWithMeta(c, http.StatusOK, "Items retrieved successfully", data, response.Pagination{
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	})
+}
+
+// ExportItems handles downloading the items matching the list filters as JSON, CSV or XLSX.
+// The category defaults to the user's preferred category.
+// JSON and CSV are streamed as they are read; XLSX is built in memory and sent at the end.
+func (h *InventoryHandler) ExportItems(c *gin.Context) {
+	var query models.ExportItemsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+	if query.Format == "" {
+		query.Format = export.FormatCSV
+	}
+	if err := applyPreferences(c, h.preferencesService, nil, &query.Category); err != nil {
+		respondError(c, err, "Failed to export items")
+		return
+	}
+
+	var buf bytes.Buffer
+	out := io.Writer(c.Writer)
+	if query.Format == export.FormatXLSX {
+		out = &buf
+	}
+
+	// The writer is created on the first batch so that errors raised before any
+	// item is read can still be answered with a normal error response
+	var writer export.ItemWriter
+	start := func() error {
+		c.Header("Content-Type", export.ContentTypes[query.Format])
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="items.%s"`, query.Format))
+		var err error
+		writer, err = export.NewItemWriter(query.Format, out, isAdmin(c))
+		return err
+	}
+
+	err := h.inventoryService.ExportItems(c.Request.Context(), &query, func(items []models.Item) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return writer.Write(items)
+	})
+	if err == nil && writer == nil {
+		err = start()
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		if writer == nil || query.Format == export.FormatXLSX {
+			respondError(c, err, "Failed to export items")
+			return
+		}
+		// Part of the body has been sent, so the status can no longer change
+		if contextErrorStatus(c.Request.Context(), err) != StatusClientClosedRequest {
+			logger.Error("Failed to export items", zap.Error(err))
+		}
+		c.Abort()
+		return
+	}
+
+	if query.Format == export.FormatXLSX {
+		c.Data(http.StatusOK, export.ContentTypes[query.Format], buf.Bytes())
+	}
+}
+
+// wantsExactCount reports whether the client asked for a total count via the Prefer header
+func wantsExactCount(c *gin.Context) bool {
+	for _, pref := range strings.Split(c.GetHeader("Prefer"), ",") {
+		if strings.EqualFold(strings.TrimSpace(pref), "count=exact") {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCategorySummary handles listing categories with their item counts
+func (h *InventoryHandler) GetCategorySummary(c *gin.Context) {
+	counts, err := h.inventoryService.GetCategorySummary(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve category summary")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Category summary retrieved successfully", counts)
+}
+
+// GetFacets handles retrieving filter counts for a search
+func (h *InventoryHandler) GetFacets(c *gin.Context) {
+	var query models.FacetsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	facets, err := h.inventoryService.GetFacets(c.Request.Context(), &query)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve facets")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Facets retrieved successfully", facets)
 }
 
 // GetItemByID handles retrieving a single inventory item by ID
@@ -62,17 +349,138 @@ func (h *InventoryHandler) GetItemByID(c *gin.Context) {
 		return
 	}
 
-	item, err := h.inventoryService.GetItemByID(uint(id))
+	// Deleted items are for admins only; the flag is ignored for everyone else
+	includeDeleted := false
+	if flag := c.Query("include_deleted"); flag != "" && isAdmin(c) {
+		if includeDeleted, err = strconv.ParseBool(flag); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid include_deleted flag")
+			return
+		}
+	}
+
+	item, err := h.inventoryService.GetItemByID(c.Request.Context(), uint(id), includeDeleted)
 	if err != nil {
-		logger.Error("Failed to retrieve item", zap.Error(err))
-		response.Error(c, http.StatusNotFound, err.Error())
+		respondError(c, err, "Failed to retrieve item")
+		return
+	}
+
+	etag := item.ETag()
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Item retrieved successfully", item)
+	// A single row is read whole, since the ETag needs its timestamps; only the response is trimmed
+	if fields := models.SplitFields(c.Query("fields")); len(fields) > 0 {
+		if err := validateItemFields(c, fields); err != nil {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Success(c, http.StatusOK, "Item retrieved successfully", item.Project(fields))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Item retrieved successfully", itemView(c, item))
 }
 
-// UpdateItem handles updating an inventory item
+// GetItemLabel handles rendering an item's printable label as a PDF download.
+// Errors are answered as JSON, since nothing is written until the PDF is complete.
+func (h *InventoryHandler) GetItemLabel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	item, err := h.inventoryService.GetItemByID(c.Request.Context(), uint(id), false)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve item")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteItemLabel(&buf, item, h.labelSize); err != nil {
+		if errors.Is(err, export.ErrUnencodable) {
+			response.ErrorWithCode(c, http.StatusUnprocessableEntity, "sku_not_encodable", "The item's SKU cannot be printed as a barcode")
+			return
+		}
+		respondError(c, err, "Failed to render label")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="label-%s.pdf"`, labelFileName.Replace(item.SKU)))
+	c.Data(http.StatusOK, export.ContentTypePDF, buf.Bytes())
+}
+
+// labelFileName replaces the characters of a SKU that are unsafe in a download file name
+var labelFileName = strings.NewReplacer(`"`, "_", `\`, "_", "/", "_", "\r", "_", "\n", "_", ";", "_")
+
+// BatchGetItems handles retrieving several inventory items by ID in one request
+func (h *InventoryHandler) BatchGetItems(c *gin.Context) {
+	var req models.BatchGetItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	items, err := h.inventoryService.GetItemsByIDs(c.Request.Context(), req.IDs)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve items")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Items retrieved successfully", itemViews(c, items))
+}
+
+// MergeItems handles folding a duplicate item into another
+func (h *InventoryHandler) MergeItems(c *gin.Context) {
+	var req models.MergeItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	item, err := h.inventoryService.MergeItems(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to merge items")
+		return
+	}
+
+	logger.Info("Items merged",
+		zap.Uint("source_id", req.SourceID),
+		zap.Uint("target_id", req.TargetID),
+		zap.Uint("user_id", c.GetUint("user_id")),
+	)
+	response.Success(c, http.StatusOK, "Items merged successfully", itemView(c, item))
+}
+
+// CloneItem handles creating a copy of an inventory item under a new SKU
+func (h *InventoryHandler) CloneItem(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	var req models.CloneItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	item, err := h.inventoryService.CloneItem(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		respondError(c, err, "Failed to clone item")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Item cloned successfully", itemView(c, item))
+}
+
+// UpdateItem handles updating an inventory item. An If-Match header makes the update
+// conditional on the item still having that ETag.
 func (h *InventoryHandler) UpdateItem(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
@@ -87,14 +495,14 @@ func (h *InventoryHandler) UpdateItem(c *gin.Context) {
 		return
 	}
 
-	item, err := h.inventoryService.UpdateItem(uint(id), &req)
+	item, err := h.inventoryService.UpdateItem(c.Request.Context(), uint(id), c.GetUint("user_id"), &req, c.GetHeader("If-Match"))
 	if err != nil {
-		logger.Error("Failed to update item", zap.Error(err))
-		response.Error(c, http.StatusBadRequest, err.Error())
+		respondError(c, err, "Failed to update item")
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Item updated successfully", item)
+	c.Header("ETag", item.ETag())
+	response.Success(c, http.StatusOK, "Item updated successfully", itemView(c, item))
 }
 
 // DeleteItem handles deleting an inventory item
@@ -106,11 +514,184 @@ func (h *InventoryHandler) DeleteItem(c *gin.Context) {
 		return
 	}
 
-	if err := h.inventoryService.DeleteItem(uint(id)); err != nil {
-		logger.Error("Failed to delete item", zap.Error(err))
-		response.Error(c, http.StatusNotFound, err.Error())
+	if err := h.inventoryService.DeleteItem(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err, "Failed to delete item")
+		return
+	}
+
+	response.Deleted(c, "Item deleted successfully", nil)
+}
+
+// DeleteItems handles deleting every item in a category. The request must carry
+// confirm=true, so a stray call without it cannot purge a category.
+func (h *InventoryHandler) DeleteItems(c *gin.Context) {
+	var query models.DeleteItemsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+	if !query.Confirm {
+		response.ErrorWithCode(c, http.StatusBadRequest, "confirmation_required", "Deleting every item in a category requires confirm=true")
+		return
+	}
+
+	result, err := h.inventoryService.DeleteItemsByCategory(c.Request.Context(), query.Category)
+	if err != nil {
+		respondError(c, err, "Failed to delete items")
+		return
+	}
+
+	logger.Info("Items deleted by category",
+		zap.String("category", result.Category),
+		zap.Int64("deleted", result.Deleted),
+		zap.Uint("user_id", c.GetUint("user_id")),
+	)
+	response.Deleted(c, "Items deleted successfully", result)
+}
+
+// BulkUpdatePrices handles changing the price of every item in a category
+func (h *InventoryHandler) BulkUpdatePrices(c *gin.Context) {
+	var req models.BulkPriceUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	result, err := h.inventoryService.BulkUpdatePrices(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to update prices")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Prices updated successfully", result)
+}
+
+// AdjustStock handles changing an item's quantity by a delta or to an absolute value
+func (h *InventoryHandler) AdjustStock(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	var req models.AdjustStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	item, err := h.inventoryService.AdjustStock(c.Request.Context(), uint(id), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to adjust stock")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Stock adjusted successfully", itemView(c, item))
+}
+
+// AdjustStockBySKU handles changing the quantity of the item with the given SKU
+func (h *InventoryHandler) AdjustStockBySKU(c *gin.Context) {
+	var req models.AdjustStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	item, err := h.inventoryService.AdjustStockBySKU(c.Request.Context(), c.Param("sku"), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to adjust stock")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Stock adjusted successfully", itemView(c, item))
+}
+
+// Reconcile handles applying the counted quantities from a physical stock-take
+func (h *InventoryHandler) Reconcile(c *gin.Context) {
+	var req models.ReconcileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	result, err := h.inventoryService.Reconcile(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to reconcile stock")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Stock reconciled successfully", result)
+}
+
+// GetMarginReport handles reporting the margin of the stock held per category
+func (h *InventoryHandler) GetMarginReport(c *gin.Context) {
+	report, err := h.inventoryService.GetMarginReport(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve margin report")
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Item deleted successfully", nil)
+	response.Success(c, http.StatusOK, "Margin report retrieved successfully", report)
+}
+
+// isAdmin reports whether the caller has the admin role
+func isAdmin(c *gin.Context) bool {
+	return c.GetString("role") == models.RoleAdmin
+}
+
+// isAnonymous reports whether the request was let through without a token, on a route
+// made public with AUTH_PUBLIC_ROUTES
+func isAnonymous(c *gin.Context) bool {
+	_, ok := c.Get("user_id")
+	return !ok
+}
+
+// sortsByQuantity reports whether a ?sort= list orders by quantity in either direction
+func sortsByQuantity(sort string) bool {
+	for _, part := range strings.Split(sort, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(part), "-") == "quantity" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateItemFields checks a ?fields= selection against what the caller may see
+func validateItemFields(c *gin.Context, fields []string) error {
+	if isAnonymous(c) {
+		return models.ValidatePublicItemFields(fields)
+	}
+	return models.ValidateItemFields(fields, isAdmin(c))
+}
+
+// itemView returns item as the caller may see it; only admins see the cost price, and
+// anonymous callers see whether it is in stock rather than the quantity
+func itemView(c *gin.Context, item *models.Item) interface{} {
+	switch {
+	case isAdmin(c):
+		return item.WithCost()
+	case isAnonymous(c):
+		return item.Public()
+	}
+	return item
+}
+
+// itemViews returns items as the caller may see them, like itemView
+func itemViews(c *gin.Context, items []models.Item) interface{} {
+	switch {
+	case isAdmin(c):
+		views := make([]models.ItemWithCost, len(items))
+		for i := range items {
+			views[i] = items[i].WithCost()
+		}
+		return views
+	case isAnonymous(c):
+		views := make([]models.PublicItem, len(items))
+		for i := range items {
+			views[i] = items[i].Public()
+		}
+		return views
+	}
+	return items
 }
@@ -0,0 +1,593 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/export"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"gorm.io/gorm"
+)
+
+// stubInventoryService answers list and lookup requests from a fixed set of items, and
+// fails writes with err. Deleted items are only returned when asked for. Creating an item
+// with the SKU of one of items follows the on_conflict mode.
+type stubInventoryService struct {
+	service.InventoryService
+	items   []models.Item
+	deleted []models.Item
+	err     error
+	purged  []string // categories passed to DeleteItemsByCategory
+}
+
+func (s *stubInventoryService) CreateItem(_ context.Context, _ uint, req *models.CreateItemRequest, onConflict string) (*models.Item, bool, error) {
+	if s.err != nil {
+		return nil, false, s.err
+	}
+	for _, existing := range s.items {
+		if existing.SKU != req.SKU {
+			continue
+		}
+		switch onConflict {
+		case models.OnConflictIgnore:
+			return &existing, false, nil
+		case models.OnConflictUpdate:
+			existing.Name, existing.Quantity, existing.Price = req.Name, req.Quantity, req.Price
+			return &existing, false, nil
+		}
+		return nil, false, service.ErrSKUExists
+	}
+	return &models.Item{ID: 1, Name: req.Name, SKU: req.SKU, Quantity: req.Quantity, Unit: models.UnitEach, Price: req.Price}, true, nil
+}
+
+func (s *stubInventoryService) UpdateItem(context.Context, uint, uint, *models.UpdateItemRequest, string) (*models.Item, error) {
+	return nil, s.err
+}
+
+func (s *stubInventoryService) DeleteItem(context.Context, uint) error {
+	return s.err
+}
+
+func (s *stubInventoryService) DeleteItemsByCategory(_ context.Context, category string) (*models.DeleteItemsResponse, error) {
+	s.purged = append(s.purged, category)
+	var deleted int64
+	for _, item := range s.items {
+		if item.Category == category {
+			deleted++
+		}
+	}
+	return &models.DeleteItemsResponse{Category: category, Deleted: deleted}, nil
+}
+
+func (s *stubInventoryService) BulkCreateItems(_ context.Context, req *models.BulkCreateItemsRequest) ([]*models.Item, error) {
+	items := make([]*models.Item, len(req.Items))
+	for i, entry := range req.Items {
+		items[i] = &models.Item{ID: uint(i + 1), Name: entry.Name, SKU: entry.SKU, Unit: models.UnitEach}
+	}
+	return items, nil
+}
+
+func (s *stubInventoryService) GetItemsByIDs(context.Context, []uint) ([]models.Item, error) {
+	return s.items, nil
+}
+
+func (s *stubInventoryService) GetAllItems(_ context.Context, query *models.ListItemsQuery) ([]models.Item, error) {
+	if query.IncludeDeleted {
+		return append(append([]models.Item(nil), s.items...), s.deleted...), nil
+	}
+	return s.items, nil
+}
+
+func (s *stubInventoryService) GetItemByID(_ context.Context, id uint, includeDeleted bool) (*models.Item, error) {
+	items := s.items
+	if includeDeleted {
+		items = append(append([]models.Item(nil), s.items...), s.deleted...)
+	}
+	for i := range items {
+		if items[i].ID == id {
+			item := items[i]
+			return &item, nil
+		}
+	}
+	return nil, service.ErrItemNotFound
+}
+
+// stubPreferences counts preference lookups and returns no preferences
+type stubPreferences struct {
+	service.PreferencesService
+	lookups int
+}
+
+func (s *stubPreferences) GetPreferences(_ context.Context, userID uint) (*models.UserPreferences, error) {
+	s.lookups++
+	return &models.UserPreferences{UserID: userID}, nil
+}
+
+var testItems = []models.Item{
+	{ID: 1, Name: "Widget", SKU: "W-1", Quantity: 12, Unit: models.UnitEach, Price: 9.5, CostPrice: 4, Category: "Parts"},
+	{ID: 2, Name: "Gadget", SKU: "G-1", Quantity: 0, Unit: models.UnitEach, Price: 20, CostPrice: 11, Category: "Parts"},
+}
+
+// newItemsRouter serves the item read routes to who
+func newItemsRouter(who caller, prefs *stubPreferences) *gin.Engine {
+	h := NewInventoryHandler(&stubInventoryService{items: testItems}, prefs, export.LabelSize{})
+	router := gin.New()
+	inventory := router.Group("/api/v1/inventory", who.authenticate)
+	inventory.GET("/items", h.GetAllItems)
+	inventory.GET("/items/:id", h.GetItemByID)
+	return router
+}
+
+// keysOf returns the sorted keys of a JSON object
+func keysOf(t *testing.T, raw json.RawMessage) []string {
+	t.Helper()
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		t.Fatalf("decoding %s: %v", raw, err)
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func TestItemViewsByCaller(t *testing.T) {
+	tests := []struct {
+		name    string
+		who     caller
+		present []string
+		absent  []string
+	}{
+		{"anonymous", asAnonymous, []string{"id", "name", "sku", "price", "in_stock"}, []string{"quantity", "backordered", "cost_price", "created_at"}},
+		{"user", asUser, []string{"id", "quantity", "backordered", "created_at"}, []string{"in_stock", "cost_price"}},
+		{"admin", asAdmin, []string{"id", "quantity", "cost_price"}, []string{"in_stock"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newItemsRouter(tt.who, &stubPreferences{})
+
+			w, resp := doRequest(t, router, http.MethodGet, "/api/v1/inventory/items", "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("list status = %d, want 200 (body %s)", w.Code, w.Body)
+			}
+			var list []json.RawMessage
+			if err := json.Unmarshal(resp.Data, &list); err != nil || len(list) != len(testItems) {
+				t.Fatalf("list data = %s, want %d items", resp.Data, len(testItems))
+			}
+
+			w, single := doRequest(t, router, http.MethodGet, "/api/v1/inventory/items/1", "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("get status = %d, want 200 (body %s)", w.Code, w.Body)
+			}
+
+			for _, raw := range []json.RawMessage{list[0], single.Data} {
+				keys := keysOf(t, raw)
+				for _, key := range tt.present {
+					if !contains(keys, key) {
+						t.Errorf("%s is missing %q", raw, key)
+					}
+				}
+				for _, key := range tt.absent {
+					if contains(keys, key) {
+						t.Errorf("%s should not have %q", raw, key)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAnonymousItemInStock(t *testing.T) {
+	router := newItemsRouter(asAnonymous, &stubPreferences{})
+	_, resp := doRequest(t, router, http.MethodGet, "/api/v1/inventory/items", "")
+
+	var items []models.PublicItem
+	if err := json.Unmarshal(resp.Data, &items); err != nil {
+		t.Fatalf("decoding %s: %v", resp.Data, err)
+	}
+	if !items[0].InStock || items[1].InStock {
+		t.Errorf("in_stock = %v, %v; want true, false", items[0].InStock, items[1].InStock)
+	}
+}
+
+func TestItemFieldSelectionByCaller(t *testing.T) {
+	tests := []struct {
+		name       string
+		who        caller
+		fields     string
+		wantStatus int
+	}{
+		{"anonymous public fields", asAnonymous, "id,name,price", http.StatusOK},
+		{"anonymous quantity", asAnonymous, "id,quantity", http.StatusBadRequest},
+		{"anonymous cost price", asAnonymous, "cost_price", http.StatusBadRequest},
+		{"anonymous timestamps", asAnonymous, "created_at", http.StatusBadRequest},
+		{"user quantity", asUser, "id,quantity", http.StatusOK},
+		{"user cost price", asUser, "cost_price", http.StatusBadRequest},
+		{"admin cost price", asAdmin, "id,cost_price", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newItemsRouter(tt.who, &stubPreferences{})
+			for _, path := range []string{"/api/v1/inventory/items", "/api/v1/inventory/items/1"} {
+				w, _ := doRequest(t, router, http.MethodGet, path+"?fields="+tt.fields, "")
+				if w.Code != tt.wantStatus {
+					t.Errorf("GET %s status = %d, want %d (body %s)", path, w.Code, tt.wantStatus, w.Body)
+				}
+			}
+		})
+	}
+}
+
+func TestItemSortByQuantityByCaller(t *testing.T) {
+	tests := []struct {
+		name       string
+		who        caller
+		sort       string
+		wantStatus int
+	}{
+		{"anonymous by price", asAnonymous, "-price", http.StatusOK},
+		{"anonymous by quantity", asAnonymous, "quantity", http.StatusBadRequest},
+		{"anonymous by quantity descending", asAnonymous, "name,%20-quantity", http.StatusBadRequest},
+		{"user by quantity", asUser, "-quantity", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newItemsRouter(tt.who, &stubPreferences{})
+			w, _ := doRequest(t, router, http.MethodGet, "/api/v1/inventory/items?sort="+tt.sort, "")
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestAnonymousItemsSkipPreferences(t *testing.T) {
+	for _, tt := range []struct {
+		who         caller
+		wantLookups int
+	}{
+		{asAnonymous, 0},
+		{asUser, 1},
+	} {
+		prefs := &stubPreferences{}
+		router := newItemsRouter(tt.who, prefs)
+		if w, _ := doRequest(t, router, http.MethodGet, "/api/v1/inventory/items", ""); w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body)
+		}
+		if prefs.lookups != tt.wantLookups {
+			t.Errorf("caller %+v: preference lookups = %d, want %d", tt.who, prefs.lookups, tt.wantLookups)
+		}
+	}
+}
+
+func TestValidatePublicRoutes(t *testing.T) {
+	tests := []struct {
+		routes  []string
+		wantErr bool
+	}{
+		{nil, false},
+		{[]string{"/api/v1/inventory/items", "/api/v1/inventory/items/:id", "/api/v1/inventory/facets"}, false},
+		{[]string{"/api/v1/inventory/items/export"}, true},
+		{[]string{"/api/v1/admin/report"}, true},
+	}
+	for _, tt := range tests {
+		if err := ValidatePublicRoutes(tt.routes); (err != nil) != tt.wantErr {
+			t.Errorf("ValidatePublicRoutes(%v) error = %v, want error %v", tt.routes, err, tt.wantErr)
+		}
+	}
+}
+
+func TestItemWriteErrorStatus(t *testing.T) {
+	notFound := service.ErrItemNotFound
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{"create with a SKU in use", http.MethodPost, "/items", `{"name":"Widget","sku":"W-1"}`, service.ErrSKUExists, http.StatusConflict},
+		{"create with an invalid body", http.MethodPost, "/items", `{"sku":"W-1"}`, nil, http.StatusBadRequest},
+		{"create rejected by the service", http.MethodPost, "/items", `{"name":"Widget","sku":"W-1"}`, &service.ValidationError{Message: "Field 'Category' is required"}, http.StatusBadRequest},
+		{"update a missing item", http.MethodPut, "/items/9", `{"name":"Widget"}`, notFound, http.StatusNotFound},
+		{"update to a SKU in use", http.MethodPut, "/items/1", `{"sku":"G-1"}`, service.ErrSKUExists, http.StatusConflict},
+		{"update with an invalid body", http.MethodPut, "/items/1", `{"price":-1}`, nil, http.StatusBadRequest},
+		{"update with an invalid ID", http.MethodPut, "/items/abc", `{"name":"Widget"}`, nil, http.StatusBadRequest},
+		{"delete a missing item", http.MethodDelete, "/items/9", "", notFound, http.StatusNotFound},
+		{"delete with an invalid ID", http.MethodDelete, "/items/abc", "", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewInventoryHandler(&stubInventoryService{err: tt.err}, &stubPreferences{}, export.LabelSize{})
+			router := gin.New()
+			items := router.Group("", asAdmin.authenticate)
+			items.POST("/items", h.CreateItem)
+			items.PUT("/items/:id", h.UpdateItem)
+			items.DELETE("/items/:id", h.DeleteItem)
+
+			w, _ := doRequest(t, router, tt.method, tt.path, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestIncludeDeletedByCaller(t *testing.T) {
+	deleted := models.Item{ID: 3, Name: "Old", SKU: "O-1", Unit: models.UnitEach, Category: "Parts",
+		DeletedAt: gorm.DeletedAt{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}}
+
+	tests := []struct {
+		name        string
+		who         caller
+		wantDeleted bool
+	}{
+		{"anonymous", asAnonymous, false},
+		{"user", asUser, false},
+		{"admin", asAdmin, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewInventoryHandler(&stubInventoryService{items: testItems, deleted: []models.Item{deleted}}, &stubPreferences{}, export.LabelSize{})
+			router := gin.New()
+			inventory := router.Group("/api/v1/inventory", tt.who.authenticate)
+			inventory.GET("/items", h.GetAllItems)
+			inventory.GET("/items/:id", h.GetItemByID)
+
+			_, resp := doRequest(t, router, http.MethodGet, "/api/v1/inventory/items?include_deleted=true", "")
+			var list []json.RawMessage
+			if err := json.Unmarshal(resp.Data, &list); err != nil {
+				t.Fatalf("decoding %s: %v", resp.Data, err)
+			}
+			wantCount := len(testItems)
+			if tt.wantDeleted {
+				wantCount++
+			}
+			if len(list) != wantCount {
+				t.Errorf("listed %d items, want %d", len(list), wantCount)
+			}
+
+			w, single := doRequest(t, router, http.MethodGet, "/api/v1/inventory/items/3?include_deleted=true", "")
+			if !tt.wantDeleted {
+				if w.Code != http.StatusNotFound {
+					t.Errorf("get deleted item status = %d, want 404 (body %s)", w.Code, w.Body)
+				}
+				return
+			}
+			if w.Code != http.StatusOK {
+				t.Fatalf("get deleted item status = %d, want 200 (body %s)", w.Code, w.Body)
+			}
+			if keys := keysOf(t, single.Data); !contains(keys, "deleted_at") {
+				t.Errorf("%s is missing \"deleted_at\"", single.Data)
+			}
+		})
+	}
+}
+
+func TestIncludeDeletedInvalidFlag(t *testing.T) {
+	tests := []struct {
+		who        caller
+		wantStatus int
+	}{
+		{asUser, http.StatusOK},
+		{asAdmin, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		router := newItemsRouter(tt.who, &stubPreferences{})
+		w, _ := doRequest(t, router, http.MethodGet, "/api/v1/inventory/items/1?include_deleted=maybe", "")
+		if w.Code != tt.wantStatus {
+			t.Errorf("caller %+v: status = %d, want %d (body %s)", tt.who, w.Code, tt.wantStatus, w.Body)
+		}
+	}
+}
+
+func TestDeleteItemsConfirmation(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCode   string
+	}{
+		{"without confirm", "?category=Parts", http.StatusBadRequest, "confirmation_required"},
+		{"confirm false", "?category=Parts&confirm=false", http.StatusBadRequest, "confirmation_required"},
+		{"confirm not a bool", "?category=Parts&confirm=yes please", http.StatusBadRequest, ""},
+		{"without category", "?confirm=true", http.StatusBadRequest, ""},
+		{"confirmed", "?category=Parts&confirm=true", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inventory := &stubInventoryService{items: testItems}
+			h := NewInventoryHandler(inventory, &stubPreferences{}, export.LabelSize{})
+			router := gin.New()
+			router.DELETE("/items", asAdmin.authenticate, h.DeleteItems)
+
+			w, resp := doRequest(t, router, http.MethodDelete, "/items"+strings.ReplaceAll(tt.query, " ", "%20"), "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantCode != "" && resp.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", resp.Code, tt.wantCode)
+			}
+			wantPurged := 0
+			if tt.wantStatus == http.StatusOK {
+				wantPurged = 1
+			}
+			if len(inventory.purged) != wantPurged {
+				t.Errorf("DeleteItemsByCategory called %d times, want %d", len(inventory.purged), wantPurged)
+			}
+			if wantPurged == 1 {
+				var result models.DeleteItemsResponse
+				if err := json.Unmarshal(resp.Data, &result); err != nil || result.Deleted != 2 {
+					t.Errorf("data = %s, want 2 items deleted", resp.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestBulkSizeLimit(t *testing.T) {
+	const limit = 5 // set by TestMain
+	items := func(n int) string {
+		entries := make([]string, n)
+		for i := range entries {
+			entries[i] = fmt.Sprintf(`{"name":"Item %d","sku":"I-%d"}`, i, i)
+		}
+		return `{"items":[` + strings.Join(entries, ",") + `]}`
+	}
+	ids := func(n int) string {
+		entries := make([]string, n)
+		for i := range entries {
+			entries[i] = strconv.Itoa(i + 1)
+		}
+		return `{"ids":[` + strings.Join(entries, ",") + `]}`
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"bulk create at the limit", "/items/bulk", items(limit), http.StatusCreated},
+		{"bulk create over the limit", "/items/bulk", items(limit + 1), http.StatusBadRequest},
+		{"batch get at the limit", "/items/batch", ids(limit), http.StatusOK},
+		{"batch get over the limit", "/items/batch", ids(limit + 1), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewInventoryHandler(&stubInventoryService{items: testItems}, &stubPreferences{}, export.LabelSize{})
+			router := gin.New()
+			items := router.Group("", asAdmin.authenticate)
+			items.POST("/items/bulk", h.BulkCreateItems)
+			items.POST("/items/batch", h.BatchGetItems)
+
+			w, resp := doRequest(t, router, http.MethodPost, tt.path, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+			if want := fmt.Sprintf("must contain at most %d entries", limit); w.Code == http.StatusBadRequest && !strings.Contains(resp.Message, want) {
+				t.Errorf("message = %q, want it to say %q", resp.Message, want)
+			}
+		})
+	}
+}
+
+func TestItemNumberBoundaries(t *testing.T) {
+	// TestMain caps quantities at 1000000 and prices at 99999999.99
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"largest quantity", `{"name":"Bolt","sku":"B-1","quantity":1000000}`, http.StatusCreated},
+		{"quantity over the cap", `{"name":"Bolt","sku":"B-1","quantity":1000000.001}`, http.StatusBadRequest},
+		{"quantity past int32", `{"name":"Bolt","sku":"B-1","quantity":2147483648}`, http.StatusBadRequest},
+		{"quantity with three decimals", `{"name":"Bolt","sku":"B-1","quantity":1.125}`, http.StatusCreated},
+		{"quantity with four decimals", `{"name":"Bolt","sku":"B-1","quantity":1.1255}`, http.StatusBadRequest},
+		{"largest price", `{"name":"Bolt","sku":"B-1","price":99999999.99}`, http.StatusCreated},
+		{"price over the cap", `{"name":"Bolt","sku":"B-1","price":100000000}`, http.StatusBadRequest},
+		{"price with three decimals", `{"name":"Bolt","sku":"B-1","price":1.999}`, http.StatusBadRequest},
+		{"negative cost price", `{"name":"Bolt","sku":"B-1","cost_price":-0.01}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewInventoryHandler(&stubInventoryService{}, &stubPreferences{}, export.LabelSize{})
+			router := gin.New()
+			router.POST("/items", asAdmin.authenticate, h.CreateItem)
+
+			w, _ := doRequest(t, router, http.MethodPost, "/items", tt.body)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestEmptyItemListIsArray(t *testing.T) {
+	for _, who := range []caller{asAnonymous, asUser, asAdmin} {
+		h := NewInventoryHandler(&stubInventoryService{}, &stubPreferences{}, export.LabelSize{})
+		router := gin.New()
+		router.GET("/items", who.authenticate, h.GetAllItems)
+
+		for _, path := range []string{"/items", "/items?fields=id,name"} {
+			w, resp := doRequest(t, router, http.MethodGet, path, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("caller %+v: GET %s status = %d, want 200 (body %s)", who, path, w.Code, w.Body)
+			}
+			if string(resp.Data) != "[]" {
+				t.Errorf("caller %+v: GET %s data = %s, want []", who, path, resp.Data)
+			}
+		}
+	}
+}
+
+func TestCreateItemOnConflict(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		body        string
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+		wantName    string
+	}{
+		{"default", "", `{"name":"New Widget","sku":"W-1","price":3}`, http.StatusConflict, "sku_exists", "", ""},
+		{"error", "?on_conflict=error", `{"name":"New Widget","sku":"W-1","price":3}`, http.StatusConflict, "sku_exists", "", ""},
+		{"ignore", "?on_conflict=ignore", `{"name":"New Widget","sku":"W-1","price":3}`, http.StatusOK, "", "Item already exists", "Widget"},
+		{"update", "?on_conflict=update", `{"name":"New Widget","sku":"W-1","price":3}`, http.StatusOK, "", "Item updated successfully", "New Widget"},
+		{"ignore with a new SKU", "?on_conflict=ignore", `{"name":"Sprocket","sku":"S-1","price":3}`, http.StatusCreated, "", "Item created successfully", "Sprocket"},
+		{"update with a new SKU", "?on_conflict=update", `{"name":"Sprocket","sku":"S-1","price":3}`, http.StatusCreated, "", "Item created successfully", "Sprocket"},
+		{"unknown mode", "?on_conflict=replace", `{"name":"New Widget","sku":"W-1","price":3}`, http.StatusBadRequest, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewInventoryHandler(&stubInventoryService{items: testItems}, &stubPreferences{}, export.LabelSize{})
+			router := gin.New()
+			router.POST("/items", asAdmin.authenticate, h.CreateItem)
+
+			w, resp := doRequest(t, router, http.MethodPost, "/items"+tt.query, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+			if resp.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", resp.Code, tt.wantCode)
+			}
+			if tt.wantMessage == "" {
+				return
+			}
+			if resp.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", resp.Message, tt.wantMessage)
+			}
+			var item struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(resp.Data, &item); err != nil {
+				t.Fatalf("decoding item %s: %v", resp.Data, err)
+			}
+			if item.Name != tt.wantName {
+				t.Errorf("item name = %q, want %q", item.Name, tt.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+)
+
+// LocationHandler handles location endpoints
+type LocationHandler struct {
+	locationService    service.LocationService
+	preferencesService service.PreferencesService
+}
+
+// NewLocationHandler creates a new location handler
+func NewLocationHandler(locationService service.LocationService, preferencesService service.PreferencesService) *LocationHandler {
+	return &LocationHandler{locationService: locationService, preferencesService: preferencesService}
+}
+
+// GetStock handles retrieving a page of a location's stock levels with their item details.
+// The page size and category default to the user's preferences.
+func (h *LocationHandler) GetStock(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid location ID")
+		return
+	}
+
+	var query models.LocationStockQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+	if err := applyPreferences(c, h.preferencesService, &query.PageSize, &query.Category); err != nil {
+		respondError(c, err, "Failed to retrieve stock levels")
+		return
+	}
+	query.Normalize()
+
+	stock, err := h.locationService.GetStock(c.Request.Context(), uint(id), &query)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve stock levels")
+		return
+	}
+
+	response.SuccessWithMeta(c, http.StatusOK, "Stock levels retrieved successfully", stock, response.Pagination{
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	})
+}
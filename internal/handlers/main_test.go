@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	validator.RegisterCustomValidations(validator.Limits{
+		MaxBulkSize: 5,
+		MaxQuantity: 1000000,
+		MaxPrice:    99999999.99,
+	})
+	os.Exit(m.Run())
+}
+
+// caller is the identity a test request is made with, as the Auth middleware would set
+// it; the zero value is an anonymous caller on a public route
+type caller struct {
+	userID uint
+	role   string
+}
+
+var (
+	asAnonymous = caller{}
+	asUser      = caller{userID: 7, role: "user"}
+	asAdmin     = caller{userID: 1, role: "admin"}
+)
+
+// authenticate sets the caller in the context like the Auth middleware
+func (who caller) authenticate(c *gin.Context) {
+	if who.userID != 0 {
+		c.Set("user_id", who.userID)
+		c.Set("role", who.role)
+	}
+	c.Next()
+}
+
+// apiResponse is the envelope of every response, with data and details left raw
+type apiResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Code    string          `json:"code"`
+	Data    json.RawMessage `json:"data"`
+	Details json.RawMessage `json:"details"`
+}
+
+// doRequest serves a request with an optional JSON body and decodes the response envelope
+func doRequest(t *testing.T, router http.Handler, method, path, body string) (*httptest.ResponseRecorder, apiResponse) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp apiResponse
+	if w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decoding response %q: %v", w.Body, err)
+		}
+	}
+	return w, resp
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/maintenance"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// MaintenanceHandler handles the maintenance mode admin endpoints
+type MaintenanceHandler struct {
+	mode *maintenance.Mode
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(mode *maintenance.Mode) *MaintenanceHandler {
+	return &MaintenanceHandler{mode: mode}
+}
+
+// GetStatus handles reporting whether maintenance mode is on
+func (h *MaintenanceHandler) GetStatus(c *gin.Context) {
+	response.Success(c, http.StatusOK, "Maintenance status retrieved successfully", gin.H{
+		"enabled": h.mode.Enabled(),
+	})
+}
+
+// SetStatus handles switching maintenance mode on or off
+func (h *MaintenanceHandler) SetStatus(c *gin.Context) {
+	var req models.SetMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	h.mode.Set(*req.Enabled)
+	logger.Info("Maintenance mode changed",
+		zap.Bool("enabled", *req.Enabled),
+		zap.Uint("user_id", c.GetUint("user_id")),
+	)
+
+	response.Success(c, http.StatusOK, "Maintenance status updated successfully", gin.H{
+		"enabled": h.mode.Enabled(),
+	})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/pkg/response"
+)
+
+// MethodNotAllowed returns the handler for requests to a routed path with a method it does
+// not support, for use with router.NoMethod. It answers 405 in the usual error envelope with
+// an Allow header listing the methods the path does support. Gin does not set Allow itself,
+// so the path is matched against router's routes, which must all be registered by the time
+// requests are served.
+func MethodNotAllowed(router *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if allowed := allowedMethods(router.Routes(), c.Request.URL.Path); len(allowed) > 0 {
+			c.Header("Allow", strings.Join(allowed, ", "))
+		}
+		response.ErrorWithCode(c, http.StatusMethodNotAllowed, "method_not_allowed", "Method "+c.Request.Method+" is not allowed on this resource")
+	}
+}
+
+// allowedMethods returns the sorted methods of the routes matching path
+func allowedMethods(routes gin.RoutesInfo, path string) []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, route := range routes {
+		if !seen[route.Method] && routeMatches(route.Path, path) {
+			seen[route.Method] = true
+			methods = append(methods, route.Method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// routeMatches reports whether path matches a route pattern, where a ":name" segment
+// matches any one segment and a "*name" segment matches the rest of the path
+func routeMatches(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if !strings.HasPrefix(segment, ":") && segment != pathSegments[i] {
+			return false
+		}
+	}
+	return len(patternSegments) == len(pathSegments)
+}
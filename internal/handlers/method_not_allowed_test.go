@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newMethodsRouter routes a part of the API with handlers that do nothing, answering
+// unsupported methods with MethodNotAllowed
+func newMethodsRouter() *gin.Engine {
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.NoMethod(MethodNotAllowed(router))
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	api := router.Group("/api/v1")
+	api.POST("/auth/login", ok)
+	api.GET("/auth/me/preferences", ok)
+	api.PUT("/auth/me/preferences", ok)
+	api.DELETE("/auth/me/sessions/:id", ok)
+	api.GET("/inventory/items", ok)
+	api.POST("/inventory/items", ok)
+	api.DELETE("/inventory/items", ok)
+	api.GET("/inventory/items/:id", ok)
+	api.PUT("/inventory/items/:id", ok)
+	api.PATCH("/inventory/items/:id", ok)
+	api.DELETE("/inventory/items/:id", ok)
+	api.POST("/inventory/items/:id/adjust", ok)
+	api.DELETE("/inventory/categories/:name", ok)
+	api.GET("/bundles/:id", ok)
+	api.POST("/bundles/:id/consume", ok)
+	api.GET("/admin/maintenance", ok)
+	api.PUT("/admin/maintenance", ok)
+	return router
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	tests := []struct {
+		method    string
+		path      string
+		wantAllow string
+	}{
+		{http.MethodGet, "/api/v1/auth/login", "POST"},
+		{http.MethodPost, "/api/v1/auth/me/preferences", "GET, PUT"},
+		{http.MethodGet, "/api/v1/auth/me/sessions/abc", "DELETE"},
+		{http.MethodPut, "/api/v1/inventory/items", "DELETE, GET, POST"},
+		{http.MethodPost, "/api/v1/inventory/items/7", "DELETE, GET, PATCH, PUT"},
+		{http.MethodGet, "/api/v1/inventory/items/7/adjust", "POST"},
+		{http.MethodGet, "/api/v1/inventory/categories/Parts", "DELETE"},
+		{http.MethodDelete, "/api/v1/bundles/3", "GET"},
+		{http.MethodGet, "/api/v1/bundles/3/consume", "POST"},
+		{http.MethodDelete, "/api/v1/admin/maintenance", "GET, PUT"},
+	}
+	router := newMethodsRouter()
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w, resp := doRequest(t, router, tt.method, tt.path, "")
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Fatalf("status = %d, want 405 (body %s)", w.Code, w.Body)
+			}
+			if allow := w.Header().Get("Allow"); allow != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", allow, tt.wantAllow)
+			}
+			if resp.Success || resp.Code != "method_not_allowed" {
+				t.Errorf("body = %s, want an error envelope with code method_not_allowed", w.Body)
+			}
+		})
+	}
+}
+
+func TestUnknownPathNotFound(t *testing.T) {
+	w := httptest.NewRecorder()
+	newMethodsRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/warehouses", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "" {
+		t.Errorf("Allow = %q, want none", allow)
+	}
+}
+
+func TestRouteMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/items", "/items", true},
+		{"/items", "/items/", true},
+		{"/items/:id", "/items/7", true},
+		{"/items/:id", "/items", false},
+		{"/items/:id", "/items/7/adjust", false},
+		{"/items/:id/adjust", "/items/7/adjust", true},
+		{"/items/export", "/items/7", false},
+		{"/debug/pprof/*name", "/debug/pprof/heap/extra", true},
+	}
+	for _, tt := range tests {
+		if got := routeMatches(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("routeMatches(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
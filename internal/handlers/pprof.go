@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPprofRoutes mounts the net/http/pprof handlers on rg, which must be the
+// /debug/pprof group because pprof.Index resolves profile names from the URL path
+func RegisterPprofRoutes(rg *gin.RouterGroup) {
+	rg.GET("/", gin.WrapF(pprof.Index))
+	rg.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	rg.GET("/profile", gin.WrapF(pprof.Profile))
+	rg.GET("/symbol", gin.WrapF(pprof.Symbol))
+	rg.POST("/symbol", gin.WrapF(pprof.Symbol))
+	rg.GET("/trace", gin.WrapF(pprof.Trace))
+	rg.GET("/:profile", gin.WrapF(pprof.Index)) // heap, goroutine, allocs, block, mutex, threadcreate
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+)
+
+// PreferencesHandler handles user preference endpoints
+type PreferencesHandler struct {
+	preferencesService service.PreferencesService
+}
+
+// NewPreferencesHandler creates a new preferences handler
+func NewPreferencesHandler(preferencesService service.PreferencesService) *PreferencesHandler {
+	return &PreferencesHandler{preferencesService: preferencesService}
+}
+
+// GetMyPreferences handles retrieving the authenticated user's preferences
+func (h *PreferencesHandler) GetMyPreferences(c *gin.Context) {
+	prefs, err := h.preferencesService.GetPreferences(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		respondError(c, err, "Failed to retrieve preferences")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Preferences retrieved successfully", prefs)
+}
+
+// UpdateMyPreferences handles changing the authenticated user's preferences
+func (h *PreferencesHandler) UpdateMyPreferences(c *gin.Context) {
+	var req models.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	prefs, err := h.preferencesService.UpdatePreferences(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to update preferences")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Preferences updated successfully", prefs)
+}
+
+// applyPreferences fills the page size and category of a list request from the
+// user's preferences when the client did not send them. Either pointer may be nil
+// for endpoints without that parameter. Anonymous callers have no preferences.
+func applyPreferences(c *gin.Context, preferencesService service.PreferencesService, pageSize *int, category *string) error {
+	if isAnonymous(c) {
+		return nil
+	}
+	needPageSize := pageSize != nil && *pageSize == 0
+	_, hasCategory := c.GetQuery("category")
+	needCategory := category != nil && !hasCategory
+	if !needPageSize && !needCategory {
+		return nil
+	}
+
+	prefs, err := preferencesService.GetPreferences(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		return err
+	}
+	if needPageSize {
+		*pageSize = prefs.PageSize
+	}
+	if needCategory {
+		*category = prefs.DefaultCategory
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+)
+
+// PricingHandler handles scheduled price and price history endpoints
+type PricingHandler struct {
+	pricingService service.PricingService
+}
+
+// NewPricingHandler creates a new pricing handler
+func NewPricingHandler(pricingService service.PricingService) *PricingHandler {
+	return &PricingHandler{pricingService: pricingService}
+}
+
+// SchedulePrice handles scheduling a future price change for an item
+func (h *PricingHandler) SchedulePrice(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	var req models.SchedulePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	scheduled, err := h.pricingService.SchedulePrice(c.Request.Context(), uint(id), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to schedule price change")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Price change scheduled successfully", scheduled)
+}
+
+// GetPendingPrices handles listing an item's pending price changes
+func (h *PricingHandler) GetPendingPrices(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	scheduled, err := h.pricingService.GetPendingPrices(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err, "Failed to retrieve scheduled price changes")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Scheduled price changes retrieved successfully", scheduled)
+}
+
+// CancelScheduledPrice handles cancelling a pending price change
+func (h *PricingHandler) CancelScheduledPrice(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid scheduled price ID")
+		return
+	}
+
+	if err := h.pricingService.CancelScheduledPrice(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err, "Failed to cancel scheduled price change")
+		return
+	}
+
+	response.Deleted(c, "Scheduled price change cancelled successfully", nil)
+}
+
+// GetPriceHistory handles listing an item's price history
+func (h *PricingHandler) GetPriceHistory(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	history, err := h.pricingService.GetPriceHistory(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err, "Failed to retrieve price history")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Price history retrieved successfully", history)
+}
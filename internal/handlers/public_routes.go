@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// publicRoutes are the read routes whose handlers can answer anonymous callers, trimming
+// what they return; only these can be made public with AUTH_PUBLIC_ROUTES
+var publicRoutes = map[string]bool{
+	"/api/v1/inventory/items":     true,
+	"/api/v1/inventory/items/:id": true,
+	"/api/v1/inventory/facets":    true,
+}
+
+// ValidatePublicRoutes checks that every route can be served to anonymous callers
+func ValidatePublicRoutes(routes []string) error {
+	for _, route := range routes {
+		if !publicRoutes[route] {
+			known := make([]string, 0, len(publicRoutes))
+			for name := range publicRoutes {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			return fmt.Errorf("route %q cannot be public; expected one of %s", route, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+)
+
+// PurchaseOrderHandler handles purchase order endpoints
+type PurchaseOrderHandler struct {
+	purchaseOrderService service.PurchaseOrderService
+}
+
+// NewPurchaseOrderHandler creates a new purchase order handler
+func NewPurchaseOrderHandler(purchaseOrderService service.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{purchaseOrderService: purchaseOrderService}
+}
+
+// CreatePurchaseOrder handles placing a purchase order
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(c *gin.Context) {
+	var req models.CreatePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	po, err := h.purchaseOrderService.CreatePurchaseOrder(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to create purchase order")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Purchase order created successfully", po)
+}
+
+// GetPurchaseOrder handles retrieving a purchase order
+func (h *PurchaseOrderHandler) GetPurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid purchase order ID")
+		return
+	}
+
+	po, err := h.purchaseOrderService.GetPurchaseOrder(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err, "Failed to retrieve purchase order")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Purchase order retrieved successfully", po)
+}
+
+// Receive handles booking a delivery against a purchase order
+func (h *PurchaseOrderHandler) Receive(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid purchase order ID")
+		return
+	}
+
+	var req models.ReceivePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	po, err := h.purchaseOrderService.Receive(c.Request.Context(), uint(id), c.GetUint("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to receive purchase order")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Purchase order received successfully", po)
+}
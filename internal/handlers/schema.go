@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"github.com/nielwyn/inventory-system/pkg/schema"
+)
+
+// SchemaHandler serves JSON Schema documents for the API request models
+type SchemaHandler struct {
+	schemas map[string]*schema.Schema
+}
+
+// NewSchemaHandler creates a new schema handler.
+// Schemas are generated once at startup from the request models' struct tags,
+// so they always match the server-side validation rules.
+func NewSchemaHandler() *SchemaHandler {
+	return &SchemaHandler{
+		schemas: map[string]*schema.Schema{
+			"create_item":       schema.Generate("CreateItemRequest", models.CreateItemRequest{}),
+			"bulk_create_items": schema.Generate("BulkCreateItemsRequest", models.BulkCreateItemsRequest{}),
+			"update_item":       schema.Generate("UpdateItemRequest", models.UpdateItemRequest{}),
+			"register":          schema.Generate("RegisterRequest", models.RegisterRequest{}),
+			"login":             schema.Generate("LoginRequest", models.LoginRequest{}),
+		},
+	}
+}
+
+// ListSchemas handles listing the available schema names
+func (h *SchemaHandler) ListSchemas(c *gin.Context) {
+	names := make([]string, 0, len(h.schemas))
+	for name := range h.schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	response.Success(c, http.StatusOK, "Schemas retrieved successfully", names)
+}
+
+// GetSchema handles retrieving the JSON Schema for a single request model
+func (h *SchemaHandler) GetSchema(c *gin.Context) {
+	s, ok := h.schemas[c.Param("model")]
+	if !ok {
+		response.Error(c, http.StatusNotFound, "Schema not found")
+		return
+	}
+
+	c.Header("Content-Type", "application/schema+json")
+	c.JSON(http.StatusOK, s)
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"go.uber.org/zap"
+)
+
+// SelfTestHandler handles the admin self-test endpoint
+type SelfTestHandler struct {
+	selfTestService service.SelfTestService
+}
+
+// NewSelfTestHandler creates a new self-test handler
+func NewSelfTestHandler(selfTestService service.SelfTestService) *SelfTestHandler {
+	return &SelfTestHandler{selfTestService: selfTestService}
+}
+
+// Run handles exercising every dependency and reporting each check.
+// Any failed check makes the response a 503 with the report in the details.
+func (h *SelfTestHandler) Run(c *gin.Context) {
+	report := h.selfTestService.Run(c.Request.Context())
+	if !report.Healthy {
+		logger.Warn("Self-test failed", zap.Any("checks", report.Checks))
+		response.ErrorWithDetails(c, http.StatusServiceUnavailable, "selftest_failed", "Self-test failed", report)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Self-test passed", report)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/export"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+)
+
+// SummaryHandler handles the admin dashboard summary and business report endpoints
+type SummaryHandler struct {
+	summaryService service.SummaryService
+	cacheTTL       time.Duration
+}
+
+// NewSummaryHandler creates a new summary handler. cacheTTL is how long the service
+// reuses a computed report, which clients are told they may cache it for.
+func NewSummaryHandler(summaryService service.SummaryService, cacheTTL time.Duration) *SummaryHandler {
+	return &SummaryHandler{summaryService: summaryService, cacheTTL: cacheTTL}
+}
+
+// GetAdminSummary handles retrieving the headline numbers of the admin dashboard
+func (h *SummaryHandler) GetAdminSummary(c *gin.Context) {
+	summary, err := h.summaryService.GetAdminSummary(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve summary")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Summary retrieved successfully", summary)
+}
+
+// GetBusinessReport handles retrieving the business report as JSON or, with
+// ?format=openmetrics, in the OpenMetrics text format
+func (h *SummaryHandler) GetBusinessReport(c *gin.Context) {
+	var query models.ReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	report, err := h.summaryService.GetBusinessReport(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve report")
+		return
+	}
+
+	// The report may be served from cache, so clients may keep it until it expires there
+	if maxAge := h.cacheTTL - time.Since(report.GeneratedAt); maxAge > 0 {
+		c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds())))
+	} else {
+		c.Header("Cache-Control", "no-cache")
+	}
+
+	if query.Format != export.FormatOpenMetrics {
+		response.Success(c, http.StatusOK, "Report retrieved successfully", report)
+		return
+	}
+	var buf bytes.Buffer
+	if err := export.WriteReportOpenMetrics(&buf, report); err != nil {
+		respondError(c, err, "Failed to retrieve report")
+		return
+	}
+	c.Data(http.StatusOK, export.ContentTypeOpenMetrics, buf.Bytes())
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+)
+
+// TagHandler handles tag endpoints
+type TagHandler struct {
+	tagService service.TagService
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{tagService: tagService}
+}
+
+// AssignTag handles attaching a tag to several items at once
+func (h *TagHandler) AssignTag(c *gin.Context) {
+	var req models.TagItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	result, err := h.tagService.AssignTag(c.Request.Context(), c.Param("tag"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to assign tag")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tag assigned successfully", result)
+}
+
+// UnassignTag handles removing a tag from several items at once
+func (h *TagHandler) UnassignTag(c *gin.Context) {
+	var req models.TagItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+
+	result, err := h.tagService.UnassignTag(c.Request.Context(), c.Param("tag"), &req)
+	if err != nil {
+		respondError(c, err, "Failed to unassign tag")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tag unassigned successfully", result)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+)
+
+// UserHandler handles user management endpoints
+type UserHandler struct {
+	userService service.UserService
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// SearchUsers handles finding users by username or email prefix
+func (h *UserHandler) SearchUsers(c *gin.Context) {
+	var query models.SearchUsersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, validator.FormatValidationError(err))
+		return
+	}
+	query.Normalize()
+
+	users, err := h.userService.SearchUsers(c.Request.Context(), &query)
+	if err != nil {
+		respondError(c, err, "Failed to search users")
+		return
+	}
+
+	response.SuccessWithMeta(c, http.StatusOK, "Users retrieved successfully", users, response.Pagination{
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	})
+}
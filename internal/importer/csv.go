@@ -0,0 +1,162 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+// fields lists the item fields a CSV column can be mapped to
+var fields = map[string]bool{
+	"name":        true,
+	"sku":         true,
+	"description": true,
+	"quantity":    true,
+	"unit":        true,
+	"price":       true,
+	"cost_price":  true,
+	"category":    true,
+}
+
+// requiredFields must be present in every import
+var requiredFields = []string{"name", "sku"}
+
+// ParseColumnMap parses a mapping such as "product_name:name,code:sku" from CSV column
+// names to item fields. Column names are matched case-insensitively.
+func ParseColumnMap(s string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		column, field, ok := strings.Cut(pair, ":")
+		column = strings.ToLower(strings.TrimSpace(column))
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || column == "" || field == "" {
+			return nil, fmt.Errorf("invalid column mapping %q, expected column:field", pair)
+		}
+		if !fields[field] {
+			return nil, fmt.Errorf("unknown field %q in column mapping", field)
+		}
+		mapping[column] = field
+	}
+	return mapping, nil
+}
+
+// ReadItems reads items from CSV with a header row. Each column is mapped to a field
+// through mapping, or else by its own name; columns matching neither are ignored.
+// Missing required columns are reported before any row is read.
+func ReadItems(r io.Reader, mapping map[string]string) ([]models.SyncItemRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		// Spreadsheet exports often start with a byte order mark
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		field, ok := mapping[name]
+		if !ok && fields[name] {
+			field = name
+		}
+		if field == "" {
+			continue
+		}
+		if _, dup := columns[field]; dup {
+			return nil, fmt.Errorf("more than one column maps to field %q", field)
+		}
+		columns[field] = i
+	}
+
+	var missing []string
+	for _, field := range requiredFields {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no column mapped to required field(s): %s", strings.Join(missing, ", "))
+	}
+
+	var items []models.SyncItemRequest
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", line, err)
+		}
+
+		item, err := parseRow(record, columns)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", line, err)
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("CSV file has no rows")
+	}
+	return items, nil
+}
+
+// parseRow converts a CSV record into a sync entry using the column positions. Fields
+// without a column, or whose cell is empty, are left out of the entry.
+func parseRow(record []string, columns map[string]int) (models.SyncItemRequest, error) {
+	value := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	text := func(field string) *string {
+		if v := value(field); v != "" {
+			return &v
+		}
+		return nil
+	}
+	number := func(field string) (*float64, error) {
+		v := value(field)
+		if v == "" {
+			return nil, nil
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", field, v)
+		}
+		return &n, nil
+	}
+
+	item := models.SyncItemRequest{
+		Name:        value("name"),
+		SKU:         value("sku"),
+		Description: text("description"),
+		Unit:        text("unit"),
+		Category:    text("category"),
+	}
+	var err error
+	if item.Quantity, err = number("quantity"); err != nil {
+		return item, err
+	}
+	if item.Price, err = number("price"); err != nil {
+		return item, err
+	}
+	if item.CostPrice, err = number("cost_price"); err != nil {
+		return item, err
+	}
+	return item, nil
+}
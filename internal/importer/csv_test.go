@@ -0,0 +1,40 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadItemsLeavesOutEmptyFields(t *testing.T) {
+	csv := "name,sku,quantity,price,cost_price,description\n" +
+		"Bolt,BOLT,12,0.5,0.2,Steel\n" +
+		"Nut,NUT,,,,\n"
+
+	items, err := ReadItems(strings.NewReader(csv), nil)
+	if err != nil {
+		t.Fatalf("ReadItems: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("read %d items, want 2", len(items))
+	}
+
+	bolt := items[0]
+	if bolt.Quantity == nil || *bolt.Quantity != 12 || bolt.Price == nil || *bolt.Price != 0.5 ||
+		bolt.CostPrice == nil || *bolt.CostPrice != 0.2 || bolt.Description == nil || *bolt.Description != "Steel" {
+		t.Errorf("first row = %+v, want every field set", bolt)
+	}
+	nut := items[1]
+	if nut.Quantity != nil || nut.Price != nil || nut.CostPrice != nil || nut.Description != nil {
+		t.Errorf("second row = %+v, want empty cells left out", nut)
+	}
+	if nut.Unit != nil || nut.Category != nil {
+		t.Errorf("second row = %+v, want fields without a column left out", nut)
+	}
+}
+
+func TestReadItemsInvalidNumber(t *testing.T) {
+	_, err := ReadItems(strings.NewReader("name,sku,price\nBolt,BOLT,cheap\n"), nil)
+	if err == nil || !strings.Contains(err.Error(), "row 2") {
+		t.Errorf("error = %v, want one naming row 2", err)
+	}
+}
@@ -0,0 +1,166 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/nielwyn/inventory-system/pkg/validator"
+)
+
+// ItemArray reads the "items" array of a JSON request body one entry at a time, so a
+// large body is never held in memory whole. Each entry is validated as it is read, and
+// errors name the entry by its index. Other members of the object are skipped. T is the
+// request type entries are decoded into.
+type ItemArray[T any] struct {
+	dec     *json.Decoder
+	index   int
+	done    bool
+	rewrite func([]byte) ([]byte, error)
+}
+
+// NewItemArray reads r up to the first entry of its "items" array
+func NewItemArray[T any](r io.Reader) (*ItemArray[T], error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, invalidJSON(err)
+		}
+		if key != "items" {
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return nil, invalidJSON(err)
+			}
+			continue
+		}
+		token, err := dec.Token()
+		if err != nil {
+			return nil, invalidJSON(err)
+		}
+		if token == nil {
+			break
+		}
+		if token != json.Delim('[') {
+			return nil, errors.New("Field 'Items' must be an array")
+		}
+		return &ItemArray[T]{dec: dec}, nil
+	}
+	return nil, errors.New("Field 'Items' is required")
+}
+
+// RewriteKeys makes Next pass each entry through rewrite, such as response.SnakeKeys,
+// before decoding it
+func (a *ItemArray[T]) RewriteKeys(rewrite func([]byte) ([]byte, error)) {
+	a.rewrite = rewrite
+}
+
+// Next reads up to n entries. It returns an empty batch once the array, and the object
+// around it, have been read to the end.
+func (a *ItemArray[T]) Next(n int) ([]T, error) {
+	if a.done {
+		return nil, nil
+	}
+	var batch []T
+	for len(batch) < n && a.dec.More() {
+		var item T
+		if err := a.decode(&item); err != nil {
+			return nil, fmt.Errorf("items[%d]: %w", a.index, invalidJSON(err))
+		}
+		if err := binding.Validator.ValidateStruct(&item); err != nil {
+			return nil, fmt.Errorf("items[%d]: %s", a.index, validator.FormatValidationError(err))
+		}
+		batch = append(batch, item)
+		a.index++
+	}
+	if len(batch) == n {
+		return batch, nil
+	}
+
+	if a.index == 0 {
+		return nil, errors.New("Field 'Items' must be at least 1")
+	}
+	if err := a.finish(); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// ReadAll reads every entry, failing as soon as there are more than max of them so an
+// oversized array is rejected without reading the rest of it
+func (a *ItemArray[T]) ReadAll(max int) ([]T, error) {
+	items, err := a.Next(max)
+	if err != nil {
+		return nil, err
+	}
+	extra, err := a.Next(1)
+	if err != nil {
+		return nil, err
+	}
+	if len(extra) > 0 {
+		return nil, fmt.Errorf("Field 'Items' must contain at most %d entries", max)
+	}
+	return items, nil
+}
+
+// decode reads the next entry into item
+func (a *ItemArray[T]) decode(item *T) error {
+	if a.rewrite == nil {
+		return a.dec.Decode(item)
+	}
+	var entry json.RawMessage
+	if err := a.dec.Decode(&entry); err != nil {
+		return err
+	}
+	entry, err := a.rewrite(entry)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(entry, item)
+}
+
+// finish reads the end of the array and the rest of the object
+func (a *ItemArray[T]) finish() error {
+	if err := expectDelim(a.dec, ']'); err != nil {
+		return err
+	}
+	for a.dec.More() {
+		if _, err := a.dec.Token(); err != nil {
+			return invalidJSON(err)
+		}
+		var skipped json.RawMessage
+		if err := a.dec.Decode(&skipped); err != nil {
+			return invalidJSON(err)
+		}
+	}
+	if err := expectDelim(a.dec, '}'); err != nil {
+		return err
+	}
+	a.done = true
+	return nil
+}
+
+// expectDelim reads the next token, which must be delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return invalidJSON(err)
+	}
+	if token != delim {
+		return fmt.Errorf("invalid JSON: expected %q", delim.String())
+	}
+	return nil
+}
+
+// invalidJSON describes a decoding error, including a body that ends early
+func invalidJSON(err error) error {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("invalid JSON: %w", err)
+}
@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type testEntry struct {
+	SKU      string `json:"sku" binding:"required"`
+	Quantity int    `json:"quantity" binding:"min=0"`
+}
+
+func TestItemArrayReadAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    int
+		wantErr string
+	}{
+		{"entries", `{"items":[{"sku":"A"},{"sku":"B","quantity":2}]}`, 2, ""},
+		{"other members skipped", `{"mode":"x","items":[{"sku":"A"}],"extra":{"a":[1]}}`, 1, ""},
+		{"at the limit", `{"items":[{"sku":"A"},{"sku":"B"},{"sku":"C"}]}`, 3, ""},
+		{"over the limit", `{"items":[{"sku":"A"},{"sku":"B"},{"sku":"C"},{"sku":"D"}]}`, 0, "at most 3 entries"},
+		{"missing items", `{"mode":"x"}`, 0, "Field 'Items' is required"},
+		{"null items", `{"items":null}`, 0, "Field 'Items' is required"},
+		{"items not an array", `{"items":{"sku":"A"}}`, 0, "must be an array"},
+		{"empty items", `{"items":[]}`, 0, "at least 1"},
+		{"not an object", `[{"sku":"A"}]`, 0, "invalid JSON"},
+		{"invalid entry", `{"items":[{"sku":"A"},{"quantity":1}]}`, 0, "items[1]: "},
+		{"malformed entry", `{"items":[{"sku":"A"},{"sku":}]}`, 0, "items[1]: invalid JSON"},
+		{"truncated body", `{"items":[{"sku":"A"}`, 0, "invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var items []testEntry
+			array, err := NewItemArray[testEntry](strings.NewReader(tt.body))
+			if err == nil {
+				items, err = array.ReadAll(3)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("reading items: %v", err)
+			}
+			if len(items) != tt.want {
+				t.Errorf("read %d items, want %d", len(items), tt.want)
+			}
+		})
+	}
+}
+
+func TestItemArrayBatches(t *testing.T) {
+	array, err := NewItemArray[testEntry](strings.NewReader(`{"items":[{"sku":"A"},{"sku":"B"},{"sku":"C"},{"sku":"D"},{"sku":"E"}]}`))
+	if err != nil {
+		t.Fatalf("NewItemArray: %v", err)
+	}
+	var sizes []int
+	for {
+		batch, err := array.Next(2)
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+		sizes = append(sizes, len(batch))
+	}
+	if fmt.Sprint(sizes) != "[2 2 1]" {
+		t.Errorf("batch sizes = %v, want [2 2 1]", sizes)
+	}
+}
+
+// generatedArray is a request body of n entries written as it is read, so the test
+// itself never holds the body in memory
+type generatedArray struct {
+	n, next int
+	pending []byte
+	read    int64
+}
+
+func (g *generatedArray) Read(p []byte) (int, error) {
+	if len(g.pending) == 0 {
+		switch {
+		case g.next == 0:
+			g.pending = []byte(`{"items":[`)
+		case g.next <= g.n:
+			g.pending = fmt.Appendf(nil, `{"sku":"SKU-%08d","quantity":%d,"padding":"%s"},`, g.next, g.next, strings.Repeat("x", 100))
+			if g.next == g.n {
+				g.pending = g.pending[:len(g.pending)-1]
+			}
+		case g.next == g.n+1:
+			g.pending = []byte(`]}`)
+		default:
+			return 0, io.EOF
+		}
+		g.next++
+	}
+	n := copy(p, g.pending)
+	g.pending = g.pending[n:]
+	g.read += int64(n)
+	return n, nil
+}
+
+func TestItemArrayBoundedMemory(t *testing.T) {
+	const entries, batchSize = 200000, 500
+	body := &generatedArray{n: entries}
+
+	heapInUse := func() uint64 {
+		var stats runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&stats)
+		return stats.HeapAlloc
+	}
+	before := heapInUse()
+
+	array, err := NewItemArray[testEntry](body)
+	if err != nil {
+		t.Fatalf("NewItemArray: %v", err)
+	}
+	var read int
+	var peak uint64
+	for batches := 0; ; batches++ {
+		batch, err := array.Next(batchSize)
+		if err != nil {
+			t.Fatalf("Next after %d entries: %v", read, err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+		if batches == 0 && body.read > 1<<20 {
+			t.Errorf("read %d bytes of the body for the first batch of %d entries", body.read, batchSize)
+		}
+		if batches%40 == 0 {
+			peak = max(peak, heapInUse())
+		}
+		read += len(batch)
+	}
+
+	if read != entries {
+		t.Errorf("read %d entries, want %d", read, entries)
+	}
+	// the body is about 30MB; holding it, or every entry, would take far more than this
+	const bound = 4 << 20
+	if body.read < 20<<20 {
+		t.Fatalf("body was only %d bytes, too small to tell", body.read)
+	}
+	if peak > before && peak-before > bound {
+		t.Errorf("heap grew by %d bytes reading a %d-byte body, want at most %d", peak-before, body.read, bound)
+	}
+}
+
+func TestItemArrayRewriteKeys(t *testing.T) {
+	type countEntry struct {
+		SKU             string `json:"sku" binding:"required"`
+		CountedQuantity int    `json:"counted_quantity"`
+	}
+	array, err := NewItemArray[countEntry](strings.NewReader(`{"items":[{"sku":"A","countedQuantity":3},{"sku":"B","counted_quantity":4}]}`))
+	if err != nil {
+		t.Fatalf("NewItemArray: %v", err)
+	}
+	array.RewriteKeys(func(entry []byte) ([]byte, error) {
+		return []byte(strings.ReplaceAll(string(entry), "countedQuantity", "counted_quantity")), nil
+	})
+	items, err := array.ReadAll(10)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if fmt.Sprint(items) != "[{A 3} {B 4}]" {
+		t.Errorf("items = %v, want [{A 3} {B 4}]", items)
+	}
+}
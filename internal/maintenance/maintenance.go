@@ -0,0 +1,36 @@
+package maintenance
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Mode tracks whether the service is in maintenance mode.
+// It is safe for concurrent use and can be switched at runtime.
+type Mode struct {
+	enabled    atomic.Bool
+	retryAfter time.Duration
+}
+
+// New creates a maintenance mode switch with its initial state and the
+// Retry-After duration advertised to rejected clients
+func New(enabled bool, retryAfter time.Duration) *Mode {
+	m := &Mode{retryAfter: retryAfter}
+	m.enabled.Store(enabled)
+	return m
+}
+
+// Enabled reports whether maintenance mode is on
+func (m *Mode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// Set turns maintenance mode on or off
+func (m *Mode) Set(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// RetryAfter returns how long clients are told to wait before retrying a write
+func (m *Mode) RetryAfter() time.Duration {
+	return m.retryAfter
+}
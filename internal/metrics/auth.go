@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Result label values
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+var (
+	authLogins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_login_total",
+		Help: "Login attempts by result.",
+	}, []string{"result"})
+
+	authRegistrations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_register_total",
+		Help: "Registration attempts by result.",
+	}, []string{"result"})
+
+	authTokenValidations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_token_validation_total",
+		Help: "Bearer token validations by result.",
+	}, []string{"result"})
+)
+
+// RecordLogin counts a login attempt that ended with err
+func RecordLogin(err error) {
+	authLogins.WithLabelValues(result(err)).Inc()
+}
+
+// RecordRegister counts a registration attempt that ended with err
+func RecordRegister(err error) {
+	authRegistrations.WithLabelValues(result(err)).Inc()
+}
+
+// RecordTokenValidation counts a token validation that ended with err
+func RecordTokenValidation(err error) {
+	authTokenValidations.WithLabelValues(result(err)).Inc()
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
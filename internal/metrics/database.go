@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	dbCircuitState = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "db_circuit_breaker_state",
+		Help: "Database circuit breaker state: 0 closed, 1 half-open, 2 open.",
+	})
+
+	dbCircuitRejections = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "db_circuit_breaker_rejected_total",
+		Help: "Database queries rejected while the circuit breaker was open.",
+	})
+)
+
+// SetCircuitState records the database circuit breaker state
+func SetCircuitState(state int) {
+	dbCircuitState.Set(float64(state))
+}
+
+// RecordCircuitRejection counts a query rejected by the open circuit breaker
+func RecordCircuitRejection() {
+	dbCircuitRejections.Inc()
+}
@@ -0,0 +1,16 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var panics = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "panics_total",
+	Help: "Panics recovered while handling HTTP requests.",
+})
+
+// RecordPanic counts a recovered panic
+func RecordPanic() {
+	panics.Inc()
+}
@@ -0,0 +1,16 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var inventoryValue = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "inventory_value",
+	Help: "Total value of active stock (sum of price * quantity), as of the last refresh.",
+})
+
+// SetInventoryValue records the total value of active stock
+func SetInventoryValue(value float64) {
+	inventoryValue.Set(value)
+}
@@ -1,17 +1,24 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/audit"
+	"github.com/nielwyn/inventory-system/internal/metrics"
+	"github.com/nielwyn/inventory-system/internal/repository"
 	"github.com/nielwyn/inventory-system/internal/service"
 	"github.com/nielwyn/inventory-system/pkg/logger"
 	"github.com/nielwyn/inventory-system/pkg/response"
 	"go.uber.org/zap"
 )
 
-// Auth middleware validates JWT tokens
-func Auth(authService service.AuthService) gin.HandlerFunc {
+// Auth middleware validates JWT tokens sent as "Authorization: <scheme> <token>".
+// The scheme must be one of schemes, compared case-insensitively.
+func Auth(authService service.AuthService, schemes []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -21,36 +28,140 @@ func Auth(authService service.AuthService) gin.HandlerFunc {
 			return
 		}
 
-		// Extract token (format: "Bearer <token>")
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
+		// Extract token (format: "<scheme> <token>")
+		scheme, tokenString, ok := strings.Cut(authHeader, " ")
+		tokenString = strings.TrimSpace(tokenString)
+		if !ok || tokenString == "" || !acceptedScheme(schemes, scheme) {
 			response.Error(c, 401, "Invalid authorization header format")
 			c.Abort()
 			return
 		}
 
-		tokenString := parts[1]
-
 		// Validate token
 		token, err := authService.ValidateToken(tokenString)
 		if err != nil {
-			logger.Error("Token validation failed", zap.Error(err))
+			metrics.RecordTokenValidation(err)
+			audit.AuthEvent(c, audit.EventTokenValidation, "", audit.OutcomeFailure, err)
 			response.Error(c, 401, "Invalid or expired token")
 			c.Abort()
 			return
 		}
 
+		// Only access tokens may authenticate requests; a refresh token is rejected here
+		if tokenType := authService.GetTokenType(token); tokenType != service.TokenTypeAccess {
+			err := fmt.Errorf("token type %q cannot be used for requests", tokenType)
+			metrics.RecordTokenValidation(err)
+			audit.AuthEvent(c, audit.EventTokenValidation, "", audit.OutcomeFailure, err)
+			response.Error(c, 401, "Invalid token type")
+			c.Abort()
+			return
+		}
+
 		// Extract user ID from token
 		userID, err := authService.GetUserFromToken(token)
 		if err != nil {
-			logger.Error("Failed to extract user from token", zap.Error(err))
+			metrics.RecordTokenValidation(err)
+			audit.AuthEvent(c, audit.EventTokenValidation, "", audit.OutcomeFailure, err)
 			response.Error(c, 401, "Invalid token claims")
 			c.Abort()
 			return
 		}
 
-		// Set user ID in context
+		// A revoked session's token is rejected even though it has not expired
+		sessionID, err := authService.CheckSession(c.Request.Context(), token, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrDatabaseUnavailable):
+				response.ErrorWithCode(c, http.StatusServiceUnavailable, "database_unavailable", "Database is unavailable; try again later")
+				c.Abort()
+				return
+			case !errors.Is(err, service.ErrSessionRevoked):
+				logger.Error("Failed to check session", zap.Error(err))
+				response.Error(c, http.StatusInternalServerError, "Internal server error")
+				c.Abort()
+				return
+			}
+			metrics.RecordTokenValidation(err)
+			audit.AuthEvent(c, audit.EventTokenValidation, "", audit.OutcomeFailure, err)
+			response.Error(c, 401, "Session has been revoked")
+			c.Abort()
+			return
+		}
+
+		metrics.RecordTokenValidation(nil)
+
+		// Set user ID, role and session in context. user_id is the effective user; with an
+		// impersonation token impersonator_id is the admin behind it, and the request
+		// context carries it so the stock movements and price changes made record it.
 		c.Set("user_id", userID)
+		c.Set("role", authService.GetRoleFromToken(token))
+		c.Set("session_id", sessionID)
+		impersonatorID, impersonated := authService.GetImpersonatorFromToken(token)
+		if impersonated {
+			c.Set("impersonator_id", impersonatorID)
+			c.Request = c.Request.WithContext(repository.ContextWithImpersonator(c.Request.Context(), impersonatorID))
+		}
+		c.Next()
+
+		if impersonated {
+			audit.ImpersonatedRequest(c)
+		}
+	}
+}
+
+// OptionalAuth lets requests without an Authorization header through anonymously, with no
+// user in the context. A request that sends one is authenticated like Auth, so an invalid
+// or expired token is still rejected rather than silently treated as anonymous.
+func OptionalAuth(authService service.AuthService, schemes []string) gin.HandlerFunc {
+	auth := Auth(authService, schemes)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		auth(c)
+	}
+}
+
+// RouteAuth authenticates requests like Auth, except that GET and HEAD requests to
+// publicRoutes, keyed by the route path as registered, are handled like OptionalAuth.
+// Writes always need a token.
+func RouteAuth(authService service.AuthService, schemes []string, publicRoutes []string) gin.HandlerFunc {
+	auth := Auth(authService, schemes)
+	optionalAuth := OptionalAuth(authService, schemes)
+	public := make(map[string]bool, len(publicRoutes))
+	for _, route := range publicRoutes {
+		public[route] = true
+	}
+	return func(c *gin.Context) {
+		read := c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead
+		if read && public[c.FullPath()] {
+			optionalAuth(c)
+			return
+		}
+		auth(c)
+	}
+}
+
+// acceptedScheme reports whether scheme is one of schemes, ignoring case
+func acceptedScheme(schemes []string, scheme string) bool {
+	for _, accepted := range schemes {
+		if strings.EqualFold(accepted, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRole middleware rejects requests from users without the given role.
+// It must run after Auth.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != role {
+			response.Error(c, 403, "Insufficient permissions")
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
@@ -0,0 +1,272 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"go.uber.org/zap/zapcore"
+)
+
+const testJWTSecret = "test-secret"
+
+// memSessions is an in-memory SessionRepository
+type memSessions map[string]*models.Session
+
+func (m memSessions) Create(_ context.Context, session *models.Session) error {
+	m[session.ID] = session
+	return nil
+}
+
+func (m memSessions) FindByID(_ context.Context, id string) (*models.Session, error) {
+	return m[id], nil
+}
+
+func (m memSessions) ListActive(context.Context, uint, time.Time) ([]models.Session, error) {
+	return nil, nil
+}
+
+func (m memSessions) Touch(context.Context, string, time.Time, time.Duration) error {
+	return nil
+}
+
+func (m memSessions) Revoke(_ context.Context, userID uint, id string, now time.Time) (bool, error) {
+	session := m[id]
+	if session == nil || session.UserID != userID {
+		return false, nil
+	}
+	session.RevokedAt = &now
+	return true, nil
+}
+
+// newTestAuthService returns an auth service validating tokens signed with testJWTSecret
+func newTestAuthService(sessions memSessions, leeway time.Duration) service.AuthService {
+	return service.NewAuthService(nil, sessions, service.AuthOptions{
+		JWTSecret:      testJWTSecret,
+		JWTExpiryHours: 1,
+		JWTLeeway:      leeway,
+	})
+}
+
+// accessClaims returns the claims of an access token for userID, valid for an hour
+func accessClaims(userID uint, role string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":        strconv.FormatUint(uint64(userID), 10),
+		"user_id":    userID,
+		"role":       role,
+		"token_type": service.TokenTypeAccess,
+		"exp":        time.Now().Add(time.Hour).Unix(),
+		"iat":        time.Now().Unix(),
+	}
+}
+
+// signToken signs claims with testJWTSecret
+func signToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return token
+}
+
+// newAuthRouter serves the item routes behind mw, answering with the authenticated user
+func newAuthRouter(mw gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	inventory := router.Group("/api/v1/inventory")
+	inventory.Use(mw)
+	whoami := func(c *gin.Context) {
+		userID, ok := c.Get("user_id")
+		if !ok {
+			c.JSON(http.StatusOK, gin.H{"anonymous": true})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"user_id": userID, "role": c.GetString("role")})
+	}
+	inventory.GET("/items", whoami)
+	inventory.POST("/items", whoami)
+	inventory.GET("/items/:id", whoami)
+	inventory.GET("/items/export", whoami)
+	return router
+}
+
+func serve(router http.Handler, method, path, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRouteAuth(t *testing.T) {
+	authService := newTestAuthService(memSessions{}, 0)
+	router := newAuthRouter(RouteAuth(authService, []string{"Bearer"}, []string{
+		"/api/v1/inventory/items",
+		"/api/v1/inventory/items/:id",
+	}))
+	valid := "Bearer " + signToken(t, accessClaims(7, models.RoleUser))
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		authorization string
+		wantStatus    int
+		wantBody      string
+	}{
+		{"public list without token", http.MethodGet, "/api/v1/inventory/items", "", http.StatusOK, `{"anonymous":true}`},
+		{"public item without token", http.MethodGet, "/api/v1/inventory/items/3", "", http.StatusOK, `{"anonymous":true}`},
+		{"public list with token", http.MethodGet, "/api/v1/inventory/items", valid, http.StatusOK, `{"role":"user","user_id":7}`},
+		{"public list with invalid token", http.MethodGet, "/api/v1/inventory/items", "Bearer not-a-jwt", http.StatusUnauthorized, ""},
+		{"public list with unknown scheme", http.MethodGet, "/api/v1/inventory/items", "Basic abc", http.StatusUnauthorized, ""},
+		{"write to public route without token", http.MethodPost, "/api/v1/inventory/items", "", http.StatusUnauthorized, ""},
+		{"write to public route with token", http.MethodPost, "/api/v1/inventory/items", valid, http.StatusOK, `{"role":"user","user_id":7}`},
+		{"private route without token", http.MethodGet, "/api/v1/inventory/items/export", "", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, tt.method, tt.path, tt.authorization)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("body = %s, want %s", w.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestRouteAuthWithoutPublicRoutes(t *testing.T) {
+	router := newAuthRouter(RouteAuth(newTestAuthService(memSessions{}, 0), []string{"Bearer"}, nil))
+
+	if w := serve(router, http.MethodGet, "/api/v1/inventory/items", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestOptionalAuth(t *testing.T) {
+	router := newAuthRouter(OptionalAuth(newTestAuthService(memSessions{}, 0), []string{"Bearer"}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantBody      string
+	}{
+		{"no header", "", http.StatusOK, `{"anonymous":true}`},
+		{"valid token", "Bearer " + signToken(t, accessClaims(4, models.RoleAdmin)), http.StatusOK, `{"role":"admin","user_id":4}`},
+		{"invalid token", "Bearer garbage", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodPost, "/api/v1/inventory/items", tt.authorization)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("body = %s, want %s", w.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestAuthSchemes(t *testing.T) {
+	token := signToken(t, accessClaims(7, models.RoleUser))
+
+	tests := []struct {
+		name          string
+		schemes       []string
+		authorization string
+		wantStatus    int
+	}{
+		{"bearer", []string{"Bearer"}, "Bearer " + token, http.StatusOK},
+		{"bearer in lower case", []string{"Bearer"}, "bearer " + token, http.StatusOK},
+		{"token not configured", []string{"Bearer"}, "Token " + token, http.StatusUnauthorized},
+		{"token", []string{"Bearer", "Token"}, "Token " + token, http.StatusOK},
+		{"token in upper case", []string{"Bearer", "Token"}, "TOKEN " + token, http.StatusOK},
+		{"bearer alongside token", []string{"Bearer", "Token"}, "Bearer " + token, http.StatusOK},
+		{"unknown scheme", []string{"Bearer", "Token"}, "Basic " + token, http.StatusUnauthorized},
+		{"scheme without a token", []string{"Bearer"}, "Bearer ", http.StatusUnauthorized},
+		{"token without a scheme", []string{"Bearer"}, token, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAuthRouter(Auth(newTestAuthService(memSessions{}, 0), tt.schemes))
+			w := serve(router, http.MethodGet, "/api/v1/inventory/items", tt.authorization)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+}
+
+func TestAuthImpersonation(t *testing.T) {
+	logs := observeLogs(t, zapcore.InfoLevel)
+	authService := newTestAuthService(memSessions{}, 0)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Auth(authService, []string{"Bearer"}))
+	identity := func(c *gin.Context) {
+		_, impersonated := c.Get("impersonator_id")
+		recordedID, _ := repository.ImpersonatorFromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{
+			"user_id":               c.GetUint("user_id"),
+			"impersonated":          impersonated,
+			"recorded_impersonator": recordedID,
+		})
+	}
+	router.GET("/items", identity)
+	router.POST("/items", identity)
+
+	impersonation := accessClaims(7, models.RoleUser)
+	impersonation["impersonated_by"] = "1"
+
+	tests := []struct {
+		name      string
+		method    string
+		claims    jwt.MapClaims
+		wantBody  string
+		wantAudit string // the actor of the audit entry, or empty for none
+	}{
+		{"impersonated write", http.MethodPost, impersonation, `{"impersonated":true,"recorded_impersonator":1,"user_id":7}`, "user 1 acting as user 7"},
+		{"impersonated read", http.MethodGet, impersonation, `{"impersonated":true,"recorded_impersonator":1,"user_id":7}`, ""},
+		{"own write", http.MethodPost, accessClaims(7, models.RoleUser), `{"impersonated":false,"recorded_impersonator":0,"user_id":7}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.TakeAll()
+			w := serve(router, tt.method, "/items", "Bearer "+signToken(t, tt.claims))
+			if w.Code != http.StatusOK || w.Body.String() != tt.wantBody {
+				t.Fatalf("response %d %s, want 200 %s", w.Code, w.Body, tt.wantBody)
+			}
+
+			entries := logs.FilterMessage("Impersonated request").All()
+			if tt.wantAudit == "" {
+				if len(entries) != 0 {
+					t.Errorf("logged %d impersonation entries, want none", len(entries))
+				}
+				return
+			}
+			if len(entries) != 1 {
+				t.Fatalf("logged %d impersonation entries, want 1", len(entries))
+			}
+			fields := entries[0].ContextMap()
+			if fields["actor"] != tt.wantAudit || fmt.Sprint(fields["impersonator_id"]) != "1" {
+				t.Errorf("audit entry %v, want actor %q and impersonator_id 1", fields, tt.wantAudit)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedValue replaces the value of every redacted field in logged bodies
+const redactedValue = "[REDACTED]"
+
+// notCaptured is logged in place of a body larger than the cap, which cannot be redacted
+const notCaptured = "[not captured: exceeds LOG_BODY_MAX_BYTES]"
+
+// BodyLogOptions configures request and response body logging
+type BodyLogOptions struct {
+	// MaxBytes caps how much of each body is logged
+	MaxBytes int
+
+	// RedactFields are JSON keys, matched case-insensitively at any depth, whose
+	// values are replaced before logging. Any key containing "password", such as
+	// "new_password" or "currentPassword", is always redacted.
+	RedactFields []string
+}
+
+// BodyLogger middleware logs request and response bodies at debug level.
+// Bodies are only logged when they are JSON, after redaction; anything else is
+// omitted since it cannot be redacted, as is a body larger than MaxBytes. Only that
+// much of a request body is held in memory; the rest streams through to the handler.
+// Headers are never logged, so the Authorization header cannot leak. When the logger
+// is above debug level the middleware does nothing.
+func BodyLogger(opts BodyLogOptions) gin.HandlerFunc {
+	redact := make(map[string]bool, len(opts.RedactFields))
+	for _, field := range opts.RedactFields {
+		redact[strings.ToLower(field)] = true
+	}
+
+	return func(c *gin.Context) {
+		if !logger.Get().Core().Enabled(zapcore.DebugLevel) {
+			c.Next()
+			return
+		}
+
+		// Only the first MaxBytes+1 bytes of the request body are read here; the handler
+		// reads them back followed by the rest, which streams through uncaptured
+		var requestBody []byte
+		requestTruncated := false
+		if body := c.Request.Body; body != nil {
+			var head io.Reader = body
+			if opts.MaxBytes > 0 {
+				head = io.LimitReader(body, int64(opts.MaxBytes)+1)
+			}
+			captured, err := io.ReadAll(head)
+			c.Request.Body = replayBody{Reader: io.MultiReader(bytes.NewReader(captured), body), Closer: body}
+			if err != nil {
+				c.Next()
+				return
+			}
+			requestBody = captured
+			requestTruncated = opts.MaxBytes > 0 && len(captured) > opts.MaxBytes
+		}
+
+		writer := &teeWriter{ResponseWriter: c.Writer, limit: opts.MaxBytes}
+		c.Writer = writer
+
+		c.Next()
+
+		requestLog, responseLog := notCaptured, notCaptured
+		if !requestTruncated {
+			requestLog = formatBody(requestBody, redact, opts.MaxBytes)
+		}
+		if !writer.truncated {
+			responseLog = formatBody(writer.body.Bytes(), redact, opts.MaxBytes)
+		}
+		logger.Debug("HTTP Body",
+			zap.String("request_id", c.GetString("request_id")),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("request_body", requestLog),
+			zap.String("response_body", responseLog),
+		)
+	}
+}
+
+// formatBody redacts a JSON body and caps it at maxBytes
+func formatBody(body []byte, redact map[string]bool, maxBytes int) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "[non-JSON body omitted]"
+	}
+	redactValue(value, redact)
+
+	out, err := json.Marshal(value)
+	if err != nil {
+		return "[body omitted]"
+	}
+	if maxBytes > 0 && len(out) > maxBytes {
+		return string(out[:maxBytes]) + "...[truncated]"
+	}
+	return string(out)
+}
+
+// redactValue replaces, in place, the values of redacted keys anywhere in a decoded JSON value
+func redactValue(value interface{}, redact map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if lower := strings.ToLower(key); redact[lower] || strings.Contains(lower, "password") {
+				v[key] = redactedValue
+				continue
+			}
+			redactValue(child, redact)
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactValue(child, redact)
+		}
+	}
+}
+
+// replayBody reads the captured start of a request body followed by the rest of it,
+// and closes the original body
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// teeWriter copies the response body into a buffer, up to limit bytes, as it is written.
+// A response larger than the limit is marked truncated since it can no longer be redacted.
+type teeWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *teeWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *teeWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *teeWriter) capture(data []byte) {
+	if w.truncated {
+		return
+	}
+	if w.limit > 0 && w.body.Len()+len(data) > w.limit {
+		w.truncated = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observeLogs captures log entries at level and above until the test ends
+func observeLogs(t *testing.T, level zapcore.Level) *observer.ObservedLogs {
+	t.Helper()
+	core, logs := observer.New(level)
+	t.Cleanup(logger.Replace(zap.New(core)))
+	return logs
+}
+
+func TestFormatBodyRedaction(t *testing.T) {
+	redact := map[string]bool{"token": true, "api_key": true}
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"password", `{"username":"a","password":"p"}`, `{"password":"[REDACTED]","username":"a"}`},
+		{"change password", `{"current_password":"old","new_password":"new"}`, `{"current_password":"[REDACTED]","new_password":"[REDACTED]"}`},
+		{"camel case", `{"currentPassword":"old","newPassword":"new"}`, `{"currentPassword":"[REDACTED]","newPassword":"[REDACTED]"}`},
+		{"upper case", `{"PASSWORD":"p"}`, `{"PASSWORD":"[REDACTED]"}`},
+		{"configured field", `{"Token":"t","api_key":"k","name":"n"}`, `{"Token":"[REDACTED]","api_key":"[REDACTED]","name":"n"}`},
+		{"nested", `{"data":[{"password":"p","id":1}]}`, `{"data":[{"id":1,"password":"[REDACTED]"}]}`},
+		{"not JSON", `password=p`, "[non-JSON body omitted]"},
+		{"empty", ``, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatBody([]byte(tt.body), redact, 0); got != tt.want {
+				t.Errorf("formatBody(%s) = %s, want %s", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBodyLoggerRedactsPasswords(t *testing.T) {
+	logs := observeLogs(t, zapcore.DebugLevel)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLogger(BodyLogOptions{MaxBytes: 4096}))
+	router.PUT("/api/v1/auth/me/password", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+
+	for _, body := range []string{
+		`{"current_password":"hunter2","new_password":"correct-horse"}`,
+		`{"currentPassword":"hunter2","newPassword":"correct-horse"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/auth/me/password", strings.NewReader(body))
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	entries := logs.FilterMessage("HTTP Body").All()
+	if len(entries) != 2 {
+		t.Fatalf("logged %d body entries, want 2", len(entries))
+	}
+	for _, entry := range entries {
+		logged := entry.ContextMap()["request_body"].(string)
+		if strings.Contains(logged, "hunter2") || strings.Contains(logged, "correct-horse") {
+			t.Errorf("request body logged with a password: %s", logged)
+		}
+	}
+}
+
+// countingReader counts the bytes read from it
+type countingReader struct {
+	r    io.Reader
+	read int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read += n
+	return n, err
+}
+
+func TestBodyLoggerCapsRequestBody(t *testing.T) {
+	logs := observeLogs(t, zapcore.DebugLevel)
+	const maxBytes = 64
+
+	tests := []struct {
+		name    string
+		body    string
+		wantLog string
+	}{
+		{"within the cap", `{"name":"Widget"}`, `{"name":"Widget"}`},
+		{"over the cap", `{"name":"` + strings.Repeat("x", 10*maxBytes) + `"}`, notCaptured},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.TakeAll()
+			source := &countingReader{r: strings.NewReader(tt.body)}
+			var handlerRead string
+			var readBeforeHandler int
+
+			router := gin.New()
+			router.Use(BodyLogger(BodyLogOptions{MaxBytes: maxBytes}))
+			router.POST("/items", func(c *gin.Context) {
+				readBeforeHandler = source.read
+				body, err := io.ReadAll(c.Request.Body)
+				if err != nil {
+					t.Errorf("reading body in handler: %v", err)
+				}
+				handlerRead = string(body)
+				c.Status(http.StatusNoContent)
+			})
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/items", source))
+
+			if handlerRead != tt.body {
+				t.Errorf("handler read %d bytes, want the whole %d-byte body", len(handlerRead), len(tt.body))
+			}
+			if readBeforeHandler > maxBytes+1 {
+				t.Errorf("middleware read %d bytes before the handler, want at most %d", readBeforeHandler, maxBytes+1)
+			}
+			entries := logs.FilterMessage("HTTP Body").All()
+			if len(entries) != 1 {
+				t.Fatalf("logged %d body entries, want 1", len(entries))
+			}
+			if logged := entries[0].ContextMap()["request_body"]; logged != tt.wantLog {
+				t.Errorf("request_body = %v, want %s", logged, tt.wantLog)
+			}
+		})
+	}
+}
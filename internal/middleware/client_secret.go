@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/pkg/response"
+)
+
+// ClientSecretHeader carries the shared secret of a trusted service
+const ClientSecretHeader = "X-Client-Secret"
+
+// ClientSecret middleware admits only callers presenting the shared secret in the
+// X-Client-Secret header. It guards service-to-service endpoints such as token
+// introspection, so they cannot be used by anyone to probe tokens.
+func ClientSecret(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(ClientSecretHeader)
+		if provided == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(secret)) != 1 {
+			response.Error(c, 401, "Invalid client secret")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/pkg/response"
+)
+
+// SnakeCaseRequests middleware rewrites the keys of JSON request bodies to snake_case, so
+// clients using camelCase keys (pageSize, countedQuantity) bind to the snake_case struct
+// tags. snake_case keys pass through unchanged. Bodies that are not valid JSON are left
+// alone for binding to reject. Rewriting reads the whole body, so bodies of the streamed
+// routes, keyed by the route path as registered, are left for their handler to convert as
+// it reads them.
+func SnakeCaseRequests(streamedRoutes []string) gin.HandlerFunc {
+	streamed := make(map[string]bool, len(streamedRoutes))
+	for _, route := range streamedRoutes {
+		streamed[route] = true
+	}
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.ContentType() != gin.MIMEJSON || streamed[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Failed to read request body")
+			c.Abort()
+			return
+		}
+		if converted, err := response.SnakeKeys(body); err == nil {
+			body = converted
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Request.ContentLength = int64(len(body))
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSnakeCaseRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(SnakeCaseRequests([]string{"/items/sync"}))
+	echo := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	}
+	router.POST("/items", echo)
+	router.PUT("/items/sync", echo)
+
+	tests := []struct {
+		name, method, path, contentType, body, want string
+	}{
+		{"camelCase keys", http.MethodPost, "/items", gin.MIMEJSON, `{"unitPrice":2,"sku":"A"}`, `{"sku":"A","unit_price":2}`},
+		{"not JSON", http.MethodPost, "/items", gin.MIMEPlain, `{"unitPrice":2}`, `{"unitPrice":2}`},
+		{"invalid JSON", http.MethodPost, "/items", gin.MIMEJSON, `{"unitPrice":`, `{"unitPrice":`},
+		{"streamed route", http.MethodPut, "/items/sync", gin.MIMEJSON, `{"items":[{"unitPrice":2}]}`, `{"items":[{"unitPrice":2}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Body.String() != tt.want {
+				t.Errorf("handler read %s, want %s", w.Body.String(), tt.want)
+			}
+		})
+	}
+}
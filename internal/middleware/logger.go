@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"math/rand"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -8,8 +10,22 @@ import (
 	"go.uber.org/zap"
 )
 
-// Logger middleware logs HTTP requests
-func Logger() gin.HandlerFunc {
+// LoggerOptions configures which requests the Logger middleware logs
+type LoggerOptions struct {
+	// ExcludePaths are paths, such as health checks, whose successful requests are not logged
+	ExcludePaths []string
+	// SuccessSampleRate is the fraction of other successful requests logged, from 0 to 1
+	SuccessSampleRate float64
+}
+
+// Logger middleware logs HTTP requests. Requests answered with 4xx or 5xx are always
+// logged; successful ones are skipped on excluded paths and sampled elsewhere.
+func Logger(opts LoggerOptions) gin.HandlerFunc {
+	excluded := make(map[string]bool, len(opts.ExcludePaths))
+	for _, path := range opts.ExcludePaths {
+		excluded[path] = true
+	}
+
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
@@ -24,8 +40,15 @@ func Logger() gin.HandlerFunc {
 		// Get status code
 		statusCode := c.Writer.Status()
 
+		if statusCode < http.StatusBadRequest && len(c.Errors) == 0 {
+			if excluded[path] || (opts.SuccessSampleRate < 1 && rand.Float64() >= opts.SuccessSampleRate) {
+				return
+			}
+		}
+
 		// Log request
 		logger.Info("HTTP Request",
+			zap.String("request_id", c.GetString("request_id")),
 			zap.String("method", c.Request.Method),
 			zap.String("path", path),
 			zap.String("query", query),
@@ -38,7 +61,7 @@ func Logger() gin.HandlerFunc {
 		// Log errors if any
 		if len(c.Errors) > 0 {
 			for _, e := range c.Errors {
-				logger.Error("Request error", zap.Error(e.Err))
+				logger.Error("Request error", zap.String("request_id", c.GetString("request_id")), zap.Error(e.Err))
 			}
 		}
 	}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+// newLoggedRouter serves paths answering with the status in their name through Logger
+func newLoggedRouter(opts LoggerOptions) *gin.Engine {
+	router := gin.New()
+	router.Use(Logger(opts))
+	for _, path := range []string{"/health", "/metrics", "/items"} {
+		router.GET(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+	router.GET("/health/deep", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/failing", func(c *gin.Context) { c.Status(http.StatusServiceUnavailable) })
+	return router
+}
+
+func TestLoggerExcludedPaths(t *testing.T) {
+	logs := observeLogs(t, zapcore.InfoLevel)
+	router := newLoggedRouter(LoggerOptions{ExcludePaths: []string{"/health", "/metrics"}, SuccessSampleRate: 1})
+
+	tests := []struct {
+		path    string
+		wantLog bool
+	}{
+		{"/health", false},
+		{"/metrics", false},
+		{"/health?verbose=1", false},
+		{"/health/deep", true}, // exclusions match whole paths
+		{"/items", true},
+		{"/missing", true}, // a 404 is logged even though nothing is routed
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			logs.TakeAll()
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if got := logs.FilterMessage("HTTP Request").Len(); (got > 0) != tt.wantLog {
+				t.Errorf("logged %d lines, want a line %v", got, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestLoggerExcludedPathErrors(t *testing.T) {
+	logs := observeLogs(t, zapcore.InfoLevel)
+	router := newLoggedRouter(LoggerOptions{ExcludePaths: []string{"/failing"}, SuccessSampleRate: 1})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/failing", nil))
+	if got := logs.FilterMessage("HTTP Request").Len(); got != 1 {
+		t.Errorf("logged %d lines for a failed request on an excluded path, want 1", got)
+	}
+}
+
+func TestLoggerSampling(t *testing.T) {
+	const requests = 20
+	tests := []struct {
+		rate      float64
+		path      string
+		wantLines int
+	}{
+		{0, "/items", 0},
+		{1, "/items", requests},
+		{0, "/failing", requests}, // errors are never sampled away
+	}
+	for _, tt := range tests {
+		logs := observeLogs(t, zapcore.InfoLevel)
+		router := newLoggedRouter(LoggerOptions{SuccessSampleRate: tt.rate})
+		for i := 0; i < requests; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+		}
+		if got := logs.FilterMessage("HTTP Request").Len(); got != tt.wantLines {
+			t.Errorf("rate %v on %s: logged %d lines, want %d", tt.rate, tt.path, got, tt.wantLines)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/maintenance"
+	"github.com/nielwyn/inventory-system/pkg/response"
+)
+
+// Maintenance middleware rejects mutating requests with 503 while maintenance mode is on.
+// Reads (GET, HEAD, OPTIONS) are always let through.
+func Maintenance(mode *maintenance.Mode) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		if mode.Enabled() {
+			c.Header("Retry-After", strconv.Itoa(int(mode.RetryAfter().Seconds())))
+			response.ErrorWithCode(c, http.StatusServiceUnavailable, "maintenance", "Service is in maintenance mode; writes are temporarily disabled")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/pkg/response"
+)
+
+// RateLimitOptions configures the RateLimit middleware
+type RateLimitOptions struct {
+	// Requests is how many requests a client may make per Window
+	Requests int
+	Window   time.Duration
+}
+
+// RateLimit middleware limits each client IP to a fixed number of requests per window.
+// Every response carries X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset
+// (the Unix time the window resets) so clients can slow down before they are rejected.
+// Requests over the limit get 429 with a Retry-After header.
+func RateLimit(opts RateLimitOptions) gin.HandlerFunc {
+	limiter := &rateLimiter{
+		limit:   opts.Requests,
+		window:  opts.Window,
+		clients: make(map[string]*rateWindow),
+	}
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		remaining, reset, allowed := limiter.take(c.ClientIP(), now)
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(opts.Requests))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds()+1)))
+			response.ErrorWithCode(c, http.StatusTooManyRequests, "rate_limited", "Too many requests; retry after the rate limit resets")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateWindow counts a client's requests in the current window
+type rateWindow struct {
+	count int
+	reset time.Time
+}
+
+// rateLimiter holds the request counts of every client seen in the current window
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	clients   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+// take records a request from client and reports how many requests it has left,
+// when its window resets and whether the request is within the limit
+func (l *rateLimiter) take(client string, now time.Time) (remaining int, reset time.Time, allowed bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop clients whose window has ended, at most once per window
+	if now.Sub(l.lastSweep) >= l.window {
+		for key, w := range l.clients {
+			if !now.Before(w.reset) {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.clients[client]
+	if !ok || !now.Before(w.reset) {
+		w = &rateWindow{reset: now.Add(l.window)}
+		l.clients[client] = w
+	}
+	if w.count >= l.limit {
+		return 0, w.reset, false
+	}
+	w.count++
+	return l.limit - w.count, w.reset, true
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newRateLimitRouter serves GET / behind the rate limiter
+func newRateLimitRouter(opts RateLimitOptions) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimit(opts))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+// requestFrom serves GET / as if sent from ip
+func requestFrom(router http.Handler, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = ip + ":12345"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	router := newRateLimitRouter(RateLimitOptions{Requests: 3, Window: time.Minute})
+	start := time.Now()
+
+	tests := []struct {
+		wantStatus    int
+		wantRemaining string
+	}{
+		{http.StatusOK, "2"},
+		{http.StatusOK, "1"},
+		{http.StatusOK, "0"},
+		{http.StatusTooManyRequests, "0"},
+	}
+	var firstReset string
+	for i, tt := range tests {
+		w := requestFrom(router, "192.0.2.1")
+		if w.Code != tt.wantStatus {
+			t.Errorf("request %d: status = %d, want %d", i+1, w.Code, tt.wantStatus)
+		}
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "3" {
+			t.Errorf("request %d: X-RateLimit-Limit = %q, want 3", i+1, got)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != tt.wantRemaining {
+			t.Errorf("request %d: X-RateLimit-Remaining = %q, want %s", i+1, got, tt.wantRemaining)
+		}
+
+		reset := w.Header().Get("X-RateLimit-Reset")
+		if i == 0 {
+			firstReset = reset
+			unix, err := strconv.ParseInt(reset, 10, 64)
+			if err != nil || unix < start.Add(time.Minute).Unix()-1 || unix > time.Now().Add(time.Minute).Unix() {
+				t.Errorf("X-RateLimit-Reset = %q, want about a minute from now", reset)
+			}
+		} else if reset != firstReset {
+			t.Errorf("request %d: X-RateLimit-Reset = %q, want the window's %q", i+1, reset, firstReset)
+		}
+		if retry := w.Header().Get("Retry-After"); (retry != "") != (tt.wantStatus == http.StatusTooManyRequests) {
+			t.Errorf("request %d: Retry-After = %q", i+1, retry)
+		}
+	}
+}
+
+func TestRateLimitPerClient(t *testing.T) {
+	router := newRateLimitRouter(RateLimitOptions{Requests: 1, Window: time.Minute})
+
+	if w := requestFrom(router, "192.0.2.1"); w.Code != http.StatusOK {
+		t.Fatalf("first client: status = %d, want 200", w.Code)
+	}
+	if w := requestFrom(router, "192.0.2.1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("first client again: status = %d, want 429", w.Code)
+	}
+	w := requestFrom(router, "192.0.2.2")
+	if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("second client: status = %d, remaining %q; want 200 and 0", w.Code, w.Header().Get("X-RateLimit-Remaining"))
+	}
+}
+
+func TestRateLimiterWindowReset(t *testing.T) {
+	limiter := &rateLimiter{limit: 2, window: time.Minute, clients: make(map[string]*rateWindow)}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		at            time.Duration
+		wantRemaining int
+		wantAllowed   bool
+	}{
+		{0, 1, true},
+		{10 * time.Second, 0, true},
+		{59 * time.Second, 0, false},
+		{time.Minute, 1, true}, // a new window starts
+	}
+	for i, step := range steps {
+		remaining, _, allowed := limiter.take("client", now.Add(step.at))
+		if remaining != step.wantRemaining || allowed != step.wantAllowed {
+			t.Errorf("step %d: take = %d, %v; want %d, %v", i, remaining, allowed, step.wantRemaining, step.wantAllowed)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/metrics"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"go.uber.org/zap"
+)
+
+// Recovery middleware turns a panic in a later handler into a JSON 500 response.
+// The panic value and stack trace are logged with the request ID; the client only
+// gets the request ID so the failure can be found in the logs.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			metrics.RecordPanic()
+			requestID := c.GetString("request_id")
+			logger.Error("Panic recovered",
+				zap.String("panic", fmt.Sprint(r)),
+				zap.String("request_id", requestID),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.Stack("stack"),
+			)
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			response.ErrorWithDetails(c, http.StatusInternalServerError, "internal_error", "Internal server error", gin.H{
+				"request_id": requestID,
+			})
+			c.Abort()
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/pkg/response"
+)
+
+// timeoutGrace is how long past the handler deadline the connection stays open,
+// so the timeout response itself can still be written
+const timeoutGrace = 5 * time.Second
+
+// TimeoutOptions configures the Timeout middleware
+type TimeoutOptions struct {
+	// Default is the time budget of every route without an override
+	Default time.Duration
+	// Routes overrides the budget per route, keyed by its full path such as
+	// "/api/v1/inventory/items/import"
+	Routes map[string]time.Duration
+}
+
+// Timeout middleware caps the total time spent handling a request. The request context
+// gets a deadline, so database queries and outgoing calls made with it stop when the
+// budget runs out. The connection's read and write deadlines are moved to match, so a
+// route may run longer than the server-wide timeouts. A handler that has not responded
+// by the deadline gets a 503 with the code "timeout".
+func Timeout(opts TimeoutOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		budget := opts.Default
+		if override, ok := opts.Routes[c.FullPath()]; ok {
+			budget = override
+		}
+		if budget <= 0 {
+			c.Next()
+			return
+		}
+
+		// Best effort: writers that cannot be unwrapped keep the server deadlines
+		rc := http.NewResponseController(c.Writer)
+		deadline := time.Now().Add(budget)
+		_ = rc.SetReadDeadline(deadline)
+		_ = rc.SetWriteDeadline(deadline.Add(timeoutGrace))
+
+		ctx, cancel := context.WithDeadline(c.Request.Context(), deadline)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			response.ErrorWithCode(c, http.StatusServiceUnavailable, "timeout", "Request timed out")
+			c.Abort()
+		}
+	}
+}
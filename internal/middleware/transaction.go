@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nielwyn/inventory-system/internal/repository"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"github.com/nielwyn/inventory-system/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Transaction middleware runs each write request inside a single database transaction.
+// The transaction is carried on the request context so every repository call made
+// by the handler joins it. It is committed when the handler responds with a 2xx or
+// 3xx status and rolled back on an error status or a panic. The response is held
+// back until the commit succeeds, so clients never see a success that was not persisted.
+func Transaction(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		// Beginning waits on the connection pool, which the breaker's query checks would not prevent
+		if cb := repository.CircuitBreakerFor(db); cb != nil && cb.Open() {
+			response.ErrorWithCode(c, http.StatusServiceUnavailable, "database_unavailable", "Database is unavailable; try again later")
+			c.Abort()
+			return
+		}
+
+		ctx := c.Request.Context()
+		tx := db.WithContext(ctx).Begin()
+		if tx.Error != nil {
+			logger.Error("Failed to begin transaction", zap.Error(tx.Error))
+			response.Error(c, http.StatusInternalServerError, "Internal server error")
+			c.Abort()
+			return
+		}
+
+		original := c.Writer
+		writer := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = writer
+		c.Request = c.Request.WithContext(repository.ContextWithTx(ctx, tx))
+
+		// Roll back before handing the panic on to the Recovery middleware,
+		// which writes its 500 through the original writer
+		defer func() {
+			if r := recover(); r != nil {
+				tx.Rollback()
+				c.Writer = original
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		c.Writer = original
+		if writer.status >= http.StatusBadRequest || len(c.Errors) > 0 {
+			tx.Rollback()
+			writer.flush()
+			return
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			logger.Error("Failed to commit transaction", zap.Error(err))
+			response.Error(c, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		writer.flush()
+	}
+}
+
+// bufferedWriter holds the status and body written by a handler until flush is called
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// flush sends the buffered status and body to the underlying writer
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() > 0 {
+		w.ResponseWriter.Write(w.body.Bytes())
+	} else {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
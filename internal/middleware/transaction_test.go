@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var errNoConn = errors.New("no database")
+
+// txConn is a connection pool that only records how transactions end; statements fail.
+// Commit returns commitErr.
+type txConn struct {
+	begun, committed, rolledBack int
+	commitErr                    error
+}
+
+func (*txConn) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errNoConn
+}
+
+func (*txConn) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errNoConn
+}
+
+func (*txConn) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errNoConn
+}
+
+func (*txConn) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (c *txConn) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	c.begun++
+	return c, nil
+}
+
+func (c *txConn) Commit() error {
+	c.committed++
+	return c.commitErr
+}
+
+func (c *txConn) Rollback() error {
+	c.rolledBack++
+	return nil
+}
+
+func TestTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name         string
+		method       string
+		handler      gin.HandlerFunc
+		commitErr    error
+		wantStatus   int
+		wantBody     string
+		wantBegun    int
+		wantCommit   int
+		wantRollback int
+	}{
+		{"read", http.MethodGet, func(c *gin.Context) {
+			c.String(http.StatusOK, "listed")
+		}, nil, http.StatusOK, "listed", 0, 0, 0},
+		{"successful write", http.MethodPost, func(c *gin.Context) {
+			c.String(http.StatusCreated, "created")
+		}, nil, http.StatusCreated, "created", 1, 1, 0},
+		{"redirect", http.MethodPost, func(c *gin.Context) {
+			c.Redirect(http.StatusSeeOther, "/items/1")
+		}, nil, http.StatusSeeOther, "", 1, 1, 0},
+		{"client error", http.MethodPut, func(c *gin.Context) {
+			c.String(http.StatusBadRequest, "invalid")
+		}, nil, http.StatusBadRequest, "invalid", 1, 0, 1},
+		{"server error", http.MethodDelete, func(c *gin.Context) {
+			c.String(http.StatusInternalServerError, "failed")
+		}, nil, http.StatusInternalServerError, "failed", 1, 0, 1},
+		{"error recorded on the context", http.MethodPost, func(c *gin.Context) {
+			_ = c.Error(errors.New("audit failed"))
+			c.String(http.StatusOK, "done")
+		}, nil, http.StatusOK, "done", 1, 0, 1},
+		{"panic", http.MethodPost, func(c *gin.Context) {
+			c.String(http.StatusCreated, "created")
+			panic("boom")
+		}, nil, http.StatusInternalServerError, "", 1, 0, 1},
+		{"failed commit", http.MethodPost, func(c *gin.Context) {
+			c.String(http.StatusCreated, "created")
+		}, errors.New("serialization failure"), http.StatusInternalServerError, "Internal server error", 1, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observeLogs(t, zapcore.ErrorLevel)
+			conn := &txConn{commitErr: tt.commitErr}
+			db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("opening session: %v", err)
+			}
+
+			router := gin.New()
+			router.Use(gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, _ interface{}) {
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}))
+			router.Use(Transaction(db))
+			router.Handle(tt.method, "/items", tt.handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, "/items", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			body := w.Body.String()
+			if !strings.Contains(body, tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", body, tt.wantBody)
+			}
+			if w.Code != http.StatusCreated && strings.Contains(body, "created") {
+				t.Errorf("body = %q, sent for a write that was not persisted", body)
+			}
+			if conn.begun != tt.wantBegun || conn.committed != tt.wantCommit || conn.rolledBack != tt.wantRollback {
+				t.Errorf("begun %d, committed %d, rolled back %d; want %d, %d, %d",
+					conn.begun, conn.committed, conn.rolledBack, tt.wantBegun, tt.wantCommit, tt.wantRollback)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Activity types
+const (
+	ActivityStockMovement = "stock_movement"
+	ActivityPriceChange   = "price_change"
+)
+
+// Activity is one entry in a user's activity feed, drawn from stock movements and price changes.
+// Only the fields relevant to its type are set.
+type Activity struct {
+	Type          string    `json:"type"`
+	ID            uint      `json:"id"`
+	ItemID        uint      `json:"item_id"`
+	Delta         *float64  `json:"delta,omitempty"`
+	QuantityAfter *float64  `json:"quantity_after,omitempty"`
+	OldPrice      *float64  `json:"old_price,omitempty"`
+	NewPrice      *float64  `json:"new_price,omitempty"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// ImpersonatorID is the admin who made the change while impersonating the user
+	ImpersonatorID *uint `json:"impersonator_id,omitempty"`
+}
+
+// ActivityQuery represents the query parameters for listing activity
+type ActivityQuery struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// Normalize fills in default pagination values
+func (q *ActivityQuery) Normalize() {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = DefaultPageSize
+	}
+}
+
+// Offset returns the number of entries to skip for the current page
+func (q *ActivityQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
+
+// AuditRecord is one stock movement in an audit export, with the item SKU and username
+// joined in. SKU and Username are empty if the item or user has since been purged.
+type AuditRecord struct {
+	ID            uint      `json:"id"`
+	ItemID        uint      `json:"item_id"`
+	SKU           string    `json:"sku"`
+	Type          string    `json:"type"`
+	Delta         float64   `json:"delta"`
+	QuantityAfter float64   `json:"quantity_after"`
+	Reason        string    `json:"reason"`
+	UserID        uint      `json:"user_id"`
+	Username      string    `json:"username"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// ImpersonatorID is the admin who made the change while impersonating the user
+	ImpersonatorID *uint `json:"impersonator_id"`
+}
+
+// AuditExportQuery represents the query parameters for exporting the audit trail.
+// From and To are dates, and both days are included.
+type AuditExportQuery struct {
+	From   time.Time `form:"from" binding:"required" time_format:"2006-01-02" time_utc:"1"`
+	To     time.Time `form:"to" binding:"required,gtefield=From" time_format:"2006-01-02" time_utc:"1"`
+	Format string    `form:"format" binding:"omitempty,oneof=json csv"` // defaults to csv
+}
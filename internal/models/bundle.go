@@ -0,0 +1,79 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Bundle is a kit sold as one unit and made up of other items. It holds no stock of its
+// own: how many can be made follows from the stock of its components.
+type Bundle struct {
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	Name      string       `gorm:"size:200;not null" json:"name"`
+	CreatedBy uint         `json:"created_by"`
+	Lines     []BundleLine `gorm:"constraint:OnDelete:CASCADE" json:"lines"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+
+	// AvailableQuantity is how many complete bundles the components in stock make up
+	AvailableQuantity float64 `gorm:"-" json:"available_quantity"`
+}
+
+// TableName specifies the table name for Bundle
+func (Bundle) TableName() string {
+	return "bundles"
+}
+
+// BundleLine is the quantity of one component item that goes into each bundle
+type BundleLine struct {
+	ID       uint    `gorm:"primaryKey" json:"id"`
+	BundleID uint    `gorm:"not null;index" json:"-"`
+	ItemID   uint    `gorm:"not null" json:"item_id"`
+	SKU      string  `gorm:"not null" json:"sku"`
+	Quantity float64 `gorm:"type:decimal(12,3);not null" json:"quantity"`
+
+	// AvailableQuantity is the component's stock; zero when it is backordered or deleted
+	AvailableQuantity float64 `gorm:"-" json:"available_quantity"`
+}
+
+// TableName specifies the table name for BundleLine
+func (BundleLine) TableName() string {
+	return "bundle_lines"
+}
+
+// Refresh recomputes the bundle's available quantity from the stock of its components:
+// the fewest bundles any one component is enough for
+func (b *Bundle) Refresh() {
+	b.AvailableQuantity = 0
+	for i, line := range b.Lines {
+		available := 0.0
+		if line.Quantity > 0 && line.AvailableQuantity > 0 {
+			// Dividing whole numbers of the smallest stored fraction keeps float error
+			// from turning 0.3 / 0.1 into 2, without rounding 1.999 / 2 up to 1
+			scale := math.Pow10(QuantityPrecision)
+			available = math.Floor(math.Round(line.AvailableQuantity*scale) / math.Round(line.Quantity*scale))
+		}
+		if i == 0 || available < b.AvailableQuantity {
+			b.AvailableQuantity = available
+		}
+	}
+}
+
+// CreateBundleRequest represents a request to define a bundle
+type CreateBundleRequest struct {
+	Name  string              `json:"name" binding:"required,max=200"`
+	Lines []BundleLineRequest `json:"lines" binding:"required,min=1,bulk,dive"`
+}
+
+// BundleLineRequest is one component of a new bundle
+type BundleLineRequest struct {
+	SKU      string  `json:"sku" binding:"required,max=100"`
+	Quantity float64 `json:"quantity" binding:"required,gt=0,quantity"`
+}
+
+// ConsumeBundleRequest represents a number of bundles assembled or sold, whose components
+// are taken out of stock
+type ConsumeBundleRequest struct {
+	Quantity float64 `json:"quantity" binding:"required,gt=0,quantity"`
+	Reason   string  `json:"reason" binding:"max=255"`
+}
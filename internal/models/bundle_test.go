@@ -0,0 +1,31 @@
+package models
+
+import "testing"
+
+func TestBundleRefresh(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []BundleLine // Quantity per bundle and AvailableQuantity in stock
+		want  float64
+	}{
+		{"exact multiple", []BundleLine{{Quantity: 2, AvailableQuantity: 6}}, 3},
+		{"remainder", []BundleLine{{Quantity: 2, AvailableQuantity: 7}}, 3},
+		{"float error", []BundleLine{{Quantity: 0.1, AvailableQuantity: 0.3}}, 3},
+		{"just short of one", []BundleLine{{Quantity: 2, AvailableQuantity: 1.999}}, 0},
+		{"just short of a multiple", []BundleLine{{Quantity: 2.001, AvailableQuantity: 2}}, 0},
+		{"just short of two", []BundleLine{{Quantity: 1.5, AvailableQuantity: 2.999}}, 1},
+		{"smallest fraction", []BundleLine{{Quantity: 0.001, AvailableQuantity: 1}}, 1000},
+		{"fewest of the components", []BundleLine{{Quantity: 1, AvailableQuantity: 10}, {Quantity: 0.25, AvailableQuantity: 0.75}}, 3},
+		{"component out of stock", []BundleLine{{Quantity: 1, AvailableQuantity: 10}, {Quantity: 1, AvailableQuantity: 0}}, 0},
+		{"no lines", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := &Bundle{Lines: tt.lines, AvailableQuantity: 99}
+			bundle.Refresh()
+			if bundle.AvailableQuantity != tt.want {
+				t.Errorf("available = %v, want %v", bundle.AvailableQuantity, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+// UncategorizedCategory is the category items are moved to when their category is deleted
+// with the reassign behavior
+const UncategorizedCategory = "Uncategorized"
+
+// Category delete behaviors for items still in a deleted category
+const (
+	CategoryDeleteBlock    = "block"    // refuse to delete a category that has items
+	CategoryDeleteReassign = "reassign" // move the items to UncategorizedCategory
+	CategoryDeleteCascade  = "cascade"  // delete the items along with the category
+)
+
+// DeleteCategoryResponse reports what happened to the items of a deleted category
+type DeleteCategoryResponse struct {
+	Category      string `json:"category"`
+	Behavior      string `json:"behavior"`
+	ItemsAffected int64  `json:"items_affected"`
+}
+
+// DeleteItemsQuery selects the items removed by a bulk delete. Confirm must be set
+// explicitly, since the request cannot be undone through the API.
+type DeleteItemsQuery struct {
+	Category string `form:"category" binding:"required,max=100"`
+	Confirm  bool   `form:"confirm"`
+}
+
+// DeleteItemsResponse reports how many items a bulk delete removed
+type DeleteItemsResponse struct {
+	Category string `json:"category"`
+	Deleted  int64  `json:"deleted"`
+}
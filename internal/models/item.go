@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"math"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,37 +12,288 @@ import (
 type Item struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
 	Name        string         `gorm:"not null" json:"name"`
-	SKU         string         `gorm:"uniqueIndex;not null" json:"sku"`
+	SKU         string         `gorm:"uniqueIndex:idx_items_sku_active,where:deleted_at IS NULL;not null" json:"sku"`
 	Description string         `json:"description"`
-	Quantity    int            `gorm:"not null;default:0" json:"quantity"`
-	Price       float64        `gorm:"not null;default:0" json:"price"`
+	Quantity    float64        `gorm:"type:decimal(12,3);not null;default:0;check:chk_items_quantity_nonnegative,quantity >= 0 OR backordered" json:"quantity"`
+	Unit        string         `gorm:"size:20;not null;default:each" json:"unit"`
+	Backordered bool           `gorm:"not null;default:false" json:"backordered"` // quantity is below zero
+	Price       float64        `gorm:"type:decimal(10,2);not null;default:0;check:chk_items_price_nonnegative,price >= 0" json:"price"`
+	CostPrice   float64        `gorm:"type:decimal(10,2);not null;default:0" json:"-"` // admin-only, see ItemWithCost
 	Category    string         `json:"category"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// Units of measure. Quantities of UnitEach items must be whole numbers.
+const (
+	UnitEach  = "each"
+	UnitKg    = "kg"
+	UnitGram  = "g"
+	UnitLiter = "liter"
+	UnitML    = "ml"
+	UnitMeter = "meter"
+)
+
+// QuantityPrecision is the number of decimal places quantities are stored with
+const QuantityPrecision = 3
+
+// MaxStoredQuantity is the largest quantity the DECIMAL(12,3) quantity columns can hold
+const MaxStoredQuantity = 999999999.999
+
+// MaxPrice is the largest price the DECIMAL(10,2) price columns can hold
+const MaxPrice = 99999999.99
+
+// RoundQuantity rounds q to the stored quantity precision
+func RoundQuantity(q float64) float64 {
+	scale := math.Pow10(QuantityPrecision)
+	return math.Round(q*scale) / scale
+}
+
+// ValidQuantity reports whether q is an acceptable quantity for unit
+func ValidQuantity(unit string, q float64) bool {
+	return unit != UnitEach || q == math.Trunc(q)
+}
+
+// ItemSKUSequence is the Postgres sequence backing generated SKUs
+const ItemSKUSequence = "item_sku_seq"
+
 // TableName specifies the table name for Item
 func (Item) TableName() string {
 	return "items"
 }
 
+// ETag returns the entity tag identifying the current version of the item.
+// It changes whenever the item is saved, since every save moves UpdatedAt.
+func (i *Item) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, i.ID, i.UpdatedAt.UnixMicro())
+}
+
+// ItemWithCost is the representation of an item that includes its cost price,
+// for callers allowed to see it. DeletedAt is only set on soft-deleted items.
+type ItemWithCost struct {
+	*Item
+	CostPrice float64    `json:"cost_price"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// WithCost returns the item including its cost price
+func (i *Item) WithCost() ItemWithCost {
+	view := ItemWithCost{Item: i, CostPrice: i.CostPrice}
+	if i.DeletedAt.Valid {
+		view.DeletedAt = &i.DeletedAt.Time
+	}
+	return view
+}
+
+// PublicItem is the representation of an item for anonymous callers: catalog details,
+// and whether the item is in stock rather than how much of it there is
+type PublicItem struct {
+	ID          uint    `json:"id"`
+	Name        string  `json:"name"`
+	SKU         string  `json:"sku"`
+	Description string  `json:"description"`
+	Unit        string  `json:"unit"`
+	Price       float64 `json:"price"`
+	Category    string  `json:"category"`
+	InStock     bool    `json:"in_stock"`
+}
+
+// Public returns the item as anonymous callers see it
+func (i *Item) Public() PublicItem {
+	return PublicItem{
+		ID:          i.ID,
+		Name:        i.Name,
+		SKU:         i.SKU,
+		Description: i.Description,
+		Unit:        i.Unit,
+		Price:       i.Price,
+		Category:    i.Category,
+		InStock:     i.Quantity > 0,
+	}
+}
+
 // CreateItemRequest represents a request to create an item
 type CreateItemRequest struct {
 	Name        string  `json:"name" binding:"required,min=1,max=200"`
-	SKU         string  `json:"sku" binding:"required,min=1,max=100"`
+	SKU         string  `json:"sku" binding:"max=100"` // optional when SKU generation is enabled
 	Description string  `json:"description" binding:"max=1000"`
-	Quantity    int     `json:"quantity" binding:"non_negative"`
-	Price       float64 `json:"price" binding:"non_negative"`
+	Quantity    float64 `json:"quantity" binding:"non_negative,quantity"`
+	Unit        string  `json:"unit" binding:"omitempty,oneof=each kg g liter ml meter"` // defaults to each
+	Price       float64 `json:"price" binding:"price"`
+	CostPrice   float64 `json:"cost_price" binding:"price"`
 	Category    string  `json:"category" binding:"max=100"`
 }
 
+// Ways of handling a create whose SKU is already in use
+const (
+	OnConflictError  = "error"  // reject it with 409
+	OnConflictIgnore = "ignore" // return the existing item unchanged
+	OnConflictUpdate = "update" // update the existing item from the request
+)
+
+// CreateItemQuery represents the query parameters for creating an item
+type CreateItemQuery struct {
+	OnConflict string `form:"on_conflict" binding:"omitempty,oneof=error ignore update"` // defaults to error
+}
+
+// BulkCreateItemsRequest represents a request to create several items at once
+type BulkCreateItemsRequest struct {
+	Items []CreateItemRequest `json:"items" binding:"required,min=1,bulk,dive"`
+}
+
+// SyncItemsRequest represents a supplier feed to upsert by SKU
+type SyncItemsRequest struct {
+	Items []SyncItemRequest `json:"items" binding:"required,min=1,bulk,dive"`
+}
+
+// SyncItemRequest is one entry of a feed. Fields left out are not changed on an existing
+// item, and take their defaults on a new one.
+type SyncItemRequest struct {
+	Name        string   `json:"name" binding:"required,min=1,max=200"`
+	SKU         string   `json:"sku" binding:"max=100"` // required, checked by the sync so errors name the entry
+	Description *string  `json:"description" binding:"omitempty,max=1000"`
+	Quantity    *float64 `json:"quantity" binding:"omitempty,non_negative,quantity"`
+	Unit        *string  `json:"unit" binding:"omitempty,oneof=each kg g liter ml meter"`
+	Price       *float64 `json:"price" binding:"omitempty,price"`
+	CostPrice   *float64 `json:"cost_price" binding:"omitempty,price"`
+	Category    *string  `json:"category" binding:"omitempty,max=100"`
+}
+
+// CreateRequest returns the request creating the entry as a new item
+func (r *SyncItemRequest) CreateRequest() *CreateItemRequest {
+	req := &CreateItemRequest{Name: r.Name, SKU: r.SKU}
+	if r.Description != nil {
+		req.Description = *r.Description
+	}
+	if r.Quantity != nil {
+		req.Quantity = *r.Quantity
+	}
+	if r.Unit != nil {
+		req.Unit = *r.Unit
+	}
+	if r.Price != nil {
+		req.Price = *r.Price
+	}
+	if r.CostPrice != nil {
+		req.CostPrice = *r.CostPrice
+	}
+	if r.Category != nil {
+		req.Category = *r.Category
+	}
+	return req
+}
+
+// SyncItemsResponse reports how many items a sync created and updated
+type SyncItemsResponse struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// BatchGetItemsRequest represents a request to fetch several items by ID
+type BatchGetItemsRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,bulk"`
+}
+
+// MergeItemsRequest represents a request to fold a duplicate item into another
+type MergeItemsRequest struct {
+	SourceID uint `json:"source_id" binding:"required"`
+	TargetID uint `json:"target_id" binding:"required,nefield=SourceID"`
+}
+
+// CloneItemRequest represents a request to copy an existing item under a new SKU
+type CloneItemRequest struct {
+	SKU           string `json:"sku" binding:"required,min=1,max=100"`
+	ResetQuantity bool   `json:"reset_quantity"`
+}
+
 // UpdateItemRequest represents a request to update an item
 type UpdateItemRequest struct {
 	Name        *string  `json:"name" binding:"omitempty,min=1,max=200"`
 	SKU         *string  `json:"sku" binding:"omitempty,min=1,max=100"`
 	Description *string  `json:"description" binding:"omitempty,max=1000"`
-	Quantity    *int     `json:"quantity" binding:"omitempty,non_negative"`
-	Price       *float64 `json:"price" binding:"omitempty,non_negative"`
+	Quantity    *float64 `json:"quantity" binding:"omitempty,non_negative,quantity"`
+	Unit        *string  `json:"unit" binding:"omitempty,oneof=each kg g liter ml meter"`
+	Price       *float64 `json:"price" binding:"omitempty,price"`
+	CostPrice   *float64 `json:"cost_price" binding:"omitempty,price"`
 	Category    *string  `json:"category" binding:"omitempty,max=100"`
 }
+
+// CategoryCount holds the number of items in a category
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+// CategoryMargin holds the total margin of the stock held in a category
+// for items of one unit of measure
+type CategoryMargin struct {
+	Category string  `json:"category"`
+	Unit     string  `json:"unit"`
+	Margin   float64 `json:"margin"`
+}
+
+// MarginReport holds the margin of the stock held, per category and unit and in total.
+// Margin is (price - cost price) * quantity summed over items; prices are per unit,
+// so margins of different units can be added up.
+type MarginReport struct {
+	Categories []CategoryMargin `json:"categories"`
+	Total      float64          `json:"total"`
+}
+
+// ExportItemsQuery represents the query parameters for exporting items
+type ExportItemsQuery struct {
+	Format   string `form:"format" binding:"omitempty,oneof=json csv xlsx"` // defaults to csv
+	Category string `form:"category" binding:"max=100"`
+	Search   string `form:"search" binding:"max=200"`
+}
+
+// FacetsQuery represents the query parameters for facet counts
+type FacetsQuery struct {
+	Search string `form:"search" binding:"max=200"`
+}
+
+// Facets holds the filter counts for a search
+type Facets struct {
+	Categories []CategoryCount `json:"categories"`
+}
+
+// Pagination defaults for list endpoints
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ListItemsQuery represents the query parameters for listing items
+type ListItemsQuery struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Category string `form:"category" binding:"max=100"`
+	Search   string `form:"search" binding:"max=200"`
+	Sort     string `form:"sort" binding:"max=200"`   // e.g. "category,-price"
+	Fields   string `form:"fields" binding:"max=200"` // e.g. "id,name,price"; empty returns whole items
+	Count    bool   `form:"count"`
+
+	// IncludeDeleted lists soft-deleted items too; only honored for admins
+	IncludeDeleted bool `form:"include_deleted"`
+}
+
+// Normalize fills in default pagination values. A list requested with neither page nor
+// page_size is not paginated, so clients written before paging still get every item.
+func (q *ListItemsQuery) Normalize() {
+	if q.Page == 0 && q.PageSize == 0 {
+		q.Page = 1
+		return
+	}
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = DefaultPageSize
+	}
+}
+
+// Offset returns the number of items to skip for the current page
+func (q *ListItemsQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// itemFieldValues maps the item fields clients may select with ?fields= to their values.
+// The names are the JSON names, which are also the column names.
+var itemFieldValues = map[string]func(i *Item) interface{}{
+	"id":          func(i *Item) interface{} { return i.ID },
+	"name":        func(i *Item) interface{} { return i.Name },
+	"sku":         func(i *Item) interface{} { return i.SKU },
+	"description": func(i *Item) interface{} { return i.Description },
+	"quantity":    func(i *Item) interface{} { return i.Quantity },
+	"unit":        func(i *Item) interface{} { return i.Unit },
+	"backordered": func(i *Item) interface{} { return i.Backordered },
+	"price":       func(i *Item) interface{} { return i.Price },
+	"cost_price":  func(i *Item) interface{} { return i.CostPrice },
+	"category":    func(i *Item) interface{} { return i.Category },
+	"created_at":  func(i *Item) interface{} { return i.CreatedAt },
+	"updated_at":  func(i *Item) interface{} { return i.UpdatedAt },
+}
+
+// SplitFields splits a comma-separated ?fields= value, dropping blanks and repeats
+func SplitFields(s string) []string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" && !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// ValidateItemFields checks that every field can be selected. cost_price is only
+// selectable when includeCost is true.
+func ValidateItemFields(fields []string, includeCost bool) error {
+	for _, field := range fields {
+		if _, ok := itemFieldValues[field]; !ok || (field == "cost_price" && !includeCost) {
+			return fmt.Errorf("Field 'Fields' contains unknown item field '%s'", field)
+		}
+	}
+	return nil
+}
+
+// publicItemFields are the item fields anonymous callers may select, those of PublicItem
+var publicItemFields = map[string]bool{
+	"id":          true,
+	"name":        true,
+	"sku":         true,
+	"description": true,
+	"unit":        true,
+	"price":       true,
+	"category":    true,
+}
+
+// ValidatePublicItemFields checks that every field can be selected by an anonymous caller,
+// who cannot see stock levels, cost prices or timestamps
+func ValidatePublicItemFields(fields []string) error {
+	for _, field := range fields {
+		if !publicItemFields[field] {
+			return fmt.Errorf("Field 'Fields' contains unknown item field '%s'", field)
+		}
+	}
+	return nil
+}
+
+// Project returns only the given fields of the item, keyed by JSON name.
+// Fields must have been checked with ValidateItemFields.
+func (i *Item) Project(fields []string) map[string]interface{} {
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		projected[field] = itemFieldValues[field](i)
+	}
+	return projected
+}
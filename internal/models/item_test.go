@@ -0,0 +1,67 @@
+package models
+
+import "testing"
+
+func TestRoundQuantity(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		want     float64
+	}{
+		{1, 1},
+		{0.1 + 0.2, 0.3},
+		{2.5 - 1.375, 1.125},
+		{1.0004, 1},
+		{1.0005, 1.001},
+		{-0.1 - 0.2, -0.3},
+	}
+	for _, tt := range tests {
+		if got := RoundQuantity(tt.quantity); got != tt.want {
+			t.Errorf("RoundQuantity(%v) = %v, want %v", tt.quantity, got, tt.want)
+		}
+	}
+}
+
+func TestValidQuantity(t *testing.T) {
+	tests := []struct {
+		unit     string
+		quantity float64
+		want     bool
+	}{
+		{UnitEach, 3, true},
+		{UnitEach, -2, true},
+		{UnitEach, 0.5, false},
+		{UnitEach, 2.001, false},
+		{UnitKg, 0.125, true},
+		{UnitLiter, 1.5, true},
+		{UnitMeter, 7, true},
+	}
+	for _, tt := range tests {
+		if got := ValidQuantity(tt.unit, tt.quantity); got != tt.want {
+			t.Errorf("ValidQuantity(%q, %v) = %v, want %v", tt.unit, tt.quantity, got, tt.want)
+		}
+	}
+}
+
+func TestListItemsQueryNormalize(t *testing.T) {
+	tests := []struct {
+		name                   string
+		page, pageSize         int
+		wantPage, wantPageSize int
+		wantOffset             int
+	}{
+		{"neither given lists everything", 0, 0, 1, 0, 0},
+		{"page given", 3, 0, 3, DefaultPageSize, 2 * DefaultPageSize},
+		{"page size given", 0, 50, 1, 50, 0},
+		{"both given", 2, 10, 2, 10, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := ListItemsQuery{Page: tt.page, PageSize: tt.pageSize}
+			q.Normalize()
+			if q.Page != tt.wantPage || q.PageSize != tt.wantPageSize || q.Offset() != tt.wantOffset {
+				t.Errorf("page %d, page size %d, offset %d; want %d, %d, %d",
+					q.Page, q.PageSize, q.Offset(), tt.wantPage, tt.wantPageSize, tt.wantOffset)
+			}
+		})
+	}
+}
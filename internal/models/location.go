@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Location is a place stock is held, such as a warehouse or store
+type Location struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:100;uniqueIndex;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Location
+func (Location) TableName() string {
+	return "locations"
+}
+
+// StockLevel is the quantity of one item held at one location
+type StockLevel struct {
+	LocationID uint      `gorm:"primaryKey;autoIncrement:false" json:"location_id"`
+	ItemID     uint      `gorm:"primaryKey;autoIncrement:false;index" json:"item_id"`
+	Quantity   float64   `gorm:"type:decimal(12,3);not null;default:0" json:"quantity"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for StockLevel
+func (StockLevel) TableName() string {
+	return "stock_levels"
+}
+
+// LocationStock is an item's stock level at a location, with the item's details
+type LocationStock struct {
+	ItemID    uint      `json:"item_id"`
+	SKU       string    `json:"sku"`
+	Name      string    `json:"name"`
+	Category  string    `json:"category"`
+	Unit      string    `json:"unit"`
+	Price     float64   `json:"price"`
+	Quantity  float64   `json:"quantity"` // held at the location
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LocationStockQuery represents the query parameters for listing a location's stock
+type LocationStockQuery struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Category string `form:"category" binding:"max=100"`
+	Search   string `form:"search" binding:"max=200"`
+}
+
+// Normalize fills in default pagination values
+func (q *LocationStockQuery) Normalize() {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = DefaultPageSize
+	}
+}
+
+// Offset returns the number of stock levels to skip for the current page
+func (q *LocationStockQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
@@ -0,0 +1,6 @@
+package models
+
+// SetMaintenanceRequest represents a request to switch maintenance mode on or off
+type SetMaintenanceRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PasswordHistory keeps the hash of a password a user has replaced, so it cannot be chosen again
+type PasswordHistory struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index:idx_password_history_user_created,priority:1"`
+	Hash      string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"index:idx_password_history_user_created,priority:2"`
+}
+
+// TableName specifies the table name for PasswordHistory
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// UserPreferences holds a user's defaults for list and report endpoints.
+// Zero values mean the user has no preference and the system default applies.
+type UserPreferences struct {
+	UserID          uint      `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	PageSize        int       `gorm:"not null;default:0" json:"page_size"`
+	Currency        string    `gorm:"size:3" json:"currency"`
+	DefaultCategory string    `gorm:"size:100" json:"default_category"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for UserPreferences
+func (UserPreferences) TableName() string {
+	return "user_preferences"
+}
+
+// UpdatePreferencesRequest represents a request to change the authenticated user's preferences.
+// Omitted fields are left unchanged; send 0 or "" to clear a preference.
+type UpdatePreferencesRequest struct {
+	PageSize        *int    `json:"page_size" binding:"omitempty,min=0,max=100"`
+	Currency        *string `json:"currency" binding:"omitempty,iso4217"`
+	DefaultCategory *string `json:"default_category" binding:"omitempty,max=100"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// PriceHistory records a change to an item's selling price
+type PriceHistory struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ItemID    uint      `gorm:"not null;index" json:"item_id"`
+	OldPrice  float64   `gorm:"not null" json:"old_price"`
+	NewPrice  float64   `gorm:"not null" json:"new_price"`
+	Reason    string    `gorm:"size:255" json:"reason"`
+	UserID    uint      `gorm:"index:idx_price_history_user_created,priority:1" json:"user_id"`
+	CreatedAt time.Time `gorm:"index:idx_price_history_user_created,priority:2;index:idx_price_history_created_at" json:"created_at"`
+
+	// ImpersonatorID is the admin who made the change while impersonating UserID
+	ImpersonatorID *uint `json:"impersonator_id,omitempty"`
+}
+
+// TableName specifies the table name for PriceHistory
+func (PriceHistory) TableName() string {
+	return "price_history"
+}
+
+// Scheduled price statuses
+const (
+	ScheduledPricePending   = "pending"
+	ScheduledPriceApplied   = "applied"
+	ScheduledPriceCancelled = "cancelled"
+)
+
+// ScheduledPrice is a price change that takes effect at a future time
+type ScheduledPrice struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	ItemID      uint       `gorm:"not null;index" json:"item_id"`
+	Price       float64    `gorm:"not null" json:"price"`
+	EffectiveAt time.Time  `gorm:"not null;index" json:"effective_at"`
+	Status      string     `gorm:"size:20;not null;default:pending;index" json:"status"`
+	UserID      uint       `json:"user_id"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for ScheduledPrice
+func (ScheduledPrice) TableName() string {
+	return "scheduled_prices"
+}
+
+// SchedulePriceRequest represents a request to schedule a future price change
+type SchedulePriceRequest struct {
+	Price       *float64  `json:"price" binding:"required,price"`
+	EffectiveAt time.Time `json:"effective_at" binding:"required"`
+}
+
+// Bulk price update operations
+const (
+	PriceOperationPercent = "percent" // change by a percentage, e.g. 10 or -15
+	PriceOperationSet     = "set"     // set to a fixed price
+	PriceOperationAdd     = "add"     // add a fixed amount, which may be negative
+)
+
+// BulkPriceUpdateRequest represents a request to change the price of every item in a category
+type BulkPriceUpdateRequest struct {
+	Category  string   `json:"category" binding:"required,max=100"`
+	Operation string   `json:"operation" binding:"required,oneof=percent set add"`
+	Value     *float64 `json:"value" binding:"required"`
+}
+
+// BulkPriceUpdateResponse reports how many items a bulk price update changed
+type BulkPriceUpdateResponse struct {
+	Updated int64 `json:"updated"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// Purchase order statuses
+const (
+	PurchaseOrderOpen              = "open"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+)
+
+// PurchaseOrder is an order placed with a supplier, received into stock line by line
+type PurchaseOrder struct {
+	ID        uint                `gorm:"primaryKey" json:"id"`
+	Supplier  string              `gorm:"size:200;not null" json:"supplier"`
+	Status    string              `gorm:"size:20;not null;default:open" json:"status"`
+	CreatedBy uint                `json:"created_by"`
+	Lines     []PurchaseOrderLine `gorm:"constraint:OnDelete:CASCADE" json:"lines"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// TableName specifies the table name for PurchaseOrder
+func (PurchaseOrder) TableName() string {
+	return "purchase_orders"
+}
+
+// PurchaseOrderLine is the quantity of one item ordered, and how much of it has arrived
+type PurchaseOrderLine struct {
+	ID               uint    `gorm:"primaryKey" json:"id"`
+	PurchaseOrderID  uint    `gorm:"not null;index" json:"-"`
+	ItemID           uint    `gorm:"not null" json:"item_id"`
+	SKU              string  `gorm:"not null" json:"sku"`
+	OrderedQuantity  float64 `gorm:"type:decimal(12,3);not null" json:"ordered_quantity"`
+	ReceivedQuantity float64 `gorm:"type:decimal(12,3);not null;default:0" json:"received_quantity"`
+
+	// RemainingQuantity is what is still to be received; never below zero
+	RemainingQuantity float64 `gorm:"-" json:"remaining_quantity"`
+}
+
+// TableName specifies the table name for PurchaseOrderLine
+func (PurchaseOrderLine) TableName() string {
+	return "purchase_order_lines"
+}
+
+// Refresh recomputes the remaining quantity of every line and the order's status from
+// the quantities received so far
+func (po *PurchaseOrder) Refresh() {
+	received, complete := false, true
+	for i := range po.Lines {
+		line := &po.Lines[i]
+		line.RemainingQuantity = 0
+		if remaining := RoundQuantity(line.OrderedQuantity - line.ReceivedQuantity); remaining > 0 {
+			line.RemainingQuantity = remaining
+			complete = false
+		}
+		if line.ReceivedQuantity > 0 {
+			received = true
+		}
+	}
+	switch {
+	case complete:
+		po.Status = PurchaseOrderReceived
+	case received:
+		po.Status = PurchaseOrderPartiallyReceived
+	default:
+		po.Status = PurchaseOrderOpen
+	}
+}
+
+// CreatePurchaseOrderRequest represents a request to place a purchase order
+type CreatePurchaseOrderRequest struct {
+	Supplier string                     `json:"supplier" binding:"required,max=200"`
+	Lines    []PurchaseOrderLineRequest `json:"lines" binding:"required,min=1,bulk,dive"`
+}
+
+// PurchaseOrderLineRequest is one item on a new purchase order
+type PurchaseOrderLineRequest struct {
+	SKU             string  `json:"sku" binding:"required,max=100"`
+	OrderedQuantity float64 `json:"ordered_quantity" binding:"required,gt=0,quantity"`
+}
+
+// ReceivePurchaseOrderRequest represents the quantities that arrived against a purchase order
+type ReceivePurchaseOrderRequest struct {
+	Lines  []ReceiptLineRequest `json:"lines" binding:"required,min=1,bulk,dive"`
+	Reason string               `json:"reason" binding:"max=255"`
+}
+
+// ReceiptLineRequest is the quantity of one item that arrived
+type ReceiptLineRequest struct {
+	SKU      string  `json:"sku" binding:"required,max=100"`
+	Quantity float64 `json:"quantity" binding:"required,gt=0,quantity"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// Self-test check statuses
+const (
+	SelfTestPass    = "pass"
+	SelfTestFail    = "fail"
+	SelfTestSkipped = "skipped"
+)
+
+// SelfTestCheck is the outcome of exercising one dependency
+type SelfTestCheck struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// SelfTestReport collects the outcome of every self-test check.
+// Healthy is true when no check failed.
+type SelfTestReport struct {
+	Healthy bool            `json:"healthy"`
+	Checks  []SelfTestCheck `json:"checks"`
+}
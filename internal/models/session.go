@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Session records a login, so its user can see where they are signed in and revoke it.
+// The ID is opaque and carried in the sid claim of the session's token; the token
+// itself is never stored.
+type Session struct {
+	ID         string     `gorm:"primaryKey;size:32" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"-"`
+	UserAgent  string     `gorm:"size:255" json:"user_agent"`
+	IPAddress  string     `gorm:"size:45" json:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"-"`
+
+	// Current marks the session the request was made with
+	Current bool `gorm:"-" json:"current"`
+}
+
+// SessionClient describes the client a session is started from
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// StockMovement records a single change to an item's quantity
+type StockMovement struct {
+	ID            uint    `gorm:"primaryKey" json:"id"`
+	ItemID        uint    `gorm:"not null;index" json:"item_id"`
+	Delta         float64 `gorm:"type:decimal(12,3);not null" json:"delta"`
+	QuantityAfter float64 `gorm:"type:decimal(12,3);not null" json:"quantity_after"`
+	Type          string  `gorm:"size:20;not null;default:adjustment" json:"type"`
+	Reason        string  `gorm:"size:255" json:"reason"`
+
+	// PurchaseOrderID references the purchase order a receipt was recorded against
+	PurchaseOrderID *uint `gorm:"index" json:"purchase_order_id,omitempty"`
+
+	// BundleID references the bundle whose components a bundle movement took out of stock
+	BundleID *uint `gorm:"index" json:"bundle_id,omitempty"`
+
+	UserID uint `gorm:"index:idx_stock_movements_user_created,priority:1" json:"user_id"`
+
+	// ImpersonatorID is the admin who made the change while impersonating UserID
+	ImpersonatorID *uint `json:"impersonator_id,omitempty"`
+
+	CreatedAt time.Time `gorm:"index:idx_stock_movements_user_created,priority:2;index:idx_stock_movements_created_at" json:"created_at"`
+}
+
+// Stock movement types
+const (
+	MovementTypeAdjustment     = "adjustment"     // a delta or absolute set through the adjust endpoints
+	MovementTypeReconciliation = "reconciliation" // a correction to a physical stock-take count
+	MovementTypeReceipt        = "receipt"        // stock received against a purchase order
+	MovementTypeMerge          = "merge"          // the stock of a duplicate item merged into this one
+	MovementTypeBundle         = "bundle"         // components taken out of stock for bundles assembled or sold
+	MovementTypeSync           = "sync"           // a quantity set by a sync or CSV import
+)
+
+// TableName specifies the table name for StockMovement
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
+
+// AdjustStockRequest represents a request to change an item's quantity, either by
+// a delta or, for a stock-take, to an absolute value. Exactly one of Delta and Set is given.
+type AdjustStockRequest struct {
+	Delta  *float64 `json:"delta" binding:"omitempty,ne=0,quantity"`
+	Set    *float64 `json:"set" binding:"omitempty,non_negative,quantity"`
+	Reason string   `json:"reason" binding:"max=255"`
+}
+
+// ReconcileRequest represents the counted quantities from a physical stock-take
+type ReconcileRequest struct {
+	Items  []ReconcileCount `json:"items" binding:"required,min=1,bulk,dive"`
+	Reason string           `json:"reason" binding:"max=255"`
+}
+
+// ReconcileCount is the counted quantity of one item
+type ReconcileCount struct {
+	SKU             string   `json:"sku" binding:"required,max=100"`
+	CountedQuantity *float64 `json:"counted_quantity" binding:"required,non_negative,quantity"`
+}
+
+// ReconcileAdjustment reports how one item's quantity was corrected
+type ReconcileAdjustment struct {
+	ItemID           uint    `json:"item_id"`
+	SKU              string  `json:"sku"`
+	PreviousQuantity float64 `json:"previous_quantity"`
+	CountedQuantity  float64 `json:"counted_quantity"`
+	Delta            float64 `json:"delta"`
+}
+
+// ReconcileResponse reports the adjustments a reconciliation made, in request order
+type ReconcileResponse struct {
+	Adjusted    int                   `json:"adjusted"`
+	Unchanged   int                   `json:"unchanged"`
+	Adjustments []ReconcileAdjustment `json:"adjustments"`
+}
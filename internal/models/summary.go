@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// AdminSummary holds the headline numbers of the admin dashboard
+type AdminSummary struct {
+	TotalItems     int64   `json:"total_items"`
+	TotalSKUs      int64   `json:"total_skus"`
+	InventoryValue float64 `json:"inventory_value"` // sum of price * quantity over active items
+
+	// LowStockCount is the number of active items at or below LowStockThreshold
+	LowStockCount     int64   `json:"low_stock_count"`
+	LowStockThreshold float64 `json:"low_stock_threshold"`
+
+	TotalUsers int64 `json:"total_users"`
+
+	// RecentActivityCount is the number of stock movements and price changes since ActivitySince
+	RecentActivityCount int64     `json:"recent_activity_count"`
+	ActivitySince       time.Time `json:"activity_since"`
+
+	// GeneratedAt is when the numbers were computed; they may be served from cache for a while
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// BusinessReport is a snapshot of stock figures for analytics ingestion. Quantities of
+// different units cannot be added up, so they are reported per unit; values can.
+type BusinessReport struct {
+	TotalItems     int64          `json:"total_items"`
+	TotalSKUs      int64          `json:"total_skus"`
+	Quantities     []UnitQuantity `json:"quantities"`
+	InventoryValue float64        `json:"inventory_value"` // sum of price * quantity over active items
+
+	Categories []CategoryReport `json:"categories"`
+
+	// LowStockCount is the number of active items at or below LowStockThreshold; LowStock
+	// lists them, lowest quantity first, up to a limit
+	LowStockThreshold float64         `json:"low_stock_threshold"`
+	LowStockCount     int64           `json:"low_stock_count"`
+	LowStock          []LowStockEntry `json:"low_stock"`
+
+	// GeneratedAt is when the report was computed; it may be served from cache for a while
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// UnitQuantity is the total quantity in stock of the items of one unit of measure
+type UnitQuantity struct {
+	Unit     string  `json:"unit"`
+	Quantity float64 `json:"quantity"`
+}
+
+// CategoryReport holds the stock figures of one category for items of one unit of measure
+type CategoryReport struct {
+	Category       string  `json:"category"`
+	Unit           string  `json:"unit"`
+	Items          int64   `json:"items"`
+	Quantity       float64 `json:"quantity"`
+	InventoryValue float64 `json:"inventory_value"`
+}
+
+// LowStockEntry identifies an item at or below the low stock threshold
+type LowStockEntry struct {
+	ID       uint    `json:"id"`
+	SKU      string  `json:"sku"`
+	Name     string  `json:"name"`
+	Category string  `json:"category"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+}
+
+// ReportQuery selects the format of the business report
+type ReportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=json openmetrics"`
+}
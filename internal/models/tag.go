@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Tag is a label that can be attached to any number of items, e.g. for a promotion
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:50;uniqueIndex;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for Tag
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// ItemTag attaches a tag to an item
+type ItemTag struct {
+	ItemID    uint      `gorm:"primaryKey;autoIncrement:false" json:"item_id"`
+	TagID     uint      `gorm:"primaryKey;autoIncrement:false;index" json:"tag_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for ItemTag
+func (ItemTag) TableName() string {
+	return "item_tags"
+}
+
+// MaxTagNameLength is the longest tag name accepted
+const MaxTagNameLength = 50
+
+// TagItemsRequest represents a request to attach a tag to, or remove it from, several items
+type TagItemsRequest struct {
+	ItemIDs []uint `json:"item_ids" binding:"required,min=1,bulk"`
+}
+
+// TagItemsResponse reports how many of the requested items a tag change affected.
+// Items that already had (or lacked) the tag, or do not exist, are not counted.
+type TagItemsResponse struct {
+	Tag       string `json:"tag"`
+	Requested int    `json:"requested"`
+	Affected  int64  `json:"affected"`
+}
@@ -1,17 +1,26 @@
 package models
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // User represents a user in the system
 type User struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
 	Username  string         `gorm:"uniqueIndex;not null" json:"username"`
 	Email     string         `gorm:"uniqueIndex;not null" json:"email"`
 	Password  string         `gorm:"not null" json:"-"` // "-" prevents password from being serialized
+	Role      string         `gorm:"size:20;not null;default:user" json:"role"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -22,6 +31,18 @@ func (User) TableName() string {
 	return "users"
 }
 
+// UserSummary is the minimal public view of a user
+type UserSummary struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// Summary returns the minimal public view of the user
+func (u *User) Summary() UserSummary {
+	return UserSummary{ID: u.ID, Username: u.Username, Role: u.Role}
+}
+
 // RegisterRequest represents a user registration request
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
@@ -29,14 +50,97 @@ type RegisterRequest struct {
 	Password string `json:"password" binding:"required,min=6"`
 }
 
+// UnmarshalJSON decodes a registration request with its username and email normalized,
+// so surrounding whitespace does not fail the email check
+func (r *RegisterRequest) UnmarshalJSON(data []byte) error {
+	type plain RegisterRequest
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	r.Username = NormalizeUsername(r.Username)
+	r.Email = NormalizeEmail(r.Email)
+	return nil
+}
+
+// NormalizeEmail returns the form emails are stored and looked up in: trimmed and lower case
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizeUsername returns the form usernames are stored and looked up in: trimmed.
+// Usernames keep their case.
+func NormalizeUsername(username string) string {
+	return strings.TrimSpace(username)
+}
+
+// ChangePasswordRequest represents a request to change the caller's password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
 // LoginRequest represents a user login request
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
 
-// LoginResponse represents a login response with JWT token
+// LoginResponse represents a login response with JWT token.
+// User holds either the full User or a UserSummary, depending on configuration.
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token string      `json:"token"`
+	User  interface{} `json:"user"`
+}
+
+// IntrospectRequest represents a request to check a token, sent as JSON or form data
+type IntrospectRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+// TokenIntrospection describes a token in the style of RFC 7662. Only Active is set
+// for a token that is invalid, expired or not an access token.
+type TokenIntrospection struct {
+	Active    bool   `json:"active"`
+	UserID    uint   `json:"user_id,omitempty"`
+	Role      string `json:"role,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+
+	// ImpersonatedBy is the admin an impersonation token was issued to
+	ImpersonatedBy uint `json:"impersonated_by,omitempty"`
+}
+
+// ImpersonationResponse is a token issued to an admin to act as another user
+type ImpersonationResponse struct {
+	Token          string      `json:"token"`
+	ExpiresAt      time.Time   `json:"expires_at"`
+	User           UserSummary `json:"user"`
+	ImpersonatedBy uint        `json:"impersonated_by"`
+}
+
+// SearchUsersQuery represents the query parameters for searching users
+type SearchUsersQuery struct {
+	Search   string `form:"search" binding:"max=100"` // username or email prefix
+	Sort     string `form:"sort" binding:"omitempty,oneof=created_at -created_at"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// Normalize fills in default pagination and sort values
+func (q *SearchUsersQuery) Normalize() {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.Sort == "" {
+		q.Sort = "-created_at"
+	}
+}
+
+// Offset returns the number of users to skip for the current page
+func (q *SearchUsersQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
 }
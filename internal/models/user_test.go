@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"alice@example.com", "alice@example.com"},
+		{" Alice@Example.COM ", "alice@example.com"},
+		{"\tBOB@EXAMPLE.COM\n", "bob@example.com"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeEmail(tt.email); got != tt.want {
+			t.Errorf("NormalizeEmail(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		username string
+		want     string
+	}{
+		{"alice", "alice"},
+		{"  Alice ", "Alice"},
+		{"\tbob\n", "bob"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeUsername(tt.username); got != tt.want {
+			t.Errorf("NormalizeUsername(%q) = %q, want %q", tt.username, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterRequestNormalized(t *testing.T) {
+	var req RegisterRequest
+	body := `{"username":" Alice ","email":" Alice@Example.COM ","password":" secret "}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("decoding %s: %v", body, err)
+	}
+	if req.Username != "Alice" || req.Email != "alice@example.com" {
+		t.Errorf("username %q, email %q; want \"Alice\", \"alice@example.com\"", req.Username, req.Email)
+	}
+	if req.Password != " secret " {
+		t.Errorf("password = %q, want it left as sent", req.Password)
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"gorm.io/gorm"
+)
+
+// ActivityRepository reads the audit trail of changes made by users
+type ActivityRepository interface {
+	FindByUser(ctx context.Context, userID uint, offset, limit int) ([]models.Activity, error)
+	StreamMovements(ctx context.Context, from, to time.Time, fn func(record *models.AuditRecord) error) error
+}
+
+type activityRepository struct {
+	db *gorm.DB
+}
+
+// NewActivityRepository creates a new activity repository
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	return &activityRepository{db: db}
+}
+
+// activityQuery merges a user's stock movements and price changes, newest first.
+// Each branch is limited to offset+limit rows so only the (user_id, created_at)
+// index ranges needed for the requested page are read.
+const activityQuery = `
+SELECT * FROM (
+	(SELECT 'stock_movement' AS type, id, item_id, delta, quantity_after,
+		NULL::numeric AS old_price, NULL::numeric AS new_price, reason, impersonator_id, created_at
+	FROM stock_movements WHERE user_id = @user ORDER BY created_at DESC LIMIT @window)
+	UNION ALL
+	(SELECT 'price_change' AS type, id, item_id, NULL::numeric, NULL::numeric,
+		old_price, new_price, reason, impersonator_id, created_at
+	FROM price_history WHERE user_id = @user ORDER BY created_at DESC LIMIT @window)
+) AS activity
+ORDER BY created_at DESC, type, id DESC
+LIMIT @limit OFFSET @offset`
+
+// FindByUser retrieves a page of the stock movements and price changes made by a user, newest first
+func (r *activityRepository) FindByUser(ctx context.Context, userID uint, offset, limit int) ([]models.Activity, error) {
+	var activity []models.Activity
+	err := conn(ctx, r.db).Raw(activityQuery, map[string]interface{}{
+		"user":   userID,
+		"window": offset + limit,
+		"limit":  limit,
+		"offset": offset,
+	}).Scan(&activity).Error
+	return activity, err
+}
+
+// auditQuery reads the stock movements made in [@from, @to) with the item SKU and username.
+// Soft-deleted items are included, as their movements are still part of the audit trail.
+const auditQuery = `
+SELECT m.id, m.item_id, COALESCE(i.sku, '') AS sku, m.type, m.delta, m.quantity_after, m.reason,
+	m.user_id, COALESCE(u.username, '') AS username, m.impersonator_id, m.created_at
+FROM stock_movements m
+LEFT JOIN items i ON i.id = m.item_id
+LEFT JOIN users u ON u.id = m.user_id
+WHERE m.created_at >= @from AND m.created_at < @to
+ORDER BY m.created_at, m.id`
+
+// StreamMovements passes each stock movement made in [from, to) to fn, oldest first.
+// Rows are read from the database one at a time, so the range may be of any size.
+func (r *activityRepository) StreamMovements(ctx context.Context, from, to time.Time, fn func(record *models.AuditRecord) error) error {
+	db := conn(ctx, r.db)
+	rows, err := db.Raw(auditQuery, map[string]interface{}{"from": from, "to": to}).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record models.AuditRecord
+		if err := db.ScanRows(rows, &record); err != nil {
+			return err
+		}
+		if err := fn(&record); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"gorm.io/gorm"
+)
+
+// BundleRepository defines the interface for bundle data access
+type BundleRepository interface {
+	Create(ctx context.Context, bundle *models.Bundle) error
+	FindByID(ctx context.Context, id uint) (*models.Bundle, error)
+	List(ctx context.Context) ([]models.Bundle, error)
+}
+
+type bundleRepository struct {
+	db *gorm.DB
+}
+
+// NewBundleRepository creates a new bundle repository
+func NewBundleRepository(db *gorm.DB) BundleRepository {
+	return &bundleRepository{db: db}
+}
+
+// Create creates a bundle together with its lines
+func (r *bundleRepository) Create(ctx context.Context, bundle *models.Bundle) error {
+	return conn(ctx, r.db).Create(bundle).Error
+}
+
+// FindByID finds a bundle by ID with its lines in the order they were added
+func (r *bundleRepository) FindByID(ctx context.Context, id uint) (*models.Bundle, error) {
+	var bundle models.Bundle
+	err := conn(ctx, r.db).Preload("Lines", orderByID).First(&bundle, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// List lists every bundle with its lines, by name
+func (r *bundleRepository) List(ctx context.Context) ([]models.Bundle, error) {
+	var bundles []models.Bundle
+	err := conn(ctx, r.db).Preload("Lines", orderByID).Order("name").Order("id").Find(&bundles).Error
+	return bundles, err
+}
+
+// orderByID orders preloaded lines in the order they were added
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nielwyn/inventory-system/internal/metrics"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"github.com/sony/gobreaker"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrCircuitOpen is returned instead of running a query while the circuit breaker is open
+var ErrCircuitOpen = errors.New("database unavailable")
+
+// circuitBreakerName names the breaker as a GORM plugin
+const circuitBreakerName = "circuit_breaker"
+
+// circuitDoneKey holds the breaker's completion callback on a statement
+const circuitDoneKey = "circuit_breaker:done"
+
+// CircuitBreakerOptions configures the database circuit breaker
+type CircuitBreakerOptions struct {
+	// Failures is how many consecutive failed queries open the breaker
+	Failures int
+	// Cooldown is how long the breaker stays open before probing the database again
+	Cooldown time.Duration
+	// Probes is how many queries are let through while probing; the breaker closes
+	// once they all succeed and opens again on the first failure
+	Probes int
+}
+
+// CircuitBreaker is a GORM plugin that fails queries fast while the database is failing.
+// After Failures consecutive queries fail because the database is unreachable or
+// overloaded, every query returns ErrCircuitOpen for Cooldown instead of waiting on
+// the connection pool. Errors from a database that answered, such as constraint
+// violations or missing rows, do not count as failures.
+type CircuitBreaker struct {
+	breaker *gobreaker.TwoStepCircuitBreaker
+}
+
+// NewCircuitBreaker creates a circuit breaker; install it with db.Use
+func NewCircuitBreaker(opts CircuitBreakerOptions) *CircuitBreaker {
+	metrics.SetCircuitState(int(gobreaker.StateClosed))
+	return &CircuitBreaker{
+		breaker: gobreaker.NewTwoStepCircuitBreaker(gobreaker.Settings{
+			Name:        "database",
+			MaxRequests: uint32(opts.Probes),
+			Timeout:     opts.Cooldown,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures >= uint32(opts.Failures)
+			},
+			OnStateChange: func(_ string, from, to gobreaker.State) {
+				metrics.SetCircuitState(int(to))
+				logger.Warn("Database circuit breaker changed state",
+					zap.String("from", from.String()),
+					zap.String("to", to.String()),
+				)
+			},
+		}),
+	}
+}
+
+// CircuitBreakerFor returns the circuit breaker installed on db, or nil when there is none
+func CircuitBreakerFor(db *gorm.DB) *CircuitBreaker {
+	cb, _ := db.Config.Plugins[circuitBreakerName].(*CircuitBreaker)
+	return cb
+}
+
+// Name implements gorm.Plugin
+func (cb *CircuitBreaker) Name() string {
+	return circuitBreakerName
+}
+
+// Initialize implements gorm.Plugin by wrapping every kind of query
+func (cb *CircuitBreaker) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("circuit_breaker:before_create", cb.before),
+		callbacks.Create().After("gorm:create").Register("circuit_breaker:after_create", cb.after),
+		callbacks.Query().Before("gorm:query").Register("circuit_breaker:before_query", cb.before),
+		callbacks.Query().After("gorm:query").Register("circuit_breaker:after_query", cb.after),
+		callbacks.Update().Before("gorm:update").Register("circuit_breaker:before_update", cb.before),
+		callbacks.Update().After("gorm:update").Register("circuit_breaker:after_update", cb.after),
+		callbacks.Delete().Before("gorm:delete").Register("circuit_breaker:before_delete", cb.before),
+		callbacks.Delete().After("gorm:delete").Register("circuit_breaker:after_delete", cb.after),
+		callbacks.Row().Before("gorm:row").Register("circuit_breaker:before_row", cb.before),
+		callbacks.Row().After("gorm:row").Register("circuit_breaker:after_row", cb.after),
+		callbacks.Raw().Before("gorm:raw").Register("circuit_breaker:before_raw", cb.before),
+		callbacks.Raw().After("gorm:raw").Register("circuit_breaker:after_raw", cb.after),
+	)
+}
+
+// State returns the breaker state: "closed", "half-open" or "open"
+func (cb *CircuitBreaker) State() string {
+	return cb.breaker.State().String()
+}
+
+// Open reports whether the breaker is rejecting queries
+func (cb *CircuitBreaker) Open() bool {
+	return cb.breaker.State() == gobreaker.StateOpen
+}
+
+// before stops the query with ErrCircuitOpen when the breaker rejects it
+func (cb *CircuitBreaker) before(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	done, err := cb.breaker.Allow()
+	if err != nil {
+		metrics.RecordCircuitRejection()
+		db.AddError(ErrCircuitOpen)
+		return
+	}
+	db.InstanceSet(circuitDoneKey, done)
+}
+
+// after records whether the query reached a working database
+func (cb *CircuitBreaker) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(circuitDoneKey)
+	if !ok {
+		return
+	}
+	if done, ok := value.(func(bool)); ok {
+		db.InstanceSet(circuitDoneKey, nil)
+		done(!isUnavailable(db.Error))
+	}
+}
+
+// isUnavailable reports whether err means the database could not serve the query,
+// rather than refusing it
+func isUnavailable(err error) bool {
+	if err == nil || errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Connection exceptions, insufficient resources and operator intervention
+		switch pgErr.Code[:2] {
+		case "08", "53", "57":
+			return true
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// ContextWithTx returns a copy of ctx carrying a transaction.
+// Repositories called with the returned context run their queries inside tx.
+func ContextWithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+type impersonatorKey struct{}
+
+// ContextWithImpersonator returns a copy of ctx carrying the ID of an admin acting as the
+// user a request runs as. Stock movements and price changes written with the returned
+// context record it next to that user.
+func ContextWithImpersonator(ctx context.Context, impersonatorID uint) context.Context {
+	return context.WithValue(ctx, impersonatorKey{}, impersonatorID)
+}
+
+// ImpersonatorFromContext returns the impersonating admin carried by ctx, if any
+func ImpersonatorFromContext(ctx context.Context) (uint, bool) {
+	id, ok := ctx.Value(impersonatorKey{}).(uint)
+	return id, ok
+}
+
+// impersonatorFrom returns the impersonating admin carried by ctx, or nil when there is
+// none, for the impersonator_id columns
+func impersonatorFrom(ctx context.Context) *uint {
+	if id, ok := ImpersonatorFromContext(ctx); ok {
+		return &id
+	}
+	return nil
+}
+
+// conn returns the transaction carried by ctx, or db when there is none, bound to ctx
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// DeleteMode selects how repositories remove records
+type DeleteMode string
+
+// Supported delete modes
+const (
+	// SoftDelete sets deleted_at and keeps the row for auditing (default)
+	SoftDelete DeleteMode = "soft"
+	// HardDelete permanently removes the row, e.g. for GDPR erasure
+	HardDelete DeleteMode = "hard"
+)
+
+// ParseDeleteMode validates a delete mode string
+func ParseDeleteMode(mode string) (DeleteMode, error) {
+	switch DeleteMode(mode) {
+	case SoftDelete, HardDelete:
+		return DeleteMode(mode), nil
+	default:
+		return "", fmt.Errorf("unknown delete mode %q (expected %q or %q)", mode, SoftDelete, HardDelete)
+	}
+}
+
+// scope returns the session to delete with for the configured mode
+func (m DeleteMode) scope(db *gorm.DB) *gorm.DB {
+	if m == HardDelete {
+		return db.Unscoped()
+	}
+	return db
+}
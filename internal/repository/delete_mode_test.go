@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseDeleteMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    DeleteMode
+		wantErr bool
+	}{
+		{"soft", SoftDelete, false},
+		{"hard", HardDelete, false},
+		{"", "", true},
+		{"SOFT", "", true},
+		{"purge", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDeleteMode(tt.mode)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseDeleteMode(%q) = %q, %v; want %q, error %v", tt.mode, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDeleteModes(t *testing.T) {
+	tests := []struct {
+		mode       DeleteMode
+		wantPrefix string
+	}{
+		{SoftDelete, `UPDATE "%s" SET "deleted_at"=`},
+		{HardDelete, `DELETE FROM "%s" WHERE`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			db, statements := dryRun(t)
+			ctx := context.Background()
+			if err := NewInventoryRepository(db, tt.mode).Delete(ctx, 7); err != nil {
+				t.Fatalf("deleting item: %v", err)
+			}
+			if err := NewUserRepository(db, tt.mode).Delete(ctx, 7); err != nil {
+				t.Fatalf("deleting user: %v", err)
+			}
+
+			got := statements()
+			if len(got) != 2 {
+				t.Fatalf("built %d statements, want 2: %q", len(got), got)
+			}
+			for i, table := range []string{"items", "users"} {
+				prefix := strings.Replace(tt.wantPrefix, "%s", table, 1)
+				if !strings.HasPrefix(got[i], prefix) || !strings.Contains(got[i], `"id" = 7`) {
+					t.Errorf("%s delete = %s, want it to start %s and match ID 7", table, got[i], prefix)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres error codes
+const (
+	pgUniqueViolation = "23505"
+	pgCheckViolation  = "23514"
+	pgNumericOverflow = "22003"
+)
+
+// ErrDuplicateKey is returned when a write violates a unique constraint
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// ErrCheckViolation is returned when a write violates a check constraint
+var ErrCheckViolation = errors.New("check constraint violated")
+
+// ErrNumericOverflow is returned when a write stores a number too large for its column
+var ErrNumericOverflow = errors.New("numeric value out of range")
+
+// ErrInsufficientStock is returned when an adjustment would drive quantity below zero
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// ErrFractionalQuantity is returned when an adjustment would leave a fractional
+// quantity on an item counted individually
+var ErrFractionalQuantity = errors.New("fractional quantity")
+
+// ErrPriceBelowMinimum is returned when a bulk price update would take a price below the allowed minimum
+var ErrPriceBelowMinimum = errors.New("price below minimum")
+
+// DuplicateKeyError describes a unique constraint violation
+type DuplicateKeyError struct {
+	Constraint string
+	Err        error
+}
+
+// Error implements the error interface
+func (e *DuplicateKeyError) Error() string {
+	return "duplicate key violates unique constraint " + e.Constraint
+}
+
+// Unwrap allows errors.Is(err, ErrDuplicateKey)
+func (e *DuplicateKeyError) Unwrap() []error {
+	return []error{ErrDuplicateKey, e.Err}
+}
+
+// CheckViolationError describes a check constraint violation
+type CheckViolationError struct {
+	Constraint string
+	Err        error
+}
+
+// Error implements the error interface
+func (e *CheckViolationError) Error() string {
+	return "row violates check constraint " + e.Constraint
+}
+
+// Unwrap allows errors.Is(err, ErrCheckViolation)
+func (e *CheckViolationError) Unwrap() []error {
+	return []error{ErrCheckViolation, e.Err}
+}
+
+// translateError converts driver-specific errors into repository errors
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return &DuplicateKeyError{Constraint: pgErr.ConstraintName, Err: err}
+	case pgCheckViolation:
+		return &CheckViolationError{Constraint: pgErr.ConstraintName, Err: err}
+	case pgNumericOverflow:
+		return fmt.Errorf("%w: %w", ErrNumericOverflow, err)
+	}
+	return err
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestTranslateError(t *testing.T) {
+	other := errors.New("connection reset")
+
+	tests := []struct {
+		name           string
+		err            error
+		wantIs         error
+		wantConstraint string
+	}{
+		{"check violation", &pgconn.PgError{Code: pgCheckViolation, ConstraintName: "chk_items_price_nonnegative"}, ErrCheckViolation, "chk_items_price_nonnegative"},
+		{"unique violation", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "idx_items_sku_active"}, ErrDuplicateKey, "idx_items_sku_active"},
+		{"numeric overflow", &pgconn.PgError{Code: pgNumericOverflow}, ErrNumericOverflow, ""},
+		{"other driver error", &pgconn.PgError{Code: "42P01"}, nil, ""},
+		{"not a driver error", other, other, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err)
+			if tt.wantIs != nil && !errors.Is(got, tt.wantIs) {
+				t.Errorf("translateError = %v, want it to match %v", got, tt.wantIs)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("translateError = %v, want it to wrap the driver error", got)
+			}
+
+			var checkErr *CheckViolationError
+			var dupErr *DuplicateKeyError
+			switch {
+			case errors.As(got, &checkErr):
+				if checkErr.Constraint != tt.wantConstraint {
+					t.Errorf("constraint = %q, want %q", checkErr.Constraint, tt.wantConstraint)
+				}
+			case errors.As(got, &dupErr):
+				if dupErr.Constraint != tt.wantConstraint {
+					t.Errorf("constraint = %q, want %q", dupErr.Constraint, tt.wantConstraint)
+				}
+			}
+		})
+	}
+}
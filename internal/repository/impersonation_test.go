@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+func TestImpersonatorRecorded(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantValues string // the end of the price change's values: its impersonator
+	}{
+		{"direct", context.Background(), ",NULL) RETURNING"},
+		{"impersonated", ContextWithImpersonator(context.Background(), 1), ",1) RETURNING"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, statements := dryRun(t)
+			repo := NewInventoryRepository(db, SoftDelete)
+
+			priceChange := &models.PriceHistory{ItemID: 3, OldPrice: 1, NewPrice: 2, Reason: "reprice", UserID: 7}
+			if err := repo.Update(tt.ctx, &models.Item{ID: 3, Name: "Bolt", SKU: "BOLT"}, priceChange); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			all := statements()
+			if last := all[len(all)-1]; !strings.HasPrefix(last, `INSERT INTO "price_history"`) || !strings.Contains(last, tt.wantValues) {
+				t.Errorf("price change written as %s, want the values %s", last, tt.wantValues)
+			}
+		})
+	}
+}
@@ -1,47 +1,182 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/nielwyn/inventory-system/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // InventoryRepository handles inventory data operations
 type InventoryRepository interface {
-	Create(item *models.Item) error
-	FindAll() ([]models.Item, error)
-	FindByID(id uint) (*models.Item, error)
-	FindBySKU(sku string) (*models.Item, error)
-	Update(item *models.Item) error
-	Delete(id uint) error
+	Create(ctx context.Context, item *models.Item) error
+	CreateBatch(ctx context.Context, items []*models.Item) error
+	FindAll(ctx context.Context, opts ListOptions) ([]models.Item, error)
+	FindInBatches(ctx context.Context, opts ListOptions, batchSize int, fn func(items []models.Item) error) error
+	Count(ctx context.Context, opts ListOptions) (int64, error)
+	CountByCategory(ctx context.Context, opts ListOptions) ([]models.CategoryCount, error)
+	MarginByCategory(ctx context.Context) ([]models.CategoryMargin, error)
+	FindByID(ctx context.Context, id uint) (*models.Item, error)
+	FindByIDWithDeleted(ctx context.Context, id uint) (*models.Item, error)
+	FindByIDForUpdate(ctx context.Context, id uint) (*models.Item, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]models.Item, error)
+	FindBySKU(ctx context.Context, sku string) (*models.Item, error)
+	FindBySKUs(ctx context.Context, skus []string) ([]models.Item, error)
+	NextSKUSequence(ctx context.Context) (int64, error)
+	Update(ctx context.Context, item *models.Item, priceChange *models.PriceHistory) error
+	BulkUpdatePrices(ctx context.Context, update PriceUpdate) (int64, error)
+	AdjustQuantity(ctx context.Context, id uint, movement *models.StockMovement, allowNegative bool) (*models.Item, error)
+	SetQuantity(ctx context.Context, id uint, quantity float64, movement *models.StockMovement) (*models.Item, error)
+	Delete(ctx context.Context, id uint) error
+	SoftDelete(ctx context.Context, id uint) error
+	MergeReferences(ctx context.Context, sourceID uint, target *models.Item) error
+	ReassignCategory(ctx context.Context, from, to string) (int64, error)
+	DeleteByCategory(ctx context.Context, category string) (int64, error)
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// PriceUpdate describes a price change applied to every active item in a category
+type PriceUpdate struct {
+	Category  string
+	Operation string // one of models.PriceOperation*
+	Value     float64
+	MinPrice  float64
+	Reason    string
+	UserID    uint
 }
 
 type inventoryRepository struct {
-	db *gorm.DB
+	db         *gorm.DB
+	deleteMode DeleteMode
 }
 
 // NewInventoryRepository creates a new inventory repository
-func NewInventoryRepository(db *gorm.DB) InventoryRepository {
-	return &inventoryRepository{db: db}
+func NewInventoryRepository(db *gorm.DB, deleteMode DeleteMode) InventoryRepository {
+	return &inventoryRepository{db: db, deleteMode: deleteMode}
 }
 
 // Create creates a new item
-func (r *inventoryRepository) Create(item *models.Item) error {
-	return r.db.Create(item).Error
+func (r *inventoryRepository) Create(ctx context.Context, item *models.Item) error {
+	return translateError(conn(ctx, r.db).Create(item).Error)
+}
+
+// CreateBatch creates several items in a single transaction
+func (r *inventoryRepository) CreateBatch(ctx context.Context, items []*models.Item) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return translateError(tx.Create(items).Error)
+	})
 }
 
-// FindAll retrieves all items
-func (r *inventoryRepository) FindAll() ([]models.Item, error) {
+// FindAll retrieves the items matching opts, sorted and paginated as requested.
+// With opts.Fields set, columns outside it are left at their zero values.
+func (r *inventoryRepository) FindAll(ctx context.Context, opts ListOptions) ([]models.Item, error) {
 	var items []models.Item
-	err := r.db.Find(&items).Error
+	query, err := opts.applySort(opts.applyFilters(conn(ctx, r.db)))
+	if err != nil {
+		return nil, err
+	}
+	if query, err = opts.applySelect(query); err != nil {
+		return nil, err
+	}
+	err = opts.applyPage(query).Find(&items).Error
 	return items, err
 }
 
+// FindInBatches walks the items matching the filters of opts in ID order, passing batchSize
+// items at a time to fn. Sorting and pagination are ignored. It stops at the first error
+// returned by fn.
+func (r *inventoryRepository) FindInBatches(ctx context.Context, opts ListOptions, batchSize int, fn func(items []models.Item) error) error {
+	var items []models.Item
+	return opts.applyFilters(conn(ctx, r.db)).FindInBatches(&items, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(items)
+	}).Error
+}
+
+// Count returns the number of items matching the filters of opts, ignoring sorting and pagination
+func (r *inventoryRepository) Count(ctx context.Context, opts ListOptions) (int64, error) {
+	var count int64
+	err := opts.applyFilters(conn(ctx, r.db).Model(&models.Item{})).Count(&count).Error
+	return count, err
+}
+
+// CountByCategory returns the number of active items matching the filters of opts per
+// category, largest first. Sorting and pagination are ignored.
+func (r *inventoryRepository) CountByCategory(ctx context.Context, opts ListOptions) ([]models.CategoryCount, error) {
+	var counts []models.CategoryCount
+	err := opts.applyFilters(conn(ctx, r.db).Model(&models.Item{})).
+		Select("category, COUNT(*) AS count").
+		Group("category").
+		Order("count DESC, category").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// MarginByCategory returns the summed (price - cost_price) * quantity of active items per
+// category and unit, largest first
+func (r *inventoryRepository) MarginByCategory(ctx context.Context) ([]models.CategoryMargin, error) {
+	var margins []models.CategoryMargin
+	err := conn(ctx, r.db).Model(&models.Item{}).
+		Select("category, unit, COALESCE(SUM((price - cost_price) * quantity), 0) AS margin").
+		Group("category, unit").
+		Order("margin DESC, category, unit").
+		Scan(&margins).Error
+	return margins, err
+}
+
 // FindByID finds an item by ID
-func (r *inventoryRepository) FindByID(id uint) (*models.Item, error) {
+func (r *inventoryRepository) FindByID(ctx context.Context, id uint) (*models.Item, error) {
+	var item models.Item
+	err := conn(ctx, r.db).First(&item, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FindByIDWithDeleted finds an item by ID whether or not it has been soft-deleted
+func (r *inventoryRepository) FindByIDWithDeleted(ctx context.Context, id uint) (*models.Item, error) {
+	var item models.Item
+	err := conn(ctx, r.db).Unscoped().First(&item, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FindByIDForUpdate finds an item by ID and locks its row until the surrounding
+// transaction ends, so it cannot change between being read and written back
+func (r *inventoryRepository) FindByIDForUpdate(ctx context.Context, id uint) (*models.Item, error) {
+	var item models.Item
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FindByIDs finds the items matching any of the given IDs; missing IDs are skipped
+func (r *inventoryRepository) FindByIDs(ctx context.Context, ids []uint) ([]models.Item, error) {
+	var items []models.Item
+	err := conn(ctx, r.db).Where("id IN ?", ids).Find(&items).Error
+	return items, err
+}
+
+// FindBySKU finds an active item by SKU; soft-deleted items do not reserve their SKU
+func (r *inventoryRepository) FindBySKU(ctx context.Context, sku string) (*models.Item, error) {
 	var item models.Item
-	err := r.db.First(&item, id).Error
+	err := conn(ctx, r.db).Where("sku = ?", sku).First(&item).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -51,10 +186,142 @@ func (r *inventoryRepository) FindByID(id uint) (*models.Item, error) {
 	return &item, nil
 }
 
-// FindBySKU finds an item by SKU
-func (r *inventoryRepository) FindBySKU(sku string) (*models.Item, error) {
+// FindBySKUs finds the active items matching any of the given SKUs
+func (r *inventoryRepository) FindBySKUs(ctx context.Context, skus []string) ([]models.Item, error) {
+	var items []models.Item
+	err := conn(ctx, r.db).Where("sku IN ?", skus).Find(&items).Error
+	return items, err
+}
+
+// NextSKUSequence returns the next value of the SKU generation sequence
+func (r *inventoryRepository) NextSKUSequence(ctx context.Context) (int64, error) {
+	var seq int64
+	err := conn(ctx, r.db).Raw("SELECT nextval(?)", models.ItemSKUSequence).Scan(&seq).Error
+	return seq, err
+}
+
+// Update updates an existing item. When priceChange is not nil it is recorded in the
+// price history in the same transaction.
+func (r *inventoryRepository) Update(ctx context.Context, item *models.Item, priceChange *models.PriceHistory) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(item).Error; err != nil {
+			return translateError(err)
+		}
+		if priceChange != nil {
+			priceChange.ImpersonatorID = impersonatorFrom(ctx)
+			return tx.Create(priceChange).Error
+		}
+		return nil
+	})
+}
+
+// priceExpressions maps bulk price operations to the SQL computing the new price
+var priceExpressions = map[string]string{
+	models.PriceOperationPercent: "ROUND(price * (1 + CAST(@value AS numeric) / 100), 2)",
+	models.PriceOperationSet:     "ROUND(CAST(@value AS numeric), 2)",
+	models.PriceOperationAdd:     "ROUND(price + CAST(@value AS numeric), 2)",
+}
+
+// bulkPriceUpdateQuery updates the prices of a category and records the changes
+// in price_history in one statement, returning how many items it updated
+const bulkPriceUpdateQuery = `
+WITH updated AS (
+	UPDATE items SET price = %s, updated_at = @now
+	FROM (SELECT id, price AS old_price FROM items WHERE category = @category AND deleted_at IS NULL) AS old
+	WHERE items.id = old.id
+	RETURNING items.id, old.old_price, items.price AS new_price
+), history AS (
+	INSERT INTO price_history (item_id, old_price, new_price, reason, user_id, impersonator_id, created_at)
+	SELECT id, old_price, new_price, @reason, @user, @impersonator, @now FROM updated WHERE old_price <> new_price
+)
+SELECT COUNT(*) FROM updated`
+
+// BulkUpdatePrices applies a price operation to every active item in a category and records
+// the price history. The category's rows are locked first, and if any resulting price would
+// fall below update.MinPrice nothing is changed and ErrPriceBelowMinimum is returned.
+func (r *inventoryRepository) BulkUpdatePrices(ctx context.Context, update PriceUpdate) (int64, error) {
+	expr, ok := priceExpressions[update.Operation]
+	if !ok {
+		return 0, fmt.Errorf("unknown price operation %q", update.Operation)
+	}
+	args := map[string]interface{}{
+		"category":     update.Category,
+		"value":        update.Value,
+		"reason":       update.Reason,
+		"user":         update.UserID,
+		"impersonator": impersonatorFrom(ctx),
+	}
+
+	var updated int64
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		args["now"] = tx.NowFunc()
+		var lowest *float64
+		err := tx.Raw(fmt.Sprintf(
+			"SELECT MIN(%s) FROM (SELECT price FROM items WHERE category = @category AND deleted_at IS NULL FOR UPDATE) AS items",
+			expr), args).Scan(&lowest).Error
+		if err != nil {
+			return err
+		}
+		if lowest != nil && *lowest < update.MinPrice {
+			return ErrPriceBelowMinimum
+		}
+
+		return translateError(tx.Raw(fmt.Sprintf(bulkPriceUpdateQuery, expr), args).Scan(&updated).Error)
+	})
+	return updated, err
+}
+
+// AdjustQuantity atomically applies movement.Delta to an item's quantity and records the movement.
+// The item row is locked for the duration of the transaction so concurrent adjustments serialize.
+// It returns nil if the item does not exist and ErrFractionalQuantity if it would leave an item
+// counted individually with a fraction. A result below zero is rejected with ErrInsufficientStock
+// unless allowNegative is set, in which case the item is flagged as backordered.
+func (r *inventoryRepository) AdjustQuantity(ctx context.Context, id uint, movement *models.StockMovement, allowNegative bool) (*models.Item, error) {
+	return r.changeQuantity(ctx, id, movement, allowNegative, func(current float64) float64 {
+		return current + movement.Delta
+	})
+}
+
+// SetQuantity atomically sets an item's quantity and records the movement, with
+// movement.Delta computed from the quantity read under the row lock.
+// It returns nil if the item does not exist.
+func (r *inventoryRepository) SetQuantity(ctx context.Context, id uint, quantity float64, movement *models.StockMovement) (*models.Item, error) {
+	return r.changeQuantity(ctx, id, movement, false, func(float64) float64 {
+		return quantity
+	})
+}
+
+// changeQuantity locks an item, replaces its quantity with next(current) and records the
+// movement with the resulting delta
+func (r *inventoryRepository) changeQuantity(ctx context.Context, id uint, movement *models.StockMovement, allowNegative bool, next func(current float64) float64) (*models.Item, error) {
 	var item models.Item
-	err := r.db.Where("sku = ?", sku).First(&item).Error
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, id).Error; err != nil {
+			return err
+		}
+
+		newQuantity := models.RoundQuantity(next(item.Quantity))
+		if newQuantity < 0 && !allowNegative {
+			return ErrInsufficientStock
+		}
+		if !models.ValidQuantity(item.Unit, newQuantity) {
+			return ErrFractionalQuantity
+		}
+		movement.Delta = models.RoundQuantity(newQuantity - item.Quantity)
+
+		err := tx.Model(&item).Updates(map[string]interface{}{
+			"quantity":    newQuantity,
+			"backordered": newQuantity < 0,
+		}).Error
+		if err != nil {
+			return translateError(err)
+		}
+
+		movement.ItemID = item.ID
+		movement.QuantityAfter = newQuantity
+		movement.ImpersonatorID = impersonatorFrom(ctx)
+		return tx.Create(movement).Error
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -64,12 +331,80 @@ func (r *inventoryRepository) FindBySKU(sku string) (*models.Item, error) {
 	return &item, nil
 }
 
-// Update updates an existing item
-func (r *inventoryRepository) Update(item *models.Item) error {
-	return r.db.Save(item).Error
+// Delete removes an item by ID, softly or permanently depending on the delete mode
+func (r *inventoryRepository) Delete(ctx context.Context, id uint) error {
+	return r.deleteMode.scope(conn(ctx, r.db)).Delete(&models.Item{}, id).Error
+}
+
+// SoftDelete sets deleted_at on an item whatever the delete mode, for items that must stay
+// on record
+func (r *inventoryRepository) SoftDelete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&models.Item{}, id).Error
+}
+
+// MergeReferences moves everything that refers to the source item over to target: stock
+// movements, price history, tags, per-location stock (summed where both items are stocked),
+// purchase order lines and bundle lines (combined where a bundle holds both items).
+// Pending scheduled price changes of the source are cancelled rather than moved, since
+// they were set for the source's price. The items themselves are left unchanged.
+func (r *inventoryRepository) MergeReferences(ctx context.Context, sourceID uint, target *models.Item) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		args := map[string]interface{}{
+			"source":    sourceID,
+			"target":    target.ID,
+			"sku":       target.SKU,
+			"pending":   models.ScheduledPricePending,
+			"cancelled": models.ScheduledPriceCancelled,
+			"now":       tx.NowFunc(),
+		}
+		statements := []string{
+			`UPDATE stock_movements SET item_id = @target WHERE item_id = @source`,
+			`UPDATE price_history SET item_id = @target WHERE item_id = @source`,
+			`UPDATE scheduled_prices SET status = @cancelled WHERE item_id = @source AND status = @pending`,
+			`INSERT INTO item_tags (item_id, tag_id, created_at)
+				SELECT @target, tag_id, created_at FROM item_tags WHERE item_id = @source
+				ON CONFLICT DO NOTHING`,
+			`DELETE FROM item_tags WHERE item_id = @source`,
+			`INSERT INTO stock_levels (location_id, item_id, quantity, updated_at)
+				SELECT location_id, @target, quantity, @now FROM stock_levels WHERE item_id = @source
+				ON CONFLICT (location_id, item_id) DO UPDATE
+				SET quantity = stock_levels.quantity + EXCLUDED.quantity, updated_at = EXCLUDED.updated_at`,
+			`DELETE FROM stock_levels WHERE item_id = @source`,
+			`UPDATE purchase_order_lines SET item_id = @target, sku = @sku WHERE item_id = @source`,
+			`UPDATE bundle_lines AS t SET quantity = t.quantity + s.quantity
+				FROM bundle_lines AS s
+				WHERE t.item_id = @target AND s.item_id = @source AND s.bundle_id = t.bundle_id`,
+			`DELETE FROM bundle_lines AS s USING bundle_lines AS t
+				WHERE s.item_id = @source AND t.item_id = @target AND t.bundle_id = s.bundle_id`,
+			`UPDATE bundle_lines SET item_id = @target, sku = @sku WHERE item_id = @source`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement, args).Error; err != nil {
+				return translateError(err)
+			}
+		}
+		return nil
+	})
+}
+
+// ReassignCategory moves every active item in category from to category to and
+// returns how many items were moved
+func (r *inventoryRepository) ReassignCategory(ctx context.Context, from, to string) (int64, error) {
+	result := conn(ctx, r.db).Model(&models.Item{}).Where("category = ?", from).Update("category", to)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByCategory deletes every active item in a category according to the
+// repository's delete mode and returns how many items were deleted
+func (r *inventoryRepository) DeleteByCategory(ctx context.Context, category string) (int64, error) {
+	result := r.deleteMode.scope(conn(ctx, r.db)).Where("category = ?", category).Delete(&models.Item{})
+	return result.RowsAffected, result.Error
 }
 
-// Delete soft deletes an item by ID
-func (r *inventoryRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Item{}, id).Error
+// WithinTransaction runs fn in a transaction, joining the one carried by ctx if there is
+// one. Repository calls made with the context passed to fn take part in the transaction.
+func (r *inventoryRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return fn(ContextWithTx(ctx, tx))
+	})
 }
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidSort is returned for a sort on a field that is not in the allowlist
+var ErrInvalidSort = errors.New("unsortable field")
+
+// ErrInvalidField is returned for a selected field that is not in the allowlist
+var ErrInvalidField = errors.New("unknown field")
+
+// ListOptions describes which items a query returns, in what order and which page of them.
+// Zero values mean no filter, ID order and no pagination.
+type ListOptions struct {
+	Category string
+	Search   string
+
+	// IncludeDeleted also matches soft-deleted items
+	IncludeDeleted bool
+
+	Sort []SortField
+
+	// Fields limits the columns read, by item JSON name; empty reads every column
+	Fields []string
+
+	Offset int
+	Limit  int
+}
+
+// SortField orders results by one column
+type SortField struct {
+	Field string // a key of itemSortColumns
+	Desc  bool
+}
+
+// itemSortColumns is the allowlist of fields items can be sorted by, mapped to their columns.
+// Sort fields come from clients and are never interpolated into SQL unless listed here.
+var itemSortColumns = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"sku":        "sku",
+	"category":   "category",
+	"quantity":   "quantity",
+	"price":      "price",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// SortableFields returns the fields items can be sorted by, in alphabetical order
+func SortableFields() []string {
+	fields := make([]string, 0, len(itemSortColumns))
+	for field := range itemSortColumns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// ParseSort parses a comma-separated list of sort fields, each optionally prefixed
+// with "-" for descending order, such as "category,-price". The ID is appended as
+// the final key unless already present, so the order is total.
+func ParseSort(s string) ([]SortField, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var fields []SortField
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := itemSortColumns[field.Field]; !ok {
+			return nil, fmt.Errorf("%w %q", ErrInvalidSort, field.Field)
+		}
+		fields = append(fields, field)
+	}
+	return withTiebreaker(fields), nil
+}
+
+// withTiebreaker appends an ascending ID sort to fields unless they already sort by ID.
+// Without it rows with equal sort values come back in any order, and pages can
+// repeat or skip rows.
+func withTiebreaker(fields []SortField) []SortField {
+	for _, field := range fields {
+		if field.Field == "id" {
+			return fields
+		}
+	}
+	return append(fields, SortField{Field: "id"})
+}
+
+// likeEscaper escapes the LIKE wildcards in user input so it is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// applyFilters adds the filter conditions of opts. The search term is matched literally
+// anywhere in the name, SKU or description, so "%" and "_" are not wildcards.
+func (opts ListOptions) applyFilters(db *gorm.DB) *gorm.DB {
+	if opts.IncludeDeleted {
+		db = db.Unscoped()
+	}
+	if opts.Category != "" {
+		db = db.Where("category = ?", opts.Category)
+	}
+	if opts.Search != "" {
+		pattern := "%" + likeEscaper.Replace(opts.Search) + "%"
+		db = db.Where("name ILIKE ? OR sku ILIKE ? OR description ILIKE ?", pattern, pattern, pattern)
+	}
+	return db
+}
+
+// applySort adds the ordering of opts, ending with the ID tiebreaker.
+// Fields outside the allowlist are rejected.
+func (opts ListOptions) applySort(db *gorm.DB) (*gorm.DB, error) {
+	for _, field := range withTiebreaker(opts.Sort) {
+		column, ok := itemSortColumns[field.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrInvalidSort, field.Field)
+		}
+		if field.Desc {
+			column += " DESC"
+		}
+		db = db.Order(column)
+	}
+	return db, nil
+}
+
+// itemSelectColumns is the allowlist of item fields that can be selected, mapped to their columns
+var itemSelectColumns = map[string]string{
+	"id":          "id",
+	"name":        "name",
+	"sku":         "sku",
+	"description": "description",
+	"quantity":    "quantity",
+	"unit":        "unit",
+	"backordered": "backordered",
+	"price":       "price",
+	"cost_price":  "cost_price",
+	"category":    "category",
+	"created_at":  "created_at",
+	"updated_at":  "updated_at",
+}
+
+// applySelect limits the columns read to the fields of opts
+func (opts ListOptions) applySelect(db *gorm.DB) (*gorm.DB, error) {
+	if len(opts.Fields) == 0 {
+		return db, nil
+	}
+	columns := make([]string, len(opts.Fields))
+	for i, field := range opts.Fields {
+		column, ok := itemSelectColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrInvalidField, field)
+		}
+		columns[i] = column
+	}
+	return db.Select(columns), nil
+}
+
+// applyPage adds the offset and limit of opts
+func (opts ListOptions) applyPage(db *gorm.DB) *gorm.DB {
+	if opts.Limit > 0 {
+		db = db.Limit(opts.Limit)
+	}
+	if opts.Offset > 0 {
+		db = db.Offset(opts.Offset)
+	}
+	return db
+}
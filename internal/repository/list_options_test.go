@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		sort    string
+		want    []SortField
+		wantErr error
+	}{
+		{"", nil, nil},
+		{"name", []SortField{{Field: "name"}, {Field: "id"}}, nil},
+		{"category, -price", []SortField{{Field: "category"}, {Field: "price", Desc: true}, {Field: "id"}}, nil},
+		{"-created_at", []SortField{{Field: "created_at", Desc: true}, {Field: "id"}}, nil},
+		{"-id", []SortField{{Field: "id", Desc: true}}, nil},
+		{"id,name", []SortField{{Field: "id"}, {Field: "name"}}, nil},
+		{"cost_price", nil, ErrInvalidSort},
+		{"name;DROP TABLE items", nil, ErrInvalidSort},
+	}
+	for _, tt := range tests {
+		got, err := ParseSort(tt.sort)
+		if !errors.Is(err, tt.wantErr) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSort(%q) = %v, %v; want %v, %v", tt.sort, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFindAllOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		sort      []SortField
+		wantOrder string
+	}{
+		{"default", nil, `ORDER BY id LIMIT 10`},
+		{"by creation", []SortField{{Field: "created_at", Desc: true}}, `ORDER BY created_at DESC,id LIMIT 10`},
+		{"by ID descending", []SortField{{Field: "id", Desc: true}}, `ORDER BY id DESC LIMIT 10`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, statements := dryRun(t)
+			repo := NewInventoryRepository(db, SoftDelete)
+			if _, err := repo.FindAll(context.Background(), ListOptions{Sort: tt.sort, Limit: 10, Offset: 20}); err != nil {
+				t.Fatalf("FindAll: %v", err)
+			}
+			if got := statements(); len(got) != 1 || !strings.Contains(got[0], tt.wantOrder) {
+				t.Errorf("FindAll built %q, want it to contain %s", got, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestSearchMatchesLiterally(t *testing.T) {
+	tests := []struct {
+		search      string
+		wantPattern string
+	}{
+		{"bolt", `'%bolt%'`},
+		{"100%", `'%100\%%'`},
+		{"A_1", `'%A\_1%'`},
+		{`C:\parts`, `'%C:\\parts%'`},
+		{`%_\`, `'%\%\_\\%'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			db, statements := dryRun(t)
+			repo := NewInventoryRepository(db, SoftDelete)
+			if _, err := repo.FindAll(context.Background(), ListOptions{Search: tt.search}); err != nil {
+				t.Fatalf("FindAll: %v", err)
+			}
+			got := statements()
+			if len(got) != 1 || strings.Count(got[0], "ILIKE "+tt.wantPattern) != 3 {
+				t.Errorf("FindAll built %q, want name, SKU and description matched against %s", got, tt.wantPattern)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"gorm.io/gorm"
+)
+
+// LocationRepository defines the interface for location data access
+type LocationRepository interface {
+	FindByID(ctx context.Context, id uint) (*models.Location, error)
+	FindStock(ctx context.Context, locationID uint, opts ListOptions) ([]models.LocationStock, error)
+}
+
+type locationRepository struct {
+	db *gorm.DB
+}
+
+// NewLocationRepository creates a new location repository
+func NewLocationRepository(db *gorm.DB) LocationRepository {
+	return &locationRepository{db: db}
+}
+
+// FindByID finds a location by ID
+func (r *locationRepository) FindByID(ctx context.Context, id uint) (*models.Location, error) {
+	var location models.Location
+	err := conn(ctx, r.db).First(&location, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &location, nil
+}
+
+// FindStock retrieves the stock levels held at a location, joined with their active items
+// in one query. opts filters and pages on the item columns like the item list. The
+// stock_levels primary key (location_id, item_id) serves the lookup and the item ID order.
+func (r *locationRepository) FindStock(ctx context.Context, locationID uint, opts ListOptions) ([]models.LocationStock, error) {
+	var stock []models.LocationStock
+	query := conn(ctx, r.db).Table("stock_levels").
+		Select("stock_levels.item_id, items.sku, items.name, items.category, items.unit, items.price, stock_levels.quantity, stock_levels.updated_at").
+		Joins("JOIN items ON items.id = stock_levels.item_id AND items.deleted_at IS NULL").
+		Where("stock_levels.location_id = ?", locationID)
+	err := opts.applyPage(opts.applyFilters(query)).Order("stock_levels.item_id").Scan(&stock).Error
+	return stock, err
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRun returns a session that builds Postgres statements without a database, and
+// a function returning the statements built so far
+func dryRun(t *testing.T) (*gorm.DB, func() []string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &noConn{}}), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("opening dry-run session: %v", err)
+	}
+
+	var statements []string
+	record := func(tx *gorm.DB) {
+		statements = append(statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}
+	for _, register := range []func(string, func(*gorm.DB)) error{
+		db.Callback().Create().After("*").Register,
+		db.Callback().Query().After("*").Register,
+		db.Callback().Update().After("*").Register,
+		db.Callback().Delete().After("*").Register,
+		db.Callback().Raw().After("*").Register,
+	} {
+		if err := register("test:record", record); err != nil {
+			t.Fatalf("registering callback: %v", err)
+		}
+	}
+	return db, func() []string { return statements }
+}
+
+// errNoConn is returned by noConn for anything that would reach a database
+var errNoConn = errors.New("dry run: no database")
+
+// noConn is a connection pool for dry-run sessions. Statements are never sent to it,
+// and transactions begin and end without doing anything.
+type noConn struct{}
+
+func (*noConn) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errNoConn
+}
+
+func (*noConn) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errNoConn
+}
+
+func (*noConn) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errNoConn
+}
+
+func (*noConn) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (c *noConn) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return c, nil
+}
+
+func (*noConn) Commit() error {
+	return nil
+}
+
+func (*noConn) Rollback() error {
+	return nil
+}
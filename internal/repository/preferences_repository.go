@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"gorm.io/gorm"
+)
+
+// PreferencesRepository defines the interface for user preference data access
+type PreferencesRepository interface {
+	FindByUserID(ctx context.Context, userID uint) (*models.UserPreferences, error)
+	Save(ctx context.Context, prefs *models.UserPreferences) error
+}
+
+type preferencesRepository struct {
+	db *gorm.DB
+}
+
+// NewPreferencesRepository creates a new preferences repository
+func NewPreferencesRepository(db *gorm.DB) PreferencesRepository {
+	return &preferencesRepository{db: db}
+}
+
+// FindByUserID finds a user's preferences; it returns nil if the user has never set any
+func (r *preferencesRepository) FindByUserID(ctx context.Context, userID uint) (*models.UserPreferences, error) {
+	var prefs models.UserPreferences
+	err := conn(ctx, r.db).First(&prefs, "user_id = ?", userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// Save creates or replaces a user's preferences
+func (r *preferencesRepository) Save(ctx context.Context, prefs *models.UserPreferences) error {
+	return conn(ctx, r.db).Save(prefs).Error
+}
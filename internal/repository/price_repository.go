@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PriceRepository handles price history and scheduled price data operations
+type PriceRepository interface {
+	CreateScheduled(ctx context.Context, scheduled *models.ScheduledPrice) error
+	FindScheduledByID(ctx context.Context, id uint) (*models.ScheduledPrice, error)
+	FindPendingByItem(ctx context.Context, itemID uint) ([]models.ScheduledPrice, error)
+	CancelScheduled(ctx context.Context, id uint) (bool, error)
+	ApplyDue(ctx context.Context, now time.Time) (int, error)
+	FindHistoryByItem(ctx context.Context, itemID uint) ([]models.PriceHistory, error)
+}
+
+type priceRepository struct {
+	db *gorm.DB
+}
+
+// NewPriceRepository creates a new price repository
+func NewPriceRepository(db *gorm.DB) PriceRepository {
+	return &priceRepository{db: db}
+}
+
+// CreateScheduled creates a new scheduled price change
+func (r *priceRepository) CreateScheduled(ctx context.Context, scheduled *models.ScheduledPrice) error {
+	return conn(ctx, r.db).Create(scheduled).Error
+}
+
+// FindScheduledByID finds a scheduled price change by ID
+func (r *priceRepository) FindScheduledByID(ctx context.Context, id uint) (*models.ScheduledPrice, error) {
+	var scheduled models.ScheduledPrice
+	err := conn(ctx, r.db).First(&scheduled, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &scheduled, nil
+}
+
+// FindPendingByItem retrieves an item's pending price changes, soonest first
+func (r *priceRepository) FindPendingByItem(ctx context.Context, itemID uint) ([]models.ScheduledPrice, error) {
+	var scheduled []models.ScheduledPrice
+	err := conn(ctx, r.db).Where("item_id = ? AND status = ?", itemID, models.ScheduledPricePending).
+		Order("effective_at").
+		Find(&scheduled).Error
+	return scheduled, err
+}
+
+// CancelScheduled cancels a pending price change and reports whether one was cancelled
+func (r *priceRepository) CancelScheduled(ctx context.Context, id uint) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.ScheduledPrice{}).
+		Where("id = ? AND status = ?", id, models.ScheduledPricePending).
+		Update("status", models.ScheduledPriceCancelled)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ApplyDue applies every pending price change whose effective time has passed, recording
+// each in the price history. Due rows are locked with SKIP LOCKED so several replicas can
+// run the scheduler without applying the same change twice. It returns the number applied.
+func (r *priceRepository) ApplyDue(ctx context.Context, now time.Time) (int, error) {
+	applied := 0
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var due []models.ScheduledPrice
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND effective_at <= ?", models.ScheduledPricePending, now).
+			Order("effective_at").
+			Find(&due).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range due {
+			scheduled := &due[i]
+
+			var item models.Item
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, scheduled.ItemID).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// The item was deleted after the change was scheduled
+				if err := tx.Model(scheduled).Update("status", models.ScheduledPriceCancelled).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			history := &models.PriceHistory{
+				ItemID:   item.ID,
+				OldPrice: item.Price,
+				NewPrice: scheduled.Price,
+				Reason:   "scheduled price change",
+				UserID:   scheduled.UserID,
+			}
+			if err := tx.Create(history).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&item).Update("price", scheduled.Price).Error; err != nil {
+				return err
+			}
+			err = tx.Model(scheduled).Updates(map[string]interface{}{
+				"status":     models.ScheduledPriceApplied,
+				"applied_at": now,
+			}).Error
+			if err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return applied, nil
+}
+
+// FindHistoryByItem retrieves an item's price history, newest first
+func (r *priceRepository) FindHistoryByItem(ctx context.Context, itemID uint) ([]models.PriceHistory, error) {
+	var history []models.PriceHistory
+	err := conn(ctx, r.db).Where("item_id = ?", itemID).Order("created_at DESC").Find(&history).Error
+	return history, err
+}
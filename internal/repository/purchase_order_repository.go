@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PurchaseOrderRepository defines the interface for purchase order data access
+type PurchaseOrderRepository interface {
+	Create(ctx context.Context, po *models.PurchaseOrder) error
+	FindByID(ctx context.Context, id uint) (*models.PurchaseOrder, error)
+	FindByIDForUpdate(ctx context.Context, id uint) (*models.PurchaseOrder, error)
+	UpdateReceived(ctx context.Context, po *models.PurchaseOrder) error
+}
+
+type purchaseOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewPurchaseOrderRepository creates a new purchase order repository
+func NewPurchaseOrderRepository(db *gorm.DB) PurchaseOrderRepository {
+	return &purchaseOrderRepository{db: db}
+}
+
+// Create creates a purchase order together with its lines
+func (r *purchaseOrderRepository) Create(ctx context.Context, po *models.PurchaseOrder) error {
+	return conn(ctx, r.db).Create(po).Error
+}
+
+// FindByID finds a purchase order by ID with its lines in the order they were added
+func (r *purchaseOrderRepository) FindByID(ctx context.Context, id uint) (*models.PurchaseOrder, error) {
+	return r.find(conn(ctx, r.db), id)
+}
+
+// FindByIDForUpdate finds a purchase order like FindByID and locks it until the
+// surrounding transaction ends, so concurrent receipts against it serialize
+func (r *purchaseOrderRepository) FindByIDForUpdate(ctx context.Context, id uint) (*models.PurchaseOrder, error) {
+	return r.find(conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func (r *purchaseOrderRepository) find(db *gorm.DB, id uint) (*models.PurchaseOrder, error) {
+	var po models.PurchaseOrder
+	err := db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&po, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &po, nil
+}
+
+// UpdateReceived saves the received quantity of every line and the order's status
+func (r *purchaseOrderRepository) UpdateReceived(ctx context.Context, po *models.PurchaseOrder) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, line := range po.Lines {
+			err := tx.Model(&models.PurchaseOrderLine{}).Where("id = ?", line.ID).
+				Update("received_quantity", line.ReceivedQuantity).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(po).Update("status", po.Status).Error
+	})
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tables the retention purge can delete old rows from
+const (
+	RetentionStockMovements = "stock_movements"
+	RetentionPriceHistory   = "price_history"
+)
+
+// retentionTables is the allowlist of purgeable tables; table names are interpolated
+// into SQL, so nothing outside it is accepted
+var retentionTables = map[string]bool{
+	RetentionStockMovements: true,
+	RetentionPriceHistory:   true,
+}
+
+// RetentionRepository defines the interface for deleting expired history
+type RetentionRepository interface {
+	PurgeBatch(ctx context.Context, table string, before time.Time, limit int) (int64, error)
+}
+
+type retentionRepository struct {
+	db *gorm.DB
+}
+
+// NewRetentionRepository creates a new retention repository
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+// PurgeBatch hard-deletes up to limit rows of table created before before, oldest first,
+// and returns how many it deleted. Each call is one short statement, so a large purge done
+// in batches never holds locks for long.
+func (r *retentionRepository) PurgeBatch(ctx context.Context, table string, before time.Time, limit int) (int64, error) {
+	if !retentionTables[table] {
+		return 0, fmt.Errorf("table %q is not subject to retention", table)
+	}
+	result := conn(ctx, r.db).Exec(
+		"DELETE FROM "+table+" WHERE id IN (SELECT id FROM "+table+" WHERE created_at < ? ORDER BY id LIMIT ?)",
+		before, limit,
+	)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPurgeBatch(t *testing.T) {
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, table := range []string{RetentionStockMovements, RetentionPriceHistory} {
+		t.Run(table, func(t *testing.T) {
+			db, statements := dryRun(t)
+			if _, err := NewRetentionRepository(db).PurgeBatch(context.Background(), table, before, 500); err != nil {
+				t.Fatalf("PurgeBatch: %v", err)
+			}
+			want := "DELETE FROM " + table + " WHERE id IN (SELECT id FROM " + table + " WHERE created_at < '2024-01-01 00:00:00' ORDER BY id LIMIT 500)"
+			if got := statements(); len(got) != 1 || !strings.HasPrefix(got[0], want) {
+				t.Errorf("built %q, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestPurgeBatchRejectsOtherTables(t *testing.T) {
+	db, statements := dryRun(t)
+	for _, table := range []string{"items", "users; DROP TABLE items"} {
+		if _, err := NewRetentionRepository(db).PurgeBatch(context.Background(), table, time.Now(), 10); err == nil {
+			t.Errorf("PurgeBatch(%q) succeeded, want an error", table)
+		}
+	}
+	if got := statements(); len(got) != 0 {
+		t.Errorf("built %q for tables outside the allowlist", got)
+	}
+}
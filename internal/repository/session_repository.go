@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"gorm.io/gorm"
+)
+
+// SessionRepository defines the interface for session data access
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	FindByID(ctx context.Context, id string) (*models.Session, error)
+	ListActive(ctx context.Context, userID uint, now time.Time) ([]models.Session, error)
+	Touch(ctx context.Context, id string, now time.Time, interval time.Duration) error
+	Revoke(ctx context.Context, userID uint, id string, now time.Time) (bool, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create records a new session, first removing the user's sessions that have expired
+// or been revoked so the table does not grow without bound
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session) error {
+	db := conn(ctx, r.db)
+	err := db.Where("user_id = ? AND (expires_at <= ? OR revoked_at IS NOT NULL)", session.UserID, session.CreatedAt).
+		Delete(&models.Session{}).Error
+	if err != nil {
+		return err
+	}
+	return translateError(db.Create(session).Error)
+}
+
+// FindByID finds a session by ID; it returns nil if there is none
+func (r *sessionRepository) FindByID(ctx context.Context, id string) (*models.Session, error) {
+	var session models.Session
+	err := conn(ctx, r.db).First(&session, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActive lists a user's sessions that are neither revoked nor expired, most recently used first
+func (r *sessionRepository) ListActive(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC").Order("id").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Touch records that a session was used at now. The write is skipped while the last
+// recorded use is more recent than interval, so busy sessions are not updated on every request.
+func (r *sessionRepository) Touch(ctx context.Context, id string, now time.Time, interval time.Duration) error {
+	return conn(ctx, r.db).Model(&models.Session{}).
+		Where("id = ? AND last_used_at < ?", id, now.Add(-interval)).
+		Update("last_used_at", now).Error
+}
+
+// Revoke revokes one of a user's active sessions and reports whether there was one to revoke
+func (r *sessionRepository) Revoke(ctx context.Context, userID uint, id string, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", id, userID, now).
+		Update("revoked_at", now)
+	return result.RowsAffected > 0, result.Error
+}
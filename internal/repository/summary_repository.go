@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"gorm.io/gorm"
+)
+
+// SummaryRepository computes aggregate figures across tables
+type SummaryRepository interface {
+	AdminSummary(ctx context.Context, lowStockThreshold float64, since time.Time) (*models.AdminSummary, error)
+	InventoryValue(ctx context.Context) (float64, error)
+	BusinessReport(ctx context.Context, lowStockThreshold float64, lowStockLimit int) (*models.BusinessReport, error)
+}
+
+type summaryRepository struct {
+	db *gorm.DB
+}
+
+// NewSummaryRepository creates a new summary repository
+func NewSummaryRepository(db *gorm.DB) SummaryRepository {
+	return &summaryRepository{db: db}
+}
+
+// adminSummaryQuery computes every dashboard figure in one round trip. The item figures
+// share one scan of the active items; the others are plain counts.
+const adminSummaryQuery = `
+SELECT
+	items.total_items, items.total_skus, items.inventory_value, items.low_stock_count,
+	(SELECT COUNT(*) FROM users WHERE deleted_at IS NULL) AS total_users,
+	(SELECT COUNT(*) FROM stock_movements WHERE created_at >= @since)
+		+ (SELECT COUNT(*) FROM price_history WHERE created_at >= @since) AS recent_activity_count
+FROM (
+	SELECT COUNT(*) AS total_items,
+		COUNT(DISTINCT sku) AS total_skus,
+		COALESCE(SUM(price * quantity), 0) AS inventory_value,
+		COUNT(*) FILTER (WHERE quantity <= @threshold) AS low_stock_count
+	FROM items WHERE deleted_at IS NULL
+) AS items`
+
+// AdminSummary returns the dashboard figures, counting items at or below lowStockThreshold
+// as low on stock and activity recorded since since
+func (r *summaryRepository) AdminSummary(ctx context.Context, lowStockThreshold float64, since time.Time) (*models.AdminSummary, error) {
+	summary := &models.AdminSummary{LowStockThreshold: lowStockThreshold, ActivitySince: since}
+	err := conn(ctx, r.db).Raw(adminSummaryQuery, map[string]interface{}{
+		"threshold": lowStockThreshold,
+		"since":     since,
+	}).Scan(summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// InventoryValue returns the total value of active stock, the sum of price * quantity
+func (r *summaryRepository) InventoryValue(ctx context.Context) (float64, error) {
+	var value float64
+	err := conn(ctx, r.db).Raw("SELECT COALESCE(SUM(price * quantity), 0) FROM items WHERE deleted_at IS NULL").
+		Scan(&value).Error
+	return value, err
+}
+
+// BusinessReport computes the report figures with four queries: the totals, the quantity
+// per unit, one row per category and unit, and the lowest-stocked items at or below
+// lowStockThreshold, at most lowStockLimit of them
+func (r *summaryRepository) BusinessReport(ctx context.Context, lowStockThreshold float64, lowStockLimit int) (*models.BusinessReport, error) {
+	db := conn(ctx, r.db)
+	report := &models.BusinessReport{LowStockThreshold: lowStockThreshold}
+	err := db.Raw(`
+SELECT COUNT(*) AS total_items,
+	COUNT(DISTINCT sku) AS total_skus,
+	COALESCE(SUM(price * quantity), 0) AS inventory_value,
+	COUNT(*) FILTER (WHERE quantity <= @threshold) AS low_stock_count
+FROM items WHERE deleted_at IS NULL`, map[string]interface{}{"threshold": lowStockThreshold}).
+		Scan(report).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Model(&models.Item{}).
+		Select("unit, SUM(quantity) AS quantity").
+		Group("unit").Order("unit").
+		Scan(&report.Quantities).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Model(&models.Item{}).
+		Select("category, unit, COUNT(*) AS items, SUM(quantity) AS quantity, SUM(price * quantity) AS inventory_value").
+		Group("category, unit").Order("category").Order("unit").
+		Scan(&report.Categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Model(&models.Item{}).
+		Select("id, sku, name, category, quantity, unit").
+		Where("quantity <= ?", lowStockThreshold).
+		Order("quantity").Order("id").
+		Limit(lowStockLimit).
+		Scan(&report.LowStock).Error
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository defines the interface for tag data access
+type TagRepository interface {
+	FindByName(ctx context.Context, name string) (*models.Tag, error)
+	Assign(ctx context.Context, name string, itemIDs []uint) (int64, error)
+	Unassign(ctx context.Context, tagID uint, itemIDs []uint) (int64, error)
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// FindByName finds a tag by name
+func (r *tagRepository) FindByName(ctx context.Context, name string) (*models.Tag, error) {
+	var tag models.Tag
+	err := conn(ctx, r.db).Where("name = ?", name).First(&tag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// assignTagQuery tags the listed active items that do not have the tag yet
+const assignTagQuery = `
+INSERT INTO item_tags (item_id, tag_id, created_at)
+SELECT id, @tag, @now FROM items WHERE id IN @items AND deleted_at IS NULL
+ON CONFLICT DO NOTHING`
+
+// Assign attaches the named tag to the listed items in one transaction, creating the
+// tag if it does not exist. It returns how many items were newly tagged; missing items
+// and items that already had the tag are skipped.
+func (r *tagRepository) Assign(ctx context.Context, name string, itemIDs []uint) (int64, error) {
+	var affected int64
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		tag := models.Tag{Name: name}
+		err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(&tag).Error
+		if err != nil {
+			return err
+		}
+		if tag.ID == 0 {
+			if err := tx.Where("name = ?", name).First(&tag).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Exec(assignTagQuery, map[string]interface{}{
+			"tag":   tag.ID,
+			"items": itemIDs,
+			"now":   tx.NowFunc(),
+		})
+		affected = result.RowsAffected
+		return result.Error
+	})
+	return affected, err
+}
+
+// Unassign removes a tag from the listed items and returns how many items lost it
+func (r *tagRepository) Unassign(ctx context.Context, tagID uint, itemIDs []uint) (int64, error) {
+	result := conn(ctx, r.db).Where("tag_id = ? AND item_id IN ?", tagID, itemIDs).Delete(&models.ItemTag{})
+	return result.RowsAffected, result.Error
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"strings"
 
 	"github.com/nielwyn/inventory-system/internal/models"
 	"gorm.io/gorm"
@@ -9,30 +11,43 @@ import (
 
 // UserRepository handles user data operations
 type UserRepository interface {
-	Create(user *models.User) error
-	FindByUsername(username string) (*models.User, error)
-	FindByEmail(email string) (*models.User, error)
-	FindByID(id uint) (*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	FindByUsername(ctx context.Context, username string) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	Delete(ctx context.Context, id uint) error
+	Search(ctx context.Context, search UserSearch) ([]models.User, error)
+	RecentPasswordHashes(ctx context.Context, userID uint, limit int) ([]string, error)
+	ChangePassword(ctx context.Context, user *models.User, hash string, keep int) error
+}
+
+// UserSearch holds the options for searching users
+type UserSearch struct {
+	Prefix     string // case-insensitive prefix of the username or email; empty matches all
+	NewestLast bool   // sort by created_at ascending instead of descending
+	Offset     int
+	Limit      int
 }
 
 type userRepository struct {
-	db *gorm.DB
+	db         *gorm.DB
+	deleteMode DeleteMode
 }
 
 // NewUserRepository creates a new user repository
-func NewUserRepository(db *gorm.DB) UserRepository {
-	return &userRepository{db: db}
+func NewUserRepository(db *gorm.DB, deleteMode DeleteMode) UserRepository {
+	return &userRepository{db: db, deleteMode: deleteMode}
 }
 
 // Create creates a new user
-func (r *userRepository) Create(user *models.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return translateError(conn(ctx, r.db).Create(user).Error)
 }
 
-// FindByUsername finds a user by username
-func (r *userRepository) FindByUsername(username string) (*models.User, error) {
+// FindByUsername finds a user by username, ignoring surrounding whitespace
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
 	var user models.User
-	err := r.db.Where("username = ?", username).First(&user).Error
+	err := conn(ctx, r.db).Where("username = ?", models.NormalizeUsername(username)).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -42,10 +57,10 @@ func (r *userRepository) FindByUsername(username string) (*models.User, error) {
 	return &user, nil
 }
 
-// FindByEmail finds a user by email
-func (r *userRepository) FindByEmail(email string) (*models.User, error) {
+// FindByEmail finds a user by email, ignoring case and surrounding whitespace
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := conn(ctx, r.db).Where("email = ?", models.NormalizeEmail(email)).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -56,9 +71,9 @@ func (r *userRepository) FindByEmail(email string) (*models.User, error) {
 }
 
 // FindByID finds a user by ID
-func (r *userRepository) FindByID(id uint) (*models.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
 	var user models.User
-	err := r.db.First(&user, id).Error
+	err := conn(ctx, r.db).First(&user, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -67,3 +82,64 @@ func (r *userRepository) FindByID(id uint) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+// Delete removes a user by ID, softly or permanently depending on the delete mode
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	return r.deleteMode.scope(conn(ctx, r.db)).Delete(&models.User{}, id).Error
+}
+
+// RecentPasswordHashes returns the hashes of the last limit passwords the user replaced, newest first
+func (r *userRepository) RecentPasswordHashes(ctx context.Context, userID uint, limit int) ([]string, error) {
+	var hashes []string
+	err := conn(ctx, r.db).Model(&models.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Pluck("hash", &hashes).Error
+	return hashes, err
+}
+
+// ChangePassword sets the user's password hash and moves the old one into the password
+// history, which is pruned to the newest keep entries. A keep of zero keeps no history.
+func (r *userRepository) ChangePassword(ctx context.Context, user *models.User, hash string, keep int) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if keep > 0 {
+			entry := &models.PasswordHistory{UserID: user.ID, Hash: user.Password}
+			if err := tx.Create(entry).Error; err != nil {
+				return err
+			}
+		}
+		stale := tx.Model(&models.PasswordHistory{}).
+			Select("id").
+			Where("user_id = ?", user.ID).
+			Order("created_at DESC, id DESC").
+			Offset(keep)
+		if err := tx.Where("id IN (?)", stale).Delete(&models.PasswordHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(user).Update("password", hash).Error; err != nil {
+			return err
+		}
+		user.Password = hash
+		return nil
+	})
+}
+
+// Search finds users whose username or email starts with the prefix, ignoring case.
+// The match is on LOWER(column) LIKE 'prefix%' so it can use the lower-case pattern
+// indexes on both columns rather than scanning the table.
+func (r *userRepository) Search(ctx context.Context, search UserSearch) ([]models.User, error) {
+	var users []models.User
+	query := conn(ctx, r.db)
+	if search.Prefix != "" {
+		pattern := likeEscaper.Replace(strings.ToLower(search.Prefix)) + "%"
+		query = query.Where("LOWER(username) LIKE ? OR LOWER(email) LIKE ?", pattern, pattern)
+	}
+	if search.NewestLast {
+		query = query.Order("created_at, id")
+	} else {
+		query = query.Order("created_at DESC, id DESC")
+	}
+	err := query.Offset(search.Offset).Limit(search.Limit).Find(&users).Error
+	return users, err
+}
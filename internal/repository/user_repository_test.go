@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestUserLookupsNormalized(t *testing.T) {
+	tests := []struct {
+		name   string
+		lookup func(r UserRepository) error
+		want   string
+	}{
+		{"by email", func(r UserRepository) error {
+			_, err := r.FindByEmail(context.Background(), " Alice@Example.COM ")
+			return err
+		}, `email = 'alice@example.com'`},
+		{"by username", func(r UserRepository) error {
+			_, err := r.FindByUsername(context.Background(), "  Alice ")
+			return err
+		}, `username = 'Alice'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, statements := dryRun(t)
+			if err := tt.lookup(NewUserRepository(db, SoftDelete)); err != nil {
+				t.Fatalf("lookup: %v", err)
+			}
+			if got := statements(); len(got) != 1 || !strings.Contains(got[0], tt.want) {
+				t.Errorf("built %q, want it to match %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// ActivityService handles user activity business logic
+type ActivityService interface {
+	GetUserActivity(ctx context.Context, userID uint, query *models.ActivityQuery) ([]models.Activity, error)
+	ExportAudit(ctx context.Context, query *models.AuditExportQuery, fn func(record *models.AuditRecord) error) error
+}
+
+type activityService struct {
+	repo repository.ActivityRepository
+}
+
+// NewActivityService creates a new activity service
+func NewActivityService(repo repository.ActivityRepository) ActivityService {
+	return &activityService{repo: repo}
+}
+
+// GetUserActivity retrieves a page of a user's stock movements and price changes, newest first
+func (s *activityService) GetUserActivity(ctx context.Context, userID uint, query *models.ActivityQuery) ([]models.Activity, error) {
+	return s.repo.FindByUser(ctx, userID, query.Offset(), query.PageSize)
+}
+
+// ExportAudit passes every stock movement made from the start of query.From to the end of
+// query.To to fn, oldest first
+func (s *activityService) ExportAudit(ctx context.Context, query *models.AuditExportQuery, fn func(record *models.AuditRecord) error) error {
+	return s.repo.StreamMovements(ctx, query.From, query.To.AddDate(0, 0, 1), fn)
+}
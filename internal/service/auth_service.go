@@ -1,10 +1,14 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/nielwyn/inventory-system/internal/metrics"
 	"github.com/nielwyn/inventory-system/internal/models"
 	"github.com/nielwyn/inventory-system/internal/repository"
 	"golang.org/x/crypto/bcrypt"
@@ -12,45 +16,103 @@ import (
 
 // AuthService handles authentication business logic
 type AuthService interface {
-	Register(req *models.RegisterRequest) (*models.User, error)
-	Login(req *models.LoginRequest) (*models.LoginResponse, error)
+	Register(ctx context.Context, req *models.RegisterRequest) (*models.User, error)
+	Login(ctx context.Context, req *models.LoginRequest, client models.SessionClient) (*models.LoginResponse, error)
 	ValidateToken(tokenString string) (*jwt.Token, error)
 	GetUserFromToken(token *jwt.Token) (uint, error)
+	GetRoleFromToken(token *jwt.Token) string
+	GetTokenType(token *jwt.Token) string
+	Introspect(ctx context.Context, tokenString string) (*models.TokenIntrospection, error)
+	ChangePassword(ctx context.Context, userID uint, req *models.ChangePasswordRequest) error
+	CheckSession(ctx context.Context, token *jwt.Token, userID uint) (string, error)
+	ListSessions(ctx context.Context, userID uint, currentID string) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID uint, id string) error
+	Impersonate(ctx context.Context, actorID, targetID uint, client models.SessionClient) (*models.ImpersonationResponse, error)
+	GetImpersonatorFromToken(token *jwt.Token) (uint, bool)
+}
+
+// Token types carried in the token_type claim
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// Login response user detail levels
+const (
+	LoginUserFull    = "full"
+	LoginUserSummary = "summary"
+)
+
+// AuthOptions holds the token and response settings for the auth service
+type AuthOptions struct {
+	JWTSecret      string
+	JWTExpiryHours int
+
+	// JWTKeyID is sent as the kid header of issued tokens so validators can pick the
+	// secret; empty issues tokens without a kid. JWTPreviousKeys holds retired secrets by
+	// key ID: they no longer sign tokens but still validate those issued before rotation.
+	JWTKeyID        string
+	JWTPreviousKeys map[string]string
+
+	// ExtraClaims are added to every issued token and ClaimsHook, when set, adds claims
+	// for the user the token is issued to. Neither can replace a reserved claim.
+	ExtraClaims map[string]string
+	ClaimsHook  ClaimsHook
+
+	// JWTLeeway is the clock skew tolerated when validating token timestamps
+	JWTLeeway time.Duration
+
+	// LoginUserDetail selects whether login returns the full user or only a summary
+	LoginUserDetail string
+
+	// PasswordHistory is how many of a user's latest passwords, the current one included,
+	// a new password must differ from (0 or 1 only rules out the current one)
+	PasswordHistory int
+
+	// ImpersonationTTL is how long a token issued to impersonate a user is valid
+	ImpersonationTTL time.Duration
 }
 
 type authService struct {
-	userRepo  repository.UserRepository
-	jwtSecret string
-	jwtExpiry int
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	opts        AuthOptions
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo repository.UserRepository, jwtSecret string, jwtExpiry int) AuthService {
+func NewAuthService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, opts AuthOptions) AuthService {
 	return &authService{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
-		jwtExpiry: jwtExpiry,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		opts:        opts,
 	}
 }
 
-// Register registers a new user
-func (s *authService) Register(req *models.RegisterRequest) (*models.User, error) {
+// Register registers a new user. The username is trimmed and the email trimmed and
+// lower-cased before they are checked and stored, so the uniqueness checks and the
+// unique indexes see the normalized form.
+func (s *authService) Register(ctx context.Context, req *models.RegisterRequest) (_ *models.User, err error) {
+	defer func() { metrics.RecordRegister(err) }()
+
+	req.Username = models.NormalizeUsername(req.Username)
+	req.Email = models.NormalizeEmail(req.Email)
+
 	// Check if username already exists
-	existingUser, err := s.userRepo.FindByUsername(req.Username)
+	existingUser, err := s.userRepo.FindByUsername(ctx, req.Username)
 	if err != nil {
 		return nil, err
 	}
 	if existingUser != nil {
-		return nil, errors.New("username already exists")
+		return nil, ErrUsernameExists
 	}
 
 	// Check if email already exists
-	existingEmail, err := s.userRepo.FindByEmail(req.Email)
+	existingEmail, err := s.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, err
 	}
 	if existingEmail != nil {
-		return nil, errors.New("email already exists")
+		return nil, ErrEmailExists
 	}
 
 	// Hash password
@@ -64,63 +126,123 @@ func (s *authService) Register(req *models.RegisterRequest) (*models.User, error
 		Username: req.Username,
 		Email:    req.Email,
 		Password: string(hashedPassword),
+		Role:     models.RoleUser,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
-		return nil, err
+	// A concurrent registration can still win the race after the checks above,
+	// in which case the unique index rejects the insert
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, userConflictError(err)
 	}
 
 	return user, nil
 }
 
+// ChangePassword replaces a user's password after checking the current one. The new
+// password may not match the current password or, with password history configured,
+// any of the ones before it.
+func (s *authService) ChangePassword(ctx context.Context, userID uint, req *models.ChangePasswordRequest) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("%w: unknown user", ErrInvalidCredentials)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		return &ValidationError{Message: "Field 'CurrentPassword' is incorrect"}
+	}
+
+	// The current password counts as the first of the history
+	recent := []string{user.Password}
+	if keep := s.opts.PasswordHistory - 1; keep > 0 {
+		hashes, err := s.userRepo.RecentPasswordHashes(ctx, user.ID, keep)
+		if err != nil {
+			return err
+		}
+		recent = append(recent, hashes...)
+	}
+	for _, hash := range recent {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(req.NewPassword)) == nil {
+			return ErrPasswordReused
+		}
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.ChangePassword(ctx, user, string(hashedPassword), max(s.opts.PasswordHistory-1, 0))
+}
+
 // Login authenticates a user and returns a JWT token
-func (s *authService) Login(req *models.LoginRequest) (*models.LoginResponse, error) {
+func (s *authService) Login(ctx context.Context, req *models.LoginRequest, client models.SessionClient) (_ *models.LoginResponse, err error) {
+	defer func() { metrics.RecordLogin(err) }()
+
 	// Find user by username
-	user, err := s.userRepo.FindByUsername(req.Username)
+	user, err := s.userRepo.FindByUsername(ctx, req.Username)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("invalid username or password")
+		return nil, fmt.Errorf("%w: unknown username", ErrInvalidCredentials)
 	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, errors.New("invalid username or password")
+		return nil, fmt.Errorf("%w: password mismatch", ErrInvalidCredentials)
 	}
 
-	// Generate JWT token
-	token, err := s.generateToken(user.ID)
+	// Record the session and generate a JWT token carrying its ID
+	expiresAt := time.Now().Add(time.Hour * time.Duration(s.opts.JWTExpiryHours))
+	session, err := s.startSession(ctx, user, client, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.generateToken(user, session.ID, expiresAt, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.LoginResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	loginResponse := &models.LoginResponse{Token: token, User: *user}
+	if s.opts.LoginUserDetail == LoginUserSummary {
+		loginResponse.User = user.Summary()
+	}
+	return loginResponse, nil
 }
 
-// generateToken generates a JWT token for a user
-func (s *authService) generateToken(userID uint) (string, error) {
+// generateToken generates a JWT token for a user's session, valid until expiresAt.
+// flags are further server-set claims, added after the extra claims so none can replace them.
+func (s *authService) generateToken(user *models.User, sessionID string, expiresAt time.Time, flags jwt.MapClaims) (string, error) {
 	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * time.Duration(s.jwtExpiry)).Unix(),
-		"iat":     time.Now().Unix(),
+		"sub":        strconv.FormatUint(uint64(user.ID), 10),
+		"user_id":    user.ID, // kept for consumers that predate sub
+		"role":       user.Role,
+		"token_type": TokenTypeAccess,
+		"sid":        sessionID,
+		"exp":        expiresAt.Unix(),
+		"iat":        time.Now().Unix(),
+	}
+	s.mergeExtraClaims(claims, user)
+	for name, value := range flags {
+		claims[name] = value
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	if s.opts.JWTKeyID != "" {
+		token.Header["kid"] = s.opts.JWTKeyID
+	}
+	return token.SignedString([]byte(s.opts.JWTSecret))
 }
 
-// ValidateToken validates a JWT token
+// ValidateToken validates a JWT token against the secret named by its kid header
 func (s *authService) ValidateToken(tokenString string) (*jwt.Token, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
-		return []byte(s.jwtSecret), nil
-	})
+		return s.signingKey(token)
+	}, jwt.WithLeeway(s.opts.JWTLeeway), jwt.WithIssuedAt())
 
 	if err != nil {
 		return nil, err
@@ -133,13 +255,36 @@ func (s *authService) ValidateToken(tokenString string) (*jwt.Token, error) {
 	return token, nil
 }
 
-// GetUserFromToken extracts the user ID from a JWT token
+// signingKey returns the secret a token was signed with, selected by its kid header.
+// Tokens without a kid were issued before key IDs were configured and are checked
+// against the current secret.
+func (s *authService) signingKey(token *jwt.Token) ([]byte, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" || kid == s.opts.JWTKeyID {
+		return []byte(s.opts.JWTSecret), nil
+	}
+	if secret, ok := s.opts.JWTPreviousKeys[kid]; ok {
+		return []byte(secret), nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// GetUserFromToken extracts the user ID from the sub claim of a JWT token.
+// Tokens issued before sub was added carry the ID in user_id instead.
 func (s *authService) GetUserFromToken(token *jwt.Token) (uint, error) {
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
 		return 0, errors.New("invalid token claims")
 	}
 
+	if sub, ok := claims["sub"].(string); ok {
+		userID, err := strconv.ParseUint(sub, 10, 32)
+		if err != nil {
+			return 0, errors.New("invalid sub claim in token")
+		}
+		return uint(userID), nil
+	}
+
 	userID, ok := claims["user_id"].(float64)
 	if !ok {
 		return 0, errors.New("user_id not found in token")
@@ -147,3 +292,72 @@ func (s *authService) GetUserFromToken(token *jwt.Token) (uint, error) {
 
 	return uint(userID), nil
 }
+
+// GetTokenType extracts the token type from a JWT token.
+// Tokens issued before token_type was added are access tokens.
+func (s *authService) GetTokenType(token *jwt.Token) string {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+
+	tokenType, ok := claims["token_type"].(string)
+	if !ok {
+		return TokenTypeAccess
+	}
+	return tokenType
+}
+
+// GetRoleFromToken extracts the user role from a JWT token.
+// Tokens issued before roles were added carry no role and are treated as regular users.
+func (s *authService) GetRoleFromToken(token *jwt.Token) string {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return models.RoleUser
+	}
+
+	role, ok := claims["role"].(string)
+	if !ok || role == "" {
+		return models.RoleUser
+	}
+	return role
+}
+
+// Introspect reports whether a token would be accepted for API requests and, if so,
+// what it carries. A token that fails validation or whose session was revoked is
+// reported inactive rather than as an error, and nothing is recorded. An error is only
+// returned when the session cannot be checked.
+func (s *authService) Introspect(ctx context.Context, tokenString string) (*models.TokenIntrospection, error) {
+	inactive := &models.TokenIntrospection{Active: false}
+	token, err := s.ValidateToken(tokenString)
+	if err != nil || s.GetTokenType(token) != TokenTypeAccess {
+		return inactive, nil
+	}
+	userID, err := s.GetUserFromToken(token)
+	if err != nil {
+		return inactive, nil
+	}
+	if _, err := s.activeSession(ctx, token, userID); err != nil {
+		if errors.Is(err, ErrSessionRevoked) {
+			return inactive, nil
+		}
+		return nil, err
+	}
+
+	result := &models.TokenIntrospection{
+		Active:    true,
+		UserID:    userID,
+		Role:      s.GetRoleFromToken(token),
+		TokenType: TokenTypeAccess,
+	}
+	if exp, err := token.Claims.GetExpirationTime(); err == nil && exp != nil {
+		result.ExpiresAt = exp.Unix()
+	}
+	if iat, err := token.Claims.GetIssuedAt(); err == nil && iat != nil {
+		result.IssuedAt = iat.Unix()
+	}
+	if actorID, ok := s.GetImpersonatorFromToken(token); ok {
+		result.ImpersonatedBy = actorID
+	}
+	return result, nil
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+const testJWTSecret = "test-secret"
+
+// newTestAuth returns an auth service over in-memory repositories, adjusted by configure
+func newTestAuth(configure func(opts *AuthOptions)) (*authService, *memUsers, *memSessions) {
+	users, sessions := newMemUsers(), newMemSessions()
+	opts := AuthOptions{JWTSecret: testJWTSecret, JWTExpiryHours: 1}
+	if configure != nil {
+		configure(&opts)
+	}
+	return NewAuthService(users, sessions, opts).(*authService), users, sessions
+}
+
+// mustRegister registers a user with the given password
+func mustRegister(t *testing.T, s AuthService, username, password string) *models.User {
+	t.Helper()
+	user, err := s.Register(context.Background(), &models.RegisterRequest{
+		Username: username,
+		Email:    username + "@example.com",
+		Password: password,
+	})
+	if err != nil {
+		t.Fatalf("Register(%q): %v", username, err)
+	}
+	return user
+}
+
+func TestChangePasswordHistory(t *testing.T) {
+	s, _, _ := newTestAuth(func(opts *AuthOptions) { opts.PasswordHistory = 3 })
+	user := mustRegister(t, s, "alice", "pass-0")
+	ctx := context.Background()
+
+	current := "pass-0"
+	steps := []struct {
+		newPassword string
+		wantErr     error
+	}{
+		{"pass-1", nil},
+		{"pass-2", nil},
+		{"pass-2", ErrPasswordReused}, // the current password
+		{"pass-1", ErrPasswordReused}, // the one before
+		{"pass-0", ErrPasswordReused}, // still within the last three
+		{"pass-3", nil},
+		{"pass-0", nil}, // now older than the last three
+	}
+	for i, step := range steps {
+		err := s.ChangePassword(ctx, user.ID, &models.ChangePasswordRequest{
+			CurrentPassword: current,
+			NewPassword:     step.newPassword,
+		})
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("step %d: changing to %q: error = %v, want %v", i, step.newPassword, err, step.wantErr)
+		}
+		if err == nil {
+			current = step.newPassword
+		}
+	}
+}
+
+func TestChangePasswordWithoutHistory(t *testing.T) {
+	for _, history := range []int{0, 1} {
+		s, _, _ := newTestAuth(func(opts *AuthOptions) { opts.PasswordHistory = history })
+		user := mustRegister(t, s, "bob", "first-pass")
+		ctx := context.Background()
+
+		change := func(current, next string) error {
+			return s.ChangePassword(ctx, user.ID, &models.ChangePasswordRequest{CurrentPassword: current, NewPassword: next})
+		}
+		if err := change("first-pass", "first-pass"); !errors.Is(err, ErrPasswordReused) {
+			t.Errorf("history %d: reusing the current password: error = %v, want %v", history, err, ErrPasswordReused)
+		}
+		if err := change("first-pass", "second-pass"); err != nil {
+			t.Fatalf("history %d: changing password: %v", history, err)
+		}
+		if err := change("second-pass", "first-pass"); err != nil {
+			t.Errorf("history %d: going back to the previous password: %v", history, err)
+		}
+	}
+}
+
+func TestChangePasswordWrongCurrent(t *testing.T) {
+	s, _, _ := newTestAuth(nil)
+	user := mustRegister(t, s, "carol", "right-pass")
+
+	err := s.ChangePassword(context.Background(), user.ID, &models.ChangePasswordRequest{
+		CurrentPassword: "wrong-pass",
+		NewPassword:     "new-pass",
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("error = %v, want a validation error", err)
+	}
+}
+
+// signTestToken signs claims as the test auth service would
+func signTestToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return token
+}
+
+func TestIntrospectSession(t *testing.T) {
+	s, _, _ := newTestAuth(nil)
+	user := mustRegister(t, s, "dave", "dave-pass")
+	ctx := context.Background()
+
+	login, err := s.Login(ctx, &models.LoginRequest{Username: "dave", Password: "dave-pass"}, models.SessionClient{})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	sessions, err := s.ListSessions(ctx, user.ID, "")
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("ListSessions = %v, %v; want one session", sessions, err)
+	}
+	claims := func(sid string) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub":        strconv.FormatUint(uint64(user.ID), 10),
+			"token_type": TokenTypeAccess,
+			"exp":        time.Now().Add(time.Hour).Unix(),
+		}
+		if sid != "" {
+			c["sid"] = sid
+		}
+		return c
+	}
+	introspect := func(token string) bool {
+		t.Helper()
+		result, err := s.Introspect(ctx, token)
+		if err != nil {
+			t.Fatalf("Introspect: %v", err)
+		}
+		return result.Active
+	}
+
+	if !introspect(login.Token) {
+		t.Error("token of an active session reported inactive")
+	}
+	if !introspect(signTestToken(t, claims(""))) {
+		t.Error("token issued before sessions were recorded reported inactive")
+	}
+	if introspect(signTestToken(t, claims("unknown-session"))) {
+		t.Error("token of an unknown session reported active")
+	}
+
+	if err := s.RevokeSession(ctx, user.ID, sessions[0].ID); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	if introspect(login.Token) {
+		t.Error("token of a revoked session reported active")
+	}
+	if _, err := s.CheckSession(ctx, mustValidate(t, s, login.Token), user.ID); !errors.Is(err, ErrSessionRevoked) {
+		t.Errorf("CheckSession error = %v, want %v", err, ErrSessionRevoked)
+	}
+}
+
+// mustValidate validates a token that is expected to be valid
+func mustValidate(t *testing.T, s AuthService, token string) *jwt.Token {
+	t.Helper()
+	parsed, err := s.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	return parsed
+}
+
+// racingUsers finds no existing users, as when concurrent registrations all pass the
+// existence checks before any of them inserts, leaving the unique indexes to decide
+type racingUsers struct {
+	*memUsers
+}
+
+func (racingUsers) FindByUsername(context.Context, string) (*models.User, error) {
+	return nil, nil
+}
+
+func (racingUsers) FindByEmail(context.Context, string) (*models.User, error) {
+	return nil, nil
+}
+
+func TestRegisterConcurrentDuplicates(t *testing.T) {
+	tests := []struct {
+		name    string
+		request func(i int) *models.RegisterRequest
+		wantErr error
+	}{
+		{"same username", func(i int) *models.RegisterRequest {
+			return &models.RegisterRequest{Username: "erin", Email: fmt.Sprintf("erin%d@example.com", i), Password: "erin-pass"}
+		}, ErrUsernameExists},
+		{"same email", func(i int) *models.RegisterRequest {
+			return &models.RegisterRequest{Username: fmt.Sprintf("erin%d", i), Email: "erin@example.com", Password: "erin-pass"}
+		}, ErrEmailExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAuthService(racingUsers{newMemUsers()}, newMemSessions(), AuthOptions{JWTSecret: testJWTSecret, JWTExpiryHours: 1})
+
+			const attempts = 4
+			errs := make([]error, attempts)
+			var wg sync.WaitGroup
+			for i := 0; i < attempts; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					_, errs[i] = s.Register(context.Background(), tt.request(i))
+				}(i)
+			}
+			wg.Wait()
+
+			succeeded := 0
+			for _, err := range errs {
+				switch {
+				case err == nil:
+					succeeded++
+				case !errors.Is(err, tt.wantErr):
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+			}
+			if succeeded != 1 {
+				t.Errorf("%d registrations succeeded, want 1", succeeded)
+			}
+		})
+	}
+}
+
+func TestValidateTokenLeeway(t *testing.T) {
+	s, _, _ := newTestAuth(func(opts *AuthOptions) { opts.JWTLeeway = 30 * time.Second })
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		exp     time.Time
+		iat     time.Time
+		wantErr bool
+	}{
+		{"not yet expired", now.Add(time.Minute), now, false},
+		{"expired within the leeway", now.Add(-10 * time.Second), now.Add(-time.Hour), false},
+		{"expired outside the leeway", now.Add(-time.Minute), now.Add(-time.Hour), true},
+		{"issued slightly in the future", now.Add(time.Hour), now.Add(10 * time.Second), false},
+		{"issued well in the future", now.Add(time.Hour), now.Add(time.Minute), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := signTestToken(t, jwt.MapClaims{
+				"sub":        "1",
+				"token_type": TokenTypeAccess,
+				"exp":        tt.exp.Unix(),
+				"iat":        tt.iat.Unix(),
+			})
+			if _, err := s.ValidateToken(token); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateToken error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTokenWithoutLeeway(t *testing.T) {
+	s, _, _ := newTestAuth(nil)
+	token := signTestToken(t, jwt.MapClaims{"sub": "1", "exp": time.Now().Add(-2 * time.Second).Unix()})
+	if _, err := s.ValidateToken(token); !errors.Is(err, jwt.ErrTokenExpired) {
+		t.Errorf("ValidateToken error = %v, want %v", err, jwt.ErrTokenExpired)
+	}
+}
+
+func TestRegisterNormalizesIdentity(t *testing.T) {
+	s, users, _ := newTestAuth(nil)
+	ctx := context.Background()
+
+	user, err := s.Register(ctx, &models.RegisterRequest{Username: " grace ", Email: " Grace@Example.COM ", Password: "grace-pass"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	stored, _ := users.FindByID(ctx, user.ID)
+	if stored.Username != "grace" || stored.Email != "grace@example.com" {
+		t.Errorf("stored username %q, email %q; want \"grace\", \"grace@example.com\"", stored.Username, stored.Email)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		email    string
+		wantErr  error
+	}{
+		{"email in another case", "grace2", "GRACE@example.com", ErrEmailExists},
+		{"email with whitespace", "grace2", "\tgrace@example.com ", ErrEmailExists},
+		{"username with whitespace", "  grace", "other@example.com", ErrUsernameExists},
+		{"username in another case", "Grace", "other@example.com", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Register(ctx, &models.RegisterRequest{Username: tt.username, Email: tt.email, Password: "other-pass"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Register error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := s.Login(ctx, &models.LoginRequest{Username: " grace\t", Password: "grace-pass"}, models.SessionClient{}); err != nil {
+		t.Errorf("logging in with whitespace around the username: %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// ErrBundleNotFound is returned for a bundle that does not exist
+var ErrBundleNotFound = errors.New("bundle not found")
+
+// BundleService handles bundle business logic
+type BundleService interface {
+	CreateBundle(ctx context.Context, userID uint, req *models.CreateBundleRequest) (*models.Bundle, error)
+	GetBundle(ctx context.Context, id uint) (*models.Bundle, error)
+	ListBundles(ctx context.Context) ([]models.Bundle, error)
+	Consume(ctx context.Context, id, userID uint, req *models.ConsumeBundleRequest) (*models.Bundle, error)
+}
+
+type bundleService struct {
+	repo          repository.BundleRepository
+	inventoryRepo repository.InventoryRepository
+
+	// skuCase normalizes SKUs like the inventory service does (see SKUCaseUpper and SKUCaseLower)
+	skuCase string
+	// allowNegativeStock lets consumption take components below zero, like stock adjustments
+	allowNegativeStock bool
+}
+
+// NewBundleService creates a new bundle service
+func NewBundleService(repo repository.BundleRepository, inventoryRepo repository.InventoryRepository, skuCase string, allowNegativeStock bool) BundleService {
+	return &bundleService{repo: repo, inventoryRepo: inventoryRepo, skuCase: skuCase, allowNegativeStock: allowNegativeStock}
+}
+
+// CreateBundle defines a bundle of existing items. Every SKU must belong to an active
+// item and appear only once.
+func (s *bundleService) CreateBundle(ctx context.Context, userID uint, req *models.CreateBundleRequest) (*models.Bundle, error) {
+	skus := make([]string, len(req.Lines))
+	for i := range req.Lines {
+		skus[i] = applySKUCase(s.skuCase, req.Lines[i].SKU)
+	}
+	if duplicates := duplicateSKUs(skus); len(duplicates) > 0 {
+		return nil, &DuplicateSKUsError{SKUs: duplicates}
+	}
+
+	items, err := s.inventoryRepo.FindBySKUs(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+	bySKU := make(map[string]*models.Item, len(items))
+	for i := range items {
+		bySKU[items[i].SKU] = &items[i]
+	}
+
+	bundle := &models.Bundle{Name: req.Name, CreatedBy: userID}
+	for i, line := range req.Lines {
+		item, ok := bySKU[skus[i]]
+		if !ok {
+			return nil, &NotFoundError{Resource: "item", Key: "sku", Value: skus[i], err: ErrItemNotFound}
+		}
+		if !models.ValidQuantity(item.Unit, line.Quantity) {
+			return nil, &ValidationError{Message: fmt.Sprintf("lines[%d]: Field 'Quantity' must be a whole number for unit '%s'", i, item.Unit)}
+		}
+		bundle.Lines = append(bundle.Lines, models.BundleLine{
+			ItemID:            item.ID,
+			SKU:               item.SKU,
+			Quantity:          models.RoundQuantity(line.Quantity),
+			AvailableQuantity: max(item.Quantity, 0),
+		})
+	}
+	bundle.Refresh()
+
+	if err := s.repo.Create(ctx, bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// GetBundle retrieves a bundle with the stock of its components and how many bundles they make up
+func (s *bundleService) GetBundle(ctx context.Context, id uint) (*models.Bundle, error) {
+	bundle, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if bundle == nil {
+		return nil, bundleNotFound(id)
+	}
+	if err := s.refresh(ctx, bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// ListBundles lists every bundle with the stock of its components and how many bundles they make up
+func (s *bundleService) ListBundles(ctx context.Context) ([]models.Bundle, error) {
+	bundles, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]*models.Bundle, len(bundles))
+	for i := range bundles {
+		refs[i] = &bundles[i]
+	}
+	if err := s.refresh(ctx, refs...); err != nil {
+		return nil, err
+	}
+	return bundles, nil
+}
+
+// Consume takes the components of quantity bundles out of stock, recording a bundle
+// movement against each component. Either every component is decremented or, when any
+// of them is short and negative stock is not allowed, none is.
+func (s *bundleService) Consume(ctx context.Context, id, userID uint, req *models.ConsumeBundleRequest) (*models.Bundle, error) {
+	if !models.ValidQuantity(models.UnitEach, req.Quantity) {
+		return nil, &ValidationError{Message: "Field 'Quantity' must be a whole number of bundles"}
+	}
+
+	var bundle *models.Bundle
+	err := s.inventoryRepo.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		bundle, err = s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if bundle == nil {
+			return bundleNotFound(id)
+		}
+
+		// Components are locked in item ID order so concurrent consumptions of bundles
+		// sharing components cannot deadlock
+		lines := make([]*models.BundleLine, len(bundle.Lines))
+		for i := range bundle.Lines {
+			lines[i] = &bundle.Lines[i]
+		}
+		sort.Slice(lines, func(i, j int) bool { return lines[i].ItemID < lines[j].ItemID })
+
+		for _, line := range lines {
+			movement := &models.StockMovement{
+				Type:     models.MovementTypeBundle,
+				Delta:    -models.RoundQuantity(req.Quantity * line.Quantity),
+				Reason:   req.Reason,
+				UserID:   userID,
+				BundleID: &bundle.ID,
+			}
+			item, err := s.inventoryRepo.AdjustQuantity(ctx, line.ItemID, movement, s.allowNegativeStock)
+			if errors.Is(err, repository.ErrInsufficientStock) {
+				return fmt.Errorf("%w of SKU '%s' for %g bundles", ErrInsufficientStock, line.SKU, req.Quantity)
+			}
+			if err != nil {
+				return itemConflictError(err)
+			}
+			if item == nil {
+				return &NotFoundError{Resource: "item", Key: "sku", Value: line.SKU, err: ErrItemNotFound}
+			}
+			line.AvailableQuantity = max(item.Quantity, 0)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	bundle.Refresh()
+	return bundle, nil
+}
+
+// refresh fills in the stock of every component of bundles and recomputes their available quantities
+func (s *bundleService) refresh(ctx context.Context, bundles ...*models.Bundle) error {
+	var ids []uint
+	for _, bundle := range bundles {
+		for _, line := range bundle.Lines {
+			ids = append(ids, line.ItemID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	items, err := s.inventoryRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	stock := make(map[uint]float64, len(items))
+	for _, item := range items {
+		stock[item.ID] = item.Quantity
+	}
+	for _, bundle := range bundles {
+		for i := range bundle.Lines {
+			bundle.Lines[i].AvailableQuantity = max(stock[bundle.Lines[i].ItemID], 0)
+		}
+		bundle.Refresh()
+	}
+	return nil
+}
+
+// bundleNotFound returns the error for a missing bundle with the given ID
+func bundleNotFound(id uint) error {
+	return &NotFoundError{Resource: "bundle", Key: "id", Value: id, err: ErrBundleNotFound}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// oneBundle is a bundle repository holding a single bundle
+type oneBundle struct {
+	repository.BundleRepository
+	bundle models.Bundle
+}
+
+func (r *oneBundle) FindByID(_ context.Context, id uint) (*models.Bundle, error) {
+	if id != r.bundle.ID {
+		return nil, nil
+	}
+	bundle := r.bundle
+	bundle.Lines = append([]models.BundleLine(nil), r.bundle.Lines...)
+	return &bundle, nil
+}
+
+func TestConsumeBundle(t *testing.T) {
+	tests := []struct {
+		name          string
+		allowNegative bool
+		quantity      float64
+		wantErr       error
+		wantStock     [2]float64 // of the bolts and nuts afterwards
+		wantAvailable float64
+	}{
+		{"in stock", false, 2, nil, [2]float64{6, 8}, 3},
+		{"short", false, 6, ErrInsufficientStock, [2]float64{10, 10}, 0},
+		{"short with negative stock allowed", true, 6, nil, [2]float64{-2, 4}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := newMemItems(
+				models.Item{ID: 1, Name: "Bolt", SKU: "BOLT", Quantity: 10, Unit: models.UnitEach},
+				models.Item{ID: 2, Name: "Nut", SKU: "NUT", Quantity: 10, Unit: models.UnitEach},
+			)
+			bundles := &oneBundle{bundle: models.Bundle{ID: 5, Name: "Fixing kit", Lines: []models.BundleLine{
+				{ItemID: 1, SKU: "BOLT", Quantity: 2},
+				{ItemID: 2, SKU: "NUT", Quantity: 1},
+			}}}
+			s := NewBundleService(bundles, items, "", tt.allowNegative)
+
+			bundle, err := s.Consume(context.Background(), 5, 1, &models.ConsumeBundleRequest{Quantity: tt.quantity})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && bundle.AvailableQuantity != tt.wantAvailable {
+				t.Errorf("%v bundles available, want %v", bundle.AvailableQuantity, tt.wantAvailable)
+			}
+			for i, want := range tt.wantStock {
+				if got := items.get(uint(i + 1)); got.Quantity != want || got.Backordered != (want < 0) {
+					t.Errorf("item %d has %v (backordered %v), want %v", got.ID, got.Quantity, got.Backordered, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// ErrCategoryNotFound is returned when no active item uses a category
+var ErrCategoryNotFound = errors.New("category not found")
+
+// ErrCategoryNotEmpty is returned when deleting a category that still has items is blocked
+var ErrCategoryNotEmpty = errors.New("category is not empty")
+
+// CategoryNotEmptyError reports how many items keep a category from being deleted
+type CategoryNotEmptyError struct {
+	Category  string
+	ItemCount int64
+}
+
+// Error implements the error interface
+func (e *CategoryNotEmptyError) Error() string {
+	return fmt.Sprintf("category '%s' still has %d items", e.Category, e.ItemCount)
+}
+
+// Unwrap allows errors.Is(err, ErrCategoryNotEmpty)
+func (e *CategoryNotEmptyError) Unwrap() error {
+	return ErrCategoryNotEmpty
+}
+
+// Details returns the category and its item count for the error response
+func (e *CategoryNotEmptyError) Details() interface{} {
+	return map[string]interface{}{"category": e.Category, "item_count": e.ItemCount}
+}
+
+// CategoryService handles category business logic.
+// Categories are not stored on their own: a category exists while active items use it.
+type CategoryService interface {
+	DeleteCategory(ctx context.Context, category string) (*models.DeleteCategoryResponse, error)
+}
+
+type categoryService struct {
+	repo           repository.InventoryRepository
+	deleteBehavior string
+}
+
+// NewCategoryService creates a new category service. deleteBehavior is one of the
+// models.CategoryDelete* constants and decides what happens to a deleted category's items.
+func NewCategoryService(repo repository.InventoryRepository, deleteBehavior string) CategoryService {
+	return &categoryService{repo: repo, deleteBehavior: deleteBehavior}
+}
+
+// DeleteCategory removes a category from all items in one transaction. Depending on the
+// configured behavior it is refused while items remain, or the items are moved to
+// models.UncategorizedCategory, or they are deleted too.
+func (s *categoryService) DeleteCategory(ctx context.Context, category string) (*models.DeleteCategoryResponse, error) {
+	if s.deleteBehavior == models.CategoryDeleteReassign && category == models.UncategorizedCategory {
+		return nil, &ValidationError{Message: fmt.Sprintf("Category '%s' cannot be deleted while items are reassigned to it", category)}
+	}
+
+	result := &models.DeleteCategoryResponse{Category: category, Behavior: s.deleteBehavior}
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		count, err := s.repo.Count(ctx, repository.ListOptions{Category: category})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return &NotFoundError{Resource: "category", Key: "name", Value: category, err: ErrCategoryNotFound}
+		}
+
+		switch s.deleteBehavior {
+		case models.CategoryDeleteReassign:
+			result.ItemsAffected, err = s.repo.ReassignCategory(ctx, category, models.UncategorizedCategory)
+		case models.CategoryDeleteCascade:
+			result.ItemsAffected, err = s.repo.DeleteByCategory(ctx, category)
+		default:
+			return &CategoryNotEmptyError{Category: category, ItemCount: count}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
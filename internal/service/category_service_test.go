@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+// categoryItems are two items in Garden and one in Kitchen
+func categoryItems() []models.Item {
+	return []models.Item{
+		{ID: 1, Name: "Rake", SKU: "RAKE", Unit: models.UnitEach, Category: "Garden"},
+		{ID: 2, Name: "Hose", SKU: "HOSE", Unit: models.UnitEach, Category: "Garden"},
+		{ID: 3, Name: "Whisk", SKU: "WHISK", Unit: models.UnitEach, Category: "Kitchen"},
+	}
+}
+
+func TestDeleteCategoryBehaviors(t *testing.T) {
+	tests := []struct {
+		behavior     string
+		wantErr      error
+		wantAffected int64
+		wantCategory string // of the Garden items afterwards
+		wantDeleted  bool
+	}{
+		{models.CategoryDeleteBlock, ErrCategoryNotEmpty, 0, "Garden", false},
+		{models.CategoryDeleteReassign, nil, 2, models.UncategorizedCategory, false},
+		{models.CategoryDeleteCascade, nil, 2, "Garden", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.behavior, func(t *testing.T) {
+			repo := newMemItems(categoryItems()...)
+			s := NewCategoryService(repo, tt.behavior)
+
+			result, err := s.DeleteCategory(context.Background(), "Garden")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (result.ItemsAffected != tt.wantAffected || result.Behavior != tt.behavior) {
+				t.Errorf("result = %+v, want %d items affected by %s", result, tt.wantAffected, tt.behavior)
+			}
+			for _, id := range []uint{1, 2} {
+				if item := repo.get(id); item.Category != tt.wantCategory || repo.deleted[id] != tt.wantDeleted {
+					t.Errorf("item %d: category %q, deleted %v; want %q, %v", id, item.Category, repo.deleted[id], tt.wantCategory, tt.wantDeleted)
+				}
+			}
+			if item := repo.get(3); item.Category != "Kitchen" || repo.deleted[3] {
+				t.Errorf("item in another category changed: %+v", item)
+			}
+		})
+	}
+}
+
+func TestDeleteCategoryBlockedCount(t *testing.T) {
+	s := NewCategoryService(newMemItems(categoryItems()...), models.CategoryDeleteBlock)
+	_, err := s.DeleteCategory(context.Background(), "Garden")
+
+	var notEmpty *CategoryNotEmptyError
+	if !errors.As(err, &notEmpty) || notEmpty.ItemCount != 2 || notEmpty.Category != "Garden" {
+		t.Errorf("error = %v, want Garden blocked by 2 items", err)
+	}
+}
+
+func TestDeleteUncategorizedWhileReassigning(t *testing.T) {
+	s := NewCategoryService(newMemItems(categoryItems()...), models.CategoryDeleteReassign)
+	if _, err := s.DeleteCategory(context.Background(), models.UncategorizedCategory); !errors.Is(err, ErrValidation) {
+		t.Errorf("error = %v, want a validation error", err)
+	}
+}
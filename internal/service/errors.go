@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// Auth errors
+var (
+	ErrUsernameExists     = errors.New("username already exists")
+	ErrEmailExists        = errors.New("email already exists")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+
+	// ErrPasswordReused is returned when a new password matches a recently used one
+	ErrPasswordReused = errors.New("password was used recently")
+)
+
+// Inventory errors
+var (
+	ErrItemNotFound = errors.New("item not found")
+	ErrSKUExists    = errors.New("item with this SKU already exists")
+
+	ErrScheduledPriceNotFound = errors.New("scheduled price change not found")
+
+	// ErrPreconditionFailed is returned when an update's If-Match ETag no longer matches the item
+	ErrPreconditionFailed = errors.New("item has been modified since it was read")
+
+	// ErrInsufficientStock is returned when an adjustment would drive quantity below zero
+	ErrInsufficientStock = repository.ErrInsufficientStock
+)
+
+// ErrDatabaseUnavailable is returned while the database circuit breaker is failing queries fast
+var ErrDatabaseUnavailable = repository.ErrCircuitOpen
+
+// ErrValidation is the base error for requests that break a business rule
+var ErrValidation = errors.New("validation failed")
+
+// ValidationError describes why a request was rejected by the service
+type ValidationError struct {
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Unwrap allows errors.Is(err, ErrValidation)
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// DuplicateSKUsError reports SKUs that appear more than once in a bulk payload
+type DuplicateSKUsError struct {
+	SKUs []string
+}
+
+// Error implements the error interface
+func (e *DuplicateSKUsError) Error() string {
+	return "duplicate SKUs in request: " + strings.Join(e.SKUs, ", ")
+}
+
+// Unwrap allows errors.Is(err, ErrValidation)
+func (e *DuplicateSKUsError) Unwrap() error {
+	return ErrValidation
+}
+
+// Details returns the offending SKUs for the error response
+func (e *DuplicateSKUsError) Details() interface{} {
+	return map[string][]string{"skus": e.SKUs}
+}
+
+// NotFoundError reports which resource was not found and the key the client asked for
+type NotFoundError struct {
+	Resource string      // "item", "scheduled_price", ...
+	Key      string      // "id" or "sku"
+	Value    interface{} // the requested key value
+	err      error
+}
+
+// Error implements the error interface
+func (e *NotFoundError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap allows errors.Is(err, ErrItemNotFound) and the other not-found sentinels
+func (e *NotFoundError) Unwrap() error {
+	return e.err
+}
+
+// Details returns the resource type and requested key for the error response
+func (e *NotFoundError) Details() interface{} {
+	return map[string]interface{}{"resource": e.Resource, e.Key: e.Value}
+}
+
+// itemNotFound returns the error for a missing item with the given ID
+func itemNotFound(id uint) error {
+	return &NotFoundError{Resource: "item", Key: "id", Value: id, err: ErrItemNotFound}
+}
+
+// userConflictError maps a unique violation on the users table to the matching typed error
+func userConflictError(err error) error {
+	var dupErr *repository.DuplicateKeyError
+	if !errors.As(err, &dupErr) {
+		return err
+	}
+	switch {
+	case strings.Contains(dupErr.Constraint, "email"):
+		return ErrEmailExists
+	case strings.Contains(dupErr.Constraint, "username"):
+		return ErrUsernameExists
+	default:
+		return err
+	}
+}
+
+// itemCheckMessages describes the item check constraints in the words of the service's own validation
+var itemCheckMessages = map[string]string{
+	"chk_items_quantity_nonnegative": "Field 'Quantity' must be at least 0",
+	"chk_items_price_nonnegative":    "Field 'Price' must be at least 0",
+}
+
+// itemConflictError maps a unique violation on the items table to ErrSKUExists, and
+// a check violation or numeric overflow to the ValidationError the service would have returned
+func itemConflictError(err error) error {
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return ErrSKUExists
+	}
+	if errors.Is(err, repository.ErrNumericOverflow) {
+		return &ValidationError{Message: "A quantity or price is larger than can be stored"}
+	}
+	var checkErr *repository.CheckViolationError
+	if errors.As(err, &checkErr) {
+		if message, ok := itemCheckMessages[checkErr.Constraint]; ok {
+			return &ValidationError{Message: message}
+		}
+		return &ValidationError{Message: "Item violates constraint " + checkErr.Constraint}
+	}
+	return err
+}
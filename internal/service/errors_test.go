@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+func TestNotFoundDetails(t *testing.T) {
+	ctx := context.Background()
+	inventory, repo := newTestInventory(InventoryPolicy{})
+	categories := NewCategoryService(repo, models.CategoryDeleteBlock)
+	auth, _, _ := newTestAuth(nil)
+
+	tests := []struct {
+		name        string
+		call        func() error
+		wantErr     error
+		wantDetails map[string]interface{}
+	}{
+		{"item by ID", func() error {
+			_, err := inventory.GetItemByID(ctx, 9, false)
+			return err
+		}, ErrItemNotFound, map[string]interface{}{"resource": "item", "id": uint(9)}},
+		{"deleted item by ID", func() error {
+			_, err := inventory.GetItemByID(ctx, 9, true)
+			return err
+		}, ErrItemNotFound, map[string]interface{}{"resource": "item", "id": uint(9)}},
+		{"item by SKU", func() error {
+			_, err := inventory.AdjustStockBySKU(ctx, "NOPE-1", 1, &models.AdjustStockRequest{Delta: ptr(1.0)})
+			return err
+		}, ErrItemNotFound, map[string]interface{}{"resource": "item", "sku": "NOPE-1"}},
+		{"category", func() error {
+			_, err := categories.DeleteCategory(ctx, "Garden")
+			return err
+		}, ErrCategoryNotFound, map[string]interface{}{"resource": "category", "name": "Garden"}},
+		{"user", func() error {
+			_, err := auth.Impersonate(ctx, 1, 42, models.SessionClient{})
+			return err
+		}, ErrUserNotFound, map[string]interface{}{"resource": "user", "id": uint(42)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			var notFound *NotFoundError
+			if !errors.As(err, &notFound) {
+				t.Fatalf("error %v is not a *NotFoundError", err)
+			}
+			if details := notFound.Details(); !reflect.DeepEqual(details, tt.wantDetails) {
+				t.Errorf("details = %v, want %v", details, tt.wantDetails)
+			}
+		})
+	}
+}
+
+func TestItemConflictError(t *testing.T) {
+	other := errors.New("connection reset")
+
+	tests := []struct {
+		name        string
+		err         error
+		wantErr     error
+		wantMessage string
+	}{
+		{"duplicate SKU", &repository.DuplicateKeyError{Constraint: "idx_items_sku_active"}, ErrSKUExists, ""},
+		{"negative quantity", &repository.CheckViolationError{Constraint: "chk_items_quantity_nonnegative"}, ErrValidation, "Field 'Quantity' must be at least 0"},
+		{"negative price", &repository.CheckViolationError{Constraint: "chk_items_price_nonnegative"}, ErrValidation, "Field 'Price' must be at least 0"},
+		{"unknown constraint", &repository.CheckViolationError{Constraint: "chk_items_other"}, ErrValidation, "Item violates constraint chk_items_other"},
+		{"numeric overflow", fmt.Errorf("%w: %w", repository.ErrNumericOverflow, other), ErrValidation, "A quantity or price is larger than can be stored"},
+		{"other error", other, other, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := itemConflictError(tt.err)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("itemConflictError = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantMessage != "" && err.Error() != tt.wantMessage {
+				t.Errorf("message = %q, want %q", err.Error(), tt.wantMessage)
+			}
+		})
+	}
+}
+
+// rejectingUpdates fails every item update with err, as the database would when a
+// write slips past the service's own validation
+type rejectingUpdates struct {
+	*memItems
+	err error
+}
+
+func (r rejectingUpdates) Update(context.Context, *models.Item, *models.PriceHistory) error {
+	return r.err
+}
+
+func TestUpdateItemCheckViolation(t *testing.T) {
+	repo := newMemItems(models.Item{ID: 1, Name: "Widget", SKU: "W-1", Quantity: 3, Unit: models.UnitEach, Price: 5})
+	s := NewInventoryService(rejectingUpdates{repo, &repository.CheckViolationError{Constraint: "chk_items_price_nonnegative"}}, InventoryPolicy{})
+
+	_, err := s.UpdateItem(context.Background(), 1, 1, &models.UpdateItemRequest{Name: ptr("Widget 2")}, "")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("UpdateItem error = %v, want a ValidationError", err)
+	}
+	if want := "Field 'Price' must be at least 0"; validationErr.Message != want {
+		t.Errorf("message = %q, want %q", validationErr.Message, want)
+	}
+}
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// memUsers is an in-memory UserRepository. Like the unique indexes of the users table,
+// it rejects a second user with the same username or email.
+type memUsers struct {
+	mu      sync.Mutex
+	users   map[uint]*models.User
+	history map[uint][]string // replaced password hashes, newest first
+	nextID  uint
+}
+
+func newMemUsers() *memUsers {
+	return &memUsers{users: make(map[uint]*models.User), history: make(map[uint][]string)}
+}
+
+func (m *memUsers) Create(_ context.Context, user *models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.users {
+		if existing.Username == user.Username {
+			return &repository.DuplicateKeyError{Constraint: "idx_users_username"}
+		}
+		if existing.Email == user.Email {
+			return &repository.DuplicateKeyError{Constraint: "idx_users_email"}
+		}
+	}
+	m.nextID++
+	user.ID = m.nextID
+	stored := *user
+	m.users[user.ID] = &stored
+	return nil
+}
+
+func (m *memUsers) find(match func(*models.User) bool) *models.User {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range m.users {
+		if match(user) {
+			found := *user
+			return &found
+		}
+	}
+	return nil
+}
+
+func (m *memUsers) FindByUsername(_ context.Context, username string) (*models.User, error) {
+	username = models.NormalizeUsername(username)
+	return m.find(func(u *models.User) bool { return u.Username == username }), nil
+}
+
+func (m *memUsers) FindByEmail(_ context.Context, email string) (*models.User, error) {
+	email = models.NormalizeEmail(email)
+	return m.find(func(u *models.User) bool { return u.Email == email }), nil
+}
+
+func (m *memUsers) FindByID(_ context.Context, id uint) (*models.User, error) {
+	return m.find(func(u *models.User) bool { return u.ID == id }), nil
+}
+
+func (m *memUsers) Delete(_ context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.users, id)
+	return nil
+}
+
+func (m *memUsers) Search(context.Context, repository.UserSearch) ([]models.User, error) {
+	return nil, nil
+}
+
+func (m *memUsers) RecentPasswordHashes(_ context.Context, userID uint, limit int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hashes := m.history[userID]
+	if len(hashes) > limit {
+		hashes = hashes[:limit]
+	}
+	return append([]string(nil), hashes...), nil
+}
+
+func (m *memUsers) ChangePassword(_ context.Context, user *models.User, hash string, keep int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	history := append([]string{user.Password}, m.history[user.ID]...)
+	if len(history) > keep {
+		history = history[:keep]
+	}
+	m.history[user.ID] = history
+	m.users[user.ID].Password = hash
+	user.Password = hash
+	return nil
+}
+
+// memSessions is an in-memory SessionRepository
+type memSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*models.Session
+}
+
+func newMemSessions() *memSessions {
+	return &memSessions{sessions: make(map[string]*models.Session)}
+}
+
+func (m *memSessions) Create(_ context.Context, session *models.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *session
+	m.sessions[session.ID] = &stored
+	return nil
+}
+
+func (m *memSessions) FindByID(_ context.Context, id string) (*models.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	found := *session
+	return &found, nil
+}
+
+func (m *memSessions) ListActive(_ context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var active []models.Session
+	for _, session := range m.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			active = append(active, *session)
+		}
+	}
+	return active, nil
+}
+
+func (m *memSessions) Touch(context.Context, string, time.Time, time.Duration) error {
+	return nil
+}
+
+func (m *memSessions) Revoke(_ context.Context, userID uint, id string, now time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+		return false, nil
+	}
+	session.RevokedAt = &now
+	return true, nil
+}
+
+// memItems is an in-memory InventoryRepository covering the calls the item write paths
+// make; other methods panic through the nil embedded interface. Like the partial unique
+// index on items, it rejects a second active item with the same SKU. WithinTransaction
+// restores the items, movements and price changes if fn fails.
+type memItems struct {
+	repository.InventoryRepository
+	mu        sync.Mutex
+	items     map[uint]*models.Item
+	deleted   map[uint]bool
+	movements []models.StockMovement
+	prices    []models.PriceHistory
+	merged    map[uint]uint // source item ID to target, by MergeReferences
+	nextID    uint
+	skuSeq    int64
+}
+
+func newMemItems(items ...models.Item) *memItems {
+	m := &memItems{items: make(map[uint]*models.Item), deleted: make(map[uint]bool), merged: make(map[uint]uint)}
+	for i := range items {
+		item := items[i]
+		if item.ID == 0 {
+			m.nextID++
+			item.ID = m.nextID
+		}
+		m.nextID = max(m.nextID, item.ID)
+		m.items[item.ID] = &item
+	}
+	return m
+}
+
+// get returns a copy of the item with id, deleted or not
+func (m *memItems) get(id uint) *models.Item {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.items[id]
+	if !ok {
+		return nil
+	}
+	found := *item
+	return &found
+}
+
+func (m *memItems) create(item *models.Item) error {
+	for id, existing := range m.items {
+		if !m.deleted[id] && existing.SKU == item.SKU {
+			return &repository.DuplicateKeyError{Constraint: "idx_items_sku_active"}
+		}
+	}
+	m.nextID++
+	item.ID = m.nextID
+	stored := *item
+	m.items[item.ID] = &stored
+	return nil
+}
+
+func (m *memItems) Create(_ context.Context, item *models.Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.create(item)
+}
+
+func (m *memItems) CreateBatch(_ context.Context, items []*models.Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, item := range items {
+		if err := m.create(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memItems) find(match func(*models.Item) bool) []models.Item {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var found []models.Item
+	for id, item := range m.items {
+		if !m.deleted[id] && match(item) {
+			found = append(found, *item)
+		}
+	}
+	return found
+}
+
+func (m *memItems) first(match func(*models.Item) bool) *models.Item {
+	if found := m.find(match); len(found) > 0 {
+		return &found[0]
+	}
+	return nil
+}
+
+func (m *memItems) FindByID(_ context.Context, id uint) (*models.Item, error) {
+	return m.first(func(item *models.Item) bool { return item.ID == id }), nil
+}
+
+func (m *memItems) FindByIDForUpdate(ctx context.Context, id uint) (*models.Item, error) {
+	return m.FindByID(ctx, id)
+}
+
+func (m *memItems) FindByIDWithDeleted(_ context.Context, id uint) (*models.Item, error) {
+	return m.get(id), nil
+}
+
+// Count counts the active items in opts.Category; other filters are not supported
+func (m *memItems) Count(_ context.Context, opts repository.ListOptions) (int64, error) {
+	return int64(len(m.find(func(item *models.Item) bool { return item.Category == opts.Category }))), nil
+}
+
+func (m *memItems) ReassignCategory(_ context.Context, from, to string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var moved int64
+	for id, item := range m.items {
+		if !m.deleted[id] && item.Category == from {
+			item.Category = to
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+func (m *memItems) DeleteByCategory(_ context.Context, category string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for id, item := range m.items {
+		if !m.deleted[id] && item.Category == category {
+			m.deleted[id] = true
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *memItems) FindBySKU(_ context.Context, sku string) (*models.Item, error) {
+	return m.first(func(item *models.Item) bool { return item.SKU == sku }), nil
+}
+
+func (m *memItems) FindBySKUs(_ context.Context, skus []string) ([]models.Item, error) {
+	return m.find(func(item *models.Item) bool { return slices.Contains(skus, item.SKU) }), nil
+}
+
+func (m *memItems) Update(_ context.Context, item *models.Item, priceChange *models.PriceHistory) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *item
+	m.items[item.ID] = &stored
+	if priceChange != nil {
+		m.prices = append(m.prices, *priceChange)
+	}
+	return nil
+}
+
+func (m *memItems) AdjustQuantity(_ context.Context, id uint, movement *models.StockMovement, allowNegative bool) (*models.Item, error) {
+	return m.changeQuantity(id, movement, allowNegative, func(current float64) float64 {
+		return current + movement.Delta
+	})
+}
+
+func (m *memItems) SetQuantity(_ context.Context, id uint, quantity float64, movement *models.StockMovement) (*models.Item, error) {
+	return m.changeQuantity(id, movement, false, func(float64) float64 { return quantity })
+}
+
+// changeQuantity follows the repository: the new quantity is rounded and checked, and the
+// movement records the resulting delta
+func (m *memItems) changeQuantity(id uint, movement *models.StockMovement, allowNegative bool, next func(current float64) float64) (*models.Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.items[id]
+	if !ok || m.deleted[id] {
+		return nil, nil
+	}
+	quantity := models.RoundQuantity(next(item.Quantity))
+	if quantity < 0 && !allowNegative {
+		return nil, repository.ErrInsufficientStock
+	}
+	if !models.ValidQuantity(item.Unit, quantity) {
+		return nil, repository.ErrFractionalQuantity
+	}
+	movement.Delta = models.RoundQuantity(quantity - item.Quantity)
+	movement.ItemID = id
+	movement.QuantityAfter = quantity
+	item.Quantity = quantity
+	item.Backordered = quantity < 0
+	m.movements = append(m.movements, *movement)
+	found := *item
+	return &found, nil
+}
+
+func (m *memItems) Delete(_ context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted[id] = true
+	return nil
+}
+
+func (m *memItems) SoftDelete(_ context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted[id] = true
+	return nil
+}
+
+func (m *memItems) NextSKUSequence(context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.skuSeq++
+	return m.skuSeq, nil
+}
+
+func (m *memItems) MergeReferences(_ context.Context, sourceID uint, target *models.Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.merged[sourceID] = target.ID
+	return nil
+}
+
+func (m *memItems) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.mu.Lock()
+	items := make(map[uint]*models.Item, len(m.items))
+	for id, item := range m.items {
+		saved := *item
+		items[id] = &saved
+	}
+	deleted := maps.Clone(m.deleted)
+	merged := maps.Clone(m.merged)
+	movements, prices := len(m.movements), len(m.prices)
+	m.mu.Unlock()
+
+	err := fn(ctx)
+	if err != nil {
+		m.mu.Lock()
+		m.items, m.deleted, m.merged = items, deleted, merged
+		m.movements, m.prices = m.movements[:movements], m.prices[:prices]
+		m.mu.Unlock()
+	}
+	return err
+}
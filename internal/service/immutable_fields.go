@@ -0,0 +1,79 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+// ImmutableFieldsError reports update fields that the policy does not allow to change
+type ImmutableFieldsError struct {
+	Fields []string
+}
+
+// Error implements the error interface
+func (e *ImmutableFieldsError) Error() string {
+	return "fields cannot be changed once set: " + strings.Join(e.Fields, ", ")
+}
+
+// Unwrap allows errors.Is(err, ErrValidation)
+func (e *ImmutableFieldsError) Unwrap() error {
+	return ErrValidation
+}
+
+// Details returns the rejected fields for the error response
+func (e *ImmutableFieldsError) Details() interface{} {
+	return map[string][]string{"fields": e.Fields}
+}
+
+// itemFieldChanges reports, per JSON field name, whether an update request changes that field
+var itemFieldChanges = map[string]func(item *models.Item, req *models.UpdateItemRequest) bool{
+	"name": func(i *models.Item, r *models.UpdateItemRequest) bool { return r.Name != nil && *r.Name != i.Name },
+	"sku":  func(i *models.Item, r *models.UpdateItemRequest) bool { return r.SKU != nil && *r.SKU != i.SKU },
+	"description": func(i *models.Item, r *models.UpdateItemRequest) bool {
+		return r.Description != nil && *r.Description != i.Description
+	},
+	"quantity": func(i *models.Item, r *models.UpdateItemRequest) bool {
+		return r.Quantity != nil && models.RoundQuantity(*r.Quantity) != i.Quantity
+	},
+	"unit":  func(i *models.Item, r *models.UpdateItemRequest) bool { return r.Unit != nil && *r.Unit != i.Unit },
+	"price": func(i *models.Item, r *models.UpdateItemRequest) bool { return r.Price != nil && *r.Price != i.Price },
+	"cost_price": func(i *models.Item, r *models.UpdateItemRequest) bool {
+		return r.CostPrice != nil && *r.CostPrice != i.CostPrice
+	},
+	"category": func(i *models.Item, r *models.UpdateItemRequest) bool {
+		return r.Category != nil && *r.Category != i.Category
+	},
+}
+
+// ValidateImmutableFields checks that every configured immutable field is an item field
+func ValidateImmutableFields(fields []string) error {
+	for _, field := range fields {
+		if _, ok := itemFieldChanges[field]; !ok {
+			known := make([]string, 0, len(itemFieldChanges))
+			for name := range itemFieldChanges {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			return fmt.Errorf("unknown item field %q; expected one of %s", field, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// checkImmutableFields rejects an update that changes a field the policy marks immutable.
+// Sending an immutable field with its current value is allowed.
+func (s *inventoryService) checkImmutableFields(item *models.Item, req *models.UpdateItemRequest) error {
+	var changed []string
+	for _, field := range s.policy.ImmutableFields {
+		if itemFieldChanges[field](item, req) {
+			changed = append(changed, field)
+		}
+	}
+	if len(changed) > 0 {
+		return &ImmutableFieldsError{Fields: changed}
+	}
+	return nil
+}
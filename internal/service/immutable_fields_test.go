@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+func TestImmutableSKU(t *testing.T) {
+	tests := []struct {
+		name       string
+		skuCase    string
+		req        models.UpdateItemRequest
+		wantFields []string
+	}{
+		{"changed SKU", SKUCasePreserve, models.UpdateItemRequest{SKU: ptr("W-2")}, []string{"sku"}},
+		{"same SKU", SKUCasePreserve, models.UpdateItemRequest{SKU: ptr("W-1"), Name: ptr("Widget 2")}, nil},
+		{"same SKU in another case", SKUCaseUpper, models.UpdateItemRequest{SKU: ptr("w-1")}, nil},
+		{"SKU left out", SKUCasePreserve, models.UpdateItemRequest{Price: ptr(12.0)}, nil},
+		{"changed SKU and price", SKUCasePreserve, models.UpdateItemRequest{SKU: ptr("W-2"), Price: ptr(12.0)}, []string{"sku"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestInventory(InventoryPolicy{ImmutableFields: []string{"sku"}, SKUCase: tt.skuCase},
+				models.Item{ID: 1, Name: "Widget", SKU: "W-1", Unit: models.UnitEach, Price: 10})
+
+			_, err := s.UpdateItem(context.Background(), 1, 1, &tt.req, "")
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("UpdateItem: %v", err)
+				}
+				return
+			}
+
+			var immutableErr *ImmutableFieldsError
+			if !errors.As(err, &immutableErr) || !errors.Is(err, ErrValidation) {
+				t.Fatalf("error = %v, want an immutable fields validation error", err)
+			}
+			if !reflect.DeepEqual(immutableErr.Fields, tt.wantFields) {
+				t.Errorf("rejected fields = %v, want %v", immutableErr.Fields, tt.wantFields)
+			}
+			if item := repo.get(1); item.SKU != "W-1" || item.Price != 10 {
+				t.Errorf("item = %+v, want it unchanged", item)
+			}
+		})
+	}
+}
+
+func TestImmutableSKUOnConflictUpdate(t *testing.T) {
+	s, _ := newTestInventory(InventoryPolicy{ImmutableFields: []string{"sku", "price"}},
+		models.Item{ID: 1, Name: "Widget", SKU: "W-1", Unit: models.UnitEach, Price: 10})
+
+	_, _, err := s.CreateItem(context.Background(), 1, &models.CreateItemRequest{Name: "Widget", SKU: "W-1", Price: 12}, models.OnConflictUpdate)
+	var immutableErr *ImmutableFieldsError
+	if !errors.As(err, &immutableErr) || !reflect.DeepEqual(immutableErr.Fields, []string{"price"}) {
+		t.Errorf("error = %v, want price rejected as immutable", err)
+	}
+}
+
+func TestValidateImmutableFields(t *testing.T) {
+	tests := []struct {
+		fields  []string
+		wantErr bool
+	}{
+		{nil, false},
+		{[]string{"sku", "cost_price", "unit"}, false},
+		{[]string{"SKU"}, true},
+		{[]string{"sku", "id"}, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateImmutableFields(tt.fields); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateImmutableFields(%v) error = %v, want error %v", tt.fields, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+// ErrUserNotFound is returned for a user that does not exist
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrImpersonationForbidden is returned when the target user may not be impersonated
+var ErrImpersonationForbidden = errors.New("user cannot be impersonated")
+
+// impersonatedByClaim carries the ID of the admin an impersonation token was issued to
+const impersonatedByClaim = "impersonated_by"
+
+// Impersonate issues a token for targetID on behalf of the admin actorID. The token is an
+// access token for the target, flagged with the impersonated_by claim, that expires after
+// ImpersonationTTL and cannot be renewed: a new one must be requested. Admins cannot be
+// impersonated, and neither can the actor themselves.
+func (s *authService) Impersonate(ctx context.Context, actorID, targetID uint, client models.SessionClient) (*models.ImpersonationResponse, error) {
+	if actorID == targetID {
+		return nil, ErrImpersonationForbidden
+	}
+	user, err := s.userRepo.FindByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &NotFoundError{Resource: "user", Key: "id", Value: targetID, err: ErrUserNotFound}
+	}
+	if user.Role == models.RoleAdmin {
+		return nil, ErrImpersonationForbidden
+	}
+
+	// The session lets the impersonation token be revoked like any other
+	expiresAt := time.Now().Add(s.opts.ImpersonationTTL)
+	session, err := s.startSession(ctx, user, client, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.generateToken(user, session.ID, expiresAt, jwt.MapClaims{
+		impersonatedByClaim: strconv.FormatUint(uint64(actorID), 10),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.ImpersonationResponse{
+		Token:          token,
+		ExpiresAt:      expiresAt,
+		User:           user.Summary(),
+		ImpersonatedBy: actorID,
+	}, nil
+}
+
+// GetImpersonatorFromToken returns the ID of the admin an impersonation token was issued
+// to; ok is false for every other token
+func (s *authService) GetImpersonatorFromToken(token *jwt.Token) (uint, bool) {
+	value, ok := StringClaim(token, impersonatedByClaim)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+func TestImpersonate(t *testing.T) {
+	s, users, _ := newTestAuth(func(opts *AuthOptions) { opts.ImpersonationTTL = 15 * time.Minute })
+	admin := mustRegister(t, s, "admin", "admin-pass")
+	users.users[admin.ID].Role = models.RoleAdmin
+	target := mustRegister(t, s, "henry", "henry-pass")
+	ctx := context.Background()
+
+	result, err := s.Impersonate(ctx, admin.ID, target.ID, models.SessionClient{})
+	if err != nil {
+		t.Fatalf("Impersonate: %v", err)
+	}
+	if result.ImpersonatedBy != admin.ID || result.User.ID != target.ID {
+		t.Errorf("result impersonated_by %d, user %d; want %d, %d", result.ImpersonatedBy, result.User.ID, admin.ID, target.ID)
+	}
+	if ttl := time.Until(result.ExpiresAt); ttl <= 14*time.Minute || ttl > 15*time.Minute {
+		t.Errorf("token expires in %v, want the 15m impersonation TTL", ttl)
+	}
+
+	token := mustValidate(t, s, result.Token)
+	if userID, err := s.GetUserFromToken(token); err != nil || userID != target.ID {
+		t.Errorf("token user = %d, %v; want the target %d", userID, err, target.ID)
+	}
+	if actorID, ok := s.GetImpersonatorFromToken(token); !ok || actorID != admin.ID {
+		t.Errorf("impersonator = %d, %v; want %d", actorID, ok, admin.ID)
+	}
+	if role := s.GetRoleFromToken(token); role != models.RoleUser {
+		t.Errorf("role = %q, want the target's role %q", role, models.RoleUser)
+	}
+	if introspection, err := s.Introspect(ctx, result.Token); err != nil || introspection.ImpersonatedBy != admin.ID {
+		t.Errorf("introspection = %+v, %v; want impersonated_by %d", introspection, err, admin.ID)
+	}
+}
+
+func TestImpersonateForbidden(t *testing.T) {
+	s, users, _ := newTestAuth(func(opts *AuthOptions) { opts.ImpersonationTTL = time.Minute })
+	admin := mustRegister(t, s, "admin", "admin-pass")
+	users.users[admin.ID].Role = models.RoleAdmin
+	otherAdmin := mustRegister(t, s, "admin2", "admin-pass")
+	users.users[otherAdmin.ID].Role = models.RoleAdmin
+
+	tests := []struct {
+		name     string
+		targetID uint
+		wantErr  error
+	}{
+		{"themselves", admin.ID, ErrImpersonationForbidden},
+		{"another admin", otherAdmin.ID, ErrImpersonationForbidden},
+		{"unknown user", 99, ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Impersonate(context.Background(), admin.ID, tt.targetID, models.SessionClient{}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetImpersonatorFromToken(t *testing.T) {
+	s, _, _ := newTestAuth(nil)
+
+	tests := []struct {
+		name   string
+		claim  interface{}
+		wantID uint
+		wantOK bool
+	}{
+		{"impersonation token", "3", 3, true},
+		{"not a number", "admin", 0, false},
+		{"number instead of string", 3, 0, false},
+		{"ordinary token", nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := jwt.MapClaims{"sub": "7", "exp": time.Now().Add(time.Hour).Unix()}
+			if tt.claim != nil {
+				claims[impersonatedByClaim] = tt.claim
+			}
+			id, ok := s.GetImpersonatorFromToken(mustValidate(t, s, signTestToken(t, claims)))
+			if id != tt.wantID || ok != tt.wantOK {
+				t.Errorf("GetImpersonatorFromToken = %d, %v; want %d, %v", id, ok, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
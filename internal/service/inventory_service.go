@@ -1,95 +1,658 @@
 package service
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/nielwyn/inventory-system/internal/export"
 	"github.com/nielwyn/inventory-system/internal/models"
 	"github.com/nielwyn/inventory-system/internal/repository"
 )
 
 // InventoryService handles inventory business logic
 type InventoryService interface {
-	CreateItem(req *models.CreateItemRequest) (*models.Item, error)
-	GetAllItems() ([]models.Item, error)
-	GetItemByID(id uint) (*models.Item, error)
-	UpdateItem(id uint, req *models.UpdateItemRequest) (*models.Item, error)
-	DeleteItem(id uint) error
+	CreateItem(ctx context.Context, userID uint, req *models.CreateItemRequest, onConflict string) (*models.Item, bool, error)
+	BulkCreateItems(ctx context.Context, req *models.BulkCreateItemsRequest) ([]*models.Item, error)
+	SyncItems(ctx context.Context, userID uint, req *models.SyncItemsRequest) (*models.SyncItemsResponse, error)
+	SyncItemStream(ctx context.Context, userID uint, next ItemBatches) (*models.SyncItemsResponse, error)
+	CloneItem(ctx context.Context, id uint, req *models.CloneItemRequest) (*models.Item, error)
+	GetAllItems(ctx context.Context, query *models.ListItemsQuery) ([]models.Item, error)
+	CountItems(ctx context.Context, query *models.ListItemsQuery) (int64, error)
+	ExportItems(ctx context.Context, query *models.ExportItemsQuery, fn func(items []models.Item) error) error
+	GetCategorySummary(ctx context.Context) ([]models.CategoryCount, error)
+	GetFacets(ctx context.Context, query *models.FacetsQuery) (*models.Facets, error)
+	GetMarginReport(ctx context.Context) (*models.MarginReport, error)
+	GetItemByID(ctx context.Context, id uint, includeDeleted bool) (*models.Item, error)
+	GetItemsByIDs(ctx context.Context, ids []uint) ([]models.Item, error)
+	UpdateItem(ctx context.Context, id, userID uint, req *models.UpdateItemRequest, ifMatch string) (*models.Item, error)
+	BulkUpdatePrices(ctx context.Context, userID uint, req *models.BulkPriceUpdateRequest) (*models.BulkPriceUpdateResponse, error)
+	DeleteItem(ctx context.Context, id uint) error
+	DeleteItemsByCategory(ctx context.Context, category string) (*models.DeleteItemsResponse, error)
+	AdjustStock(ctx context.Context, id, userID uint, req *models.AdjustStockRequest) (*models.Item, error)
+	AdjustStockBySKU(ctx context.Context, sku string, userID uint, req *models.AdjustStockRequest) (*models.Item, error)
+	Reconcile(ctx context.Context, userID uint, req *models.ReconcileRequest) (*models.ReconcileResponse, error)
+	MergeItems(ctx context.Context, userID uint, req *models.MergeItemsRequest) (*models.Item, error)
+}
+
+// InventoryPolicy holds the deployment-specific rules enforced on item writes
+type InventoryPolicy struct {
+	RequireCategory bool
+	MinPrice        float64
+
+	// Categories, when set, are the only categories items may be given
+	Categories []string
+
+	// AutoGenerateSKU assigns a SKU from SKUFormat to items created without one.
+	// SKUGenerationAttempts is how many generated SKUs a create tries before giving up
+	// when they are already taken.
+	AutoGenerateSKU       bool
+	SKUFormat             string
+	SKUGenerationAttempts int
+
+	// SKUCase normalizes SKUs to upper or lower case (see SKUCaseUpper and SKUCaseLower)
+	SKUCase string
+
+	// ImmutableFields lists item fields (by JSON name) that updates may not change
+	ImmutableFields []string
+
+	// MaxBatchGetIDs caps how many IDs a single batch-get may request (0 means no limit)
+	MaxBatchGetIDs int
+
+	// MaxXLSXExportRows caps how many items an XLSX export may contain, since
+	// workbooks are built in memory (0 means no limit)
+	MaxXLSXExportRows int
+
+	// AllowNegativeStock lets adjustments take quantity below zero, marking the item backordered
+	AllowNegativeStock bool
+
+	// DefaultSort orders item lists that do not ask for a sort
+	DefaultSort []repository.SortField
+}
+
+// validate checks an item's category and price against the policy
+func (p InventoryPolicy) validate(category string, price float64) error {
+	if p.RequireCategory && strings.TrimSpace(category) == "" {
+		return &ValidationError{Message: "Field 'Category' is required"}
+	}
+	if len(p.Categories) > 0 && category != "" && !slices.Contains(p.Categories, category) {
+		return &ValidationError{Message: "Field 'Category' must be one of: " + strings.Join(p.Categories, ", ")}
+	}
+	if price < p.MinPrice {
+		return &ValidationError{Message: fmt.Sprintf("Field 'Price' must be at least %.2f", p.MinPrice)}
+	}
+	return nil
+}
+
+// validateQuantity checks that quantity can be held in unit; items counted
+// individually cannot hold fractional quantities
+func validateQuantity(unit string, quantity float64) error {
+	if !models.ValidQuantity(unit, quantity) {
+		return &ValidationError{Message: fmt.Sprintf("Field 'Quantity' must be a whole number for unit '%s'", unit)}
+	}
+	return nil
 }
 
 type inventoryService struct {
-	repo repository.InventoryRepository
+	repo   repository.InventoryRepository
+	policy InventoryPolicy
 }
 
 // NewInventoryService creates a new inventory service
-func NewInventoryService(repo repository.InventoryRepository) InventoryService {
-	return &inventoryService{repo: repo}
+func NewInventoryService(repo repository.InventoryRepository, policy InventoryPolicy) InventoryService {
+	return &inventoryService{repo: repo, policy: policy}
 }
 
-// CreateItem creates a new inventory item
-func (s *inventoryService) CreateItem(req *models.CreateItemRequest) (*models.Item, error) {
+// CreateItem creates a new inventory item and reports whether it did. When the SKU is
+// already in use, onConflict decides what happens: models.OnConflictError (or empty)
+// returns ErrSKUExists, models.OnConflictIgnore returns the existing item unchanged, and
+// models.OnConflictUpdate updates it from req as UpdateItem would, on behalf of userID.
+func (s *inventoryService) CreateItem(ctx context.Context, userID uint, req *models.CreateItemRequest, onConflict string) (*models.Item, bool, error) {
+	if err := s.policy.validate(req.Category, req.Price); err != nil {
+		return nil, false, err
+	}
+	if err := validateQuantity(itemUnit(req), req.Quantity); err != nil {
+		return nil, false, err
+	}
+	if req.SKU == "" && s.policy.AutoGenerateSKU {
+		item, err := s.createWithGeneratedSKU(ctx, req)
+		return item, err == nil, err
+	}
+	if err := s.resolveSKU(ctx, req); err != nil {
+		return nil, false, err
+	}
+
 	// Check if SKU already exists
-	existingItem, err := s.repo.FindBySKU(req.SKU)
+	existingItem, err := s.repo.FindBySKU(ctx, req.SKU)
+	if err != nil {
+		return nil, false, err
+	}
+	if existingItem != nil {
+		item, err := s.resolveCreateConflict(ctx, userID, existingItem, req, onConflict)
+		return item, false, err
+	}
+
+	// Create item. The insert runs in a savepoint, so when a concurrent request creates
+	// the SKU first, the failure does not abort the request's transaction and the
+	// conflict is resolved as if the item had been found above.
+	item := newItem(req)
+	err = s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.repo.Create(ctx, item)
+	})
+	if err == nil {
+		return item, true, nil
+	}
+	if !errors.Is(err, repository.ErrDuplicateKey) {
+		return nil, false, itemConflictError(err)
+	}
+	existingItem, err = s.repo.FindBySKU(ctx, req.SKU)
+	if err != nil {
+		return nil, false, err
+	}
+	if existingItem == nil {
+		return nil, false, ErrSKUExists
+	}
+	item, err = s.resolveCreateConflict(ctx, userID, existingItem, req, onConflict)
+	return item, false, err
+}
+
+// resolveCreateConflict applies onConflict to a create request whose SKU is taken by existing
+func (s *inventoryService) resolveCreateConflict(ctx context.Context, userID uint, existing *models.Item, req *models.CreateItemRequest, onConflict string) (*models.Item, error) {
+	switch onConflict {
+	case models.OnConflictIgnore:
+		return existing, nil
+	case models.OnConflictUpdate:
+		return s.UpdateItem(ctx, existing.ID, userID, updateFromCreate(req), "")
+	}
+	return nil, ErrSKUExists
+}
+
+// updateFromCreate returns an update that sets every field of an item to those of a
+// create request, the unit defaulting as it would for a new item
+func updateFromCreate(req *models.CreateItemRequest) *models.UpdateItemRequest {
+	unit := itemUnit(req)
+	return &models.UpdateItemRequest{
+		Name:        &req.Name,
+		SKU:         &req.SKU,
+		Description: &req.Description,
+		Quantity:    &req.Quantity,
+		Unit:        &unit,
+		Price:       &req.Price,
+		CostPrice:   &req.CostPrice,
+		Category:    &req.Category,
+	}
+}
+
+// BulkCreateItems creates several items in one transaction.
+// The payload is checked for repeated SKUs and for SKUs already in use before
+// anything is written, so a bad upload fails up front rather than mid-transaction.
+func (s *inventoryService) BulkCreateItems(ctx context.Context, req *models.BulkCreateItemsRequest) ([]*models.Item, error) {
+	skus := make([]string, 0, len(req.Items))
+	seen := make(map[string]bool, len(req.Items))
+	var duplicates []string
+	for i := range req.Items {
+		itemReq := &req.Items[i]
+		if err := s.policy.validate(itemReq.Category, itemReq.Price); err != nil {
+			return nil, &ValidationError{Message: fmt.Sprintf("items[%d]: %s", i, err)}
+		}
+		if err := validateQuantity(itemUnit(itemReq), itemReq.Quantity); err != nil {
+			return nil, &ValidationError{Message: fmt.Sprintf("items[%d]: %s", i, err)}
+		}
+		if err := s.resolveSKU(ctx, itemReq); err != nil {
+			if errors.Is(err, ErrValidation) {
+				return nil, &ValidationError{Message: fmt.Sprintf("items[%d]: %s", i, err)}
+			}
+			return nil, err
+		}
+		if seen[itemReq.SKU] {
+			duplicates = append(duplicates, itemReq.SKU)
+			continue
+		}
+		seen[itemReq.SKU] = true
+		skus = append(skus, itemReq.SKU)
+	}
+	if len(duplicates) > 0 {
+		return nil, &DuplicateSKUsError{SKUs: duplicates}
+	}
+
+	// Check if any SKU already exists
+	existingItems, err := s.repo.FindBySKUs(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+	if len(existingItems) > 0 {
+		existing := make([]string, len(existingItems))
+		for i, item := range existingItems {
+			existing[i] = item.SKU
+		}
+		return nil, fmt.Errorf("%w: %s", ErrSKUExists, strings.Join(existing, ", "))
+	}
+
+	items := make([]*models.Item, len(req.Items))
+	for i := range req.Items {
+		items[i] = newItem(&req.Items[i])
+	}
+
+	if err := s.repo.CreateBatch(ctx, items); err != nil {
+		return nil, itemConflictError(err)
+	}
+
+	return items, nil
+}
+
+// ItemBatches returns the next batch of a feed of items, or an empty batch at its end
+type ItemBatches func() ([]models.SyncItemRequest, error)
+
+// SyncItems upserts a feed of items keyed by SKU, creating missing items and
+// updating existing ones in a single transaction, on behalf of userID
+func (s *inventoryService) SyncItems(ctx context.Context, userID uint, req *models.SyncItemsRequest) (*models.SyncItemsResponse, error) {
+	done := false
+	return s.SyncItemStream(ctx, userID, func() ([]models.SyncItemRequest, error) {
+		if done {
+			return nil, nil
+		}
+		done = true
+		return req.Items, nil
+	})
+}
+
+// SyncItemStream upserts a feed of items keyed by SKU like SyncItems, a batch at a time,
+// so only one batch and the SKUs seen so far are held in memory. Batches already written
+// are rolled back with the caller's transaction when a later one fails. Once a repeated
+// SKU turns up nothing more is written, but the feed is read to the end to report them all.
+func (s *inventoryService) SyncItemStream(ctx context.Context, userID uint, next ItemBatches) (*models.SyncItemsResponse, error) {
+	result := &models.SyncItemsResponse{}
+	seen := make(map[string]bool)
+	var duplicates []string
+	for offset := 0; ; {
+		batch, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for i := range batch {
+			entry := &batch[i]
+			if entry.SKU == "" {
+				return nil, &ValidationError{Message: fmt.Sprintf("items[%d]: Field 'SKU' is required", offset+i)}
+			}
+			entry.SKU = s.normalizeSKU(entry.SKU)
+			if seen[entry.SKU] {
+				duplicates = append(duplicates, entry.SKU)
+			}
+			seen[entry.SKU] = true
+		}
+		first := offset
+		offset += len(batch)
+		if len(duplicates) > 0 {
+			continue
+		}
+
+		created, updated, err := s.syncBatch(ctx, userID, batch, first)
+		if err != nil {
+			return nil, err
+		}
+		result.Created += created
+		result.Updated += updated
+	}
+	if len(duplicates) > 0 {
+		return nil, &DuplicateSKUsError{SKUs: duplicates}
+	}
+
+	return result, nil
+}
+
+// syncAttempts is how many times a sync batch is written before a SKU that other
+// requests keep creating first is reported as a conflict
+const syncAttempts = 3
+
+// syncBatch creates the entries of a feed whose SKU is not in use and updates the items
+// that have it, in one transaction. The existing items are locked in ID order while they
+// are updated. first is the position of the batch's first entry in the feed.
+//
+// Each attempt runs in a savepoint. When a concurrent request inserts one of the new SKUs
+// first, the insert fails on the unique index and only the attempt is rolled back; the
+// next one finds the item and updates it instead.
+func (s *inventoryService) syncBatch(ctx context.Context, userID uint, batch []models.SyncItemRequest, first int) (created, updated int, err error) {
+	skus := make([]string, len(batch))
+	for i := range batch {
+		skus[i] = batch[i].SKU
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+			created, updated, err = s.writeSyncBatch(ctx, userID, batch, skus, first)
+			return err
+		})
+		if err == nil {
+			return created, updated, nil
+		}
+		if !errors.Is(err, repository.ErrDuplicateKey) || attempt == syncAttempts {
+			return 0, 0, itemConflictError(err)
+		}
+	}
+}
+
+// writeSyncBatch makes one attempt at syncBatch, returning a failed insert's error as is
+func (s *inventoryService) writeSyncBatch(ctx context.Context, userID uint, batch []models.SyncItemRequest, skus []string, first int) (created, updated int, err error) {
+	existing, err := s.repo.FindBySKUs(ctx, skus)
+	if err != nil {
+		return 0, 0, err
+	}
+	slices.SortFunc(existing, func(a, b models.Item) int { return cmp.Compare(a.ID, b.ID) })
+	bySKU := make(map[string]*models.Item, len(existing))
+	for _, found := range existing {
+		item, err := s.repo.FindByIDForUpdate(ctx, found.ID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if item != nil {
+			bySKU[item.SKU] = item
+		}
+	}
+
+	var items []*models.Item
+	for i := range batch {
+		entry := &batch[i]
+		if item := bySKU[entry.SKU]; item != nil {
+			if err := s.syncItem(ctx, userID, item, entry); err != nil {
+				return 0, 0, entryError(first+i, err)
+			}
+			continue
+		}
+		req := entry.CreateRequest()
+		if err := s.policy.validate(req.Category, req.Price); err != nil {
+			return 0, 0, entryError(first+i, err)
+		}
+		if err := validateQuantity(itemUnit(req), req.Quantity); err != nil {
+			return 0, 0, entryError(first+i, err)
+		}
+		items = append(items, newItem(req))
+	}
+	if len(items) > 0 {
+		if err := s.repo.CreateBatch(ctx, items); err != nil {
+			return 0, 0, err
+		}
+	}
+	return len(items), len(batch) - len(items), nil
+}
+
+// syncItem updates an existing item from the fields a feed entry supplies; the others are
+// left as they are. As on the update and adjust endpoints, a new price is recorded in the
+// price history and a new quantity as a stock movement, of type sync.
+func (s *inventoryService) syncItem(ctx context.Context, userID uint, item *models.Item, entry *models.SyncItemRequest) error {
+	quantity := item.Quantity
+	if entry.Quantity != nil {
+		quantity = models.RoundQuantity(*entry.Quantity)
+	}
+	if entry.Unit != nil {
+		item.Unit = *entry.Unit
+	}
+	if err := validateQuantity(item.Unit, quantity); err != nil {
+		return err
+	}
+
+	item.Name = entry.Name
+	if entry.Description != nil {
+		item.Description = *entry.Description
+	}
+	var priceChange *models.PriceHistory
+	if entry.Price != nil && *entry.Price != item.Price {
+		priceChange = &models.PriceHistory{
+			ItemID:   item.ID,
+			OldPrice: item.Price,
+			NewPrice: *entry.Price,
+			Reason:   "sync",
+			UserID:   userID,
+		}
+		item.Price = *entry.Price
+	}
+	if entry.CostPrice != nil {
+		item.CostPrice = *entry.CostPrice
+	}
+	// As on update, an item may keep a category that has since been removed from the list
+	policy := s.policy
+	if entry.Category == nil || *entry.Category == item.Category {
+		policy.Categories = nil
+	}
+	if entry.Category != nil {
+		item.Category = *entry.Category
+	}
+	if err := policy.validate(item.Category, item.Price); err != nil {
+		return err
+	}
+
+	if err := s.repo.Update(ctx, item, priceChange); err != nil {
+		return itemConflictError(err)
+	}
+	if quantity == item.Quantity {
+		return nil
+	}
+	movement := &models.StockMovement{Type: models.MovementTypeSync, UserID: userID}
+	if _, err := s.repo.SetQuantity(ctx, item.ID, quantity, movement); err != nil {
+		return itemConflictError(err)
+	}
+	return nil
+}
+
+// entryError names the feed entry at index in a validation error
+func entryError(index int, err error) error {
+	if errors.Is(err, ErrValidation) {
+		return &ValidationError{Message: fmt.Sprintf("items[%d]: %s", index, err)}
+	}
+	return err
+}
+
+// CloneItem creates a copy of an existing item under a new SKU, optionally with zero quantity
+func (s *inventoryService) CloneItem(ctx context.Context, id uint, req *models.CloneItemRequest) (*models.Item, error) {
+	source, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, itemNotFound(id)
+	}
+
+	sku := s.normalizeSKU(req.SKU)
+	existingItem, err := s.repo.FindBySKU(ctx, sku)
 	if err != nil {
 		return nil, err
 	}
 	if existingItem != nil {
-		return nil, errors.New("item with this SKU already exists")
+		return nil, ErrSKUExists
+	}
+
+	clone := &models.Item{
+		Name:        source.Name,
+		SKU:         sku,
+		Description: source.Description,
+		Quantity:    source.Quantity,
+		Unit:        source.Unit,
+		Price:       source.Price,
+		CostPrice:   source.CostPrice,
+		Category:    source.Category,
+	}
+	if req.ResetQuantity {
+		clone.Quantity = 0
+	}
+
+	if err := s.repo.Create(ctx, clone); err != nil {
+		return nil, itemConflictError(err)
 	}
 
-	// Create item
-	item := &models.Item{
+	return clone, nil
+}
+
+// newItem builds an item from a create request
+func newItem(req *models.CreateItemRequest) *models.Item {
+	return &models.Item{
 		Name:        req.Name,
 		SKU:         req.SKU,
 		Description: req.Description,
-		Quantity:    req.Quantity,
+		Quantity:    models.RoundQuantity(req.Quantity),
+		Unit:        itemUnit(req),
 		Price:       req.Price,
+		CostPrice:   req.CostPrice,
 		Category:    req.Category,
 	}
+}
 
-	if err := s.repo.Create(item); err != nil {
-		return nil, err
+// itemUnit returns the unit of measure requested for a new item, defaulting to each
+func itemUnit(req *models.CreateItemRequest) string {
+	if req.Unit == "" {
+		return models.UnitEach
 	}
+	return req.Unit
+}
 
-	return item, nil
+// GetAllItems retrieves a page of inventory items matching the query
+func (s *inventoryService) GetAllItems(ctx context.Context, query *models.ListItemsQuery) ([]models.Item, error) {
+	sort, err := repository.ParseSort(query.Sort)
+	if err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("Field 'Sort' contains an %s; sortable fields are %s", err, strings.Join(repository.SortableFields(), ", "))}
+	}
+	if sort == nil {
+		sort = s.policy.DefaultSort
+	}
+	return s.repo.FindAll(ctx, repository.ListOptions{
+		Category: query.Category,
+		Search:   query.Search,
+		Sort:     sort,
+		Fields:   models.SplitFields(query.Fields),
+		Offset:   query.Offset(),
+		Limit:    query.PageSize,
+
+		IncludeDeleted: query.IncludeDeleted,
+	})
+}
+
+// CountItems returns the total number of items matching the query filters
+func (s *inventoryService) CountItems(ctx context.Context, query *models.ListItemsQuery) (int64, error) {
+	return s.repo.Count(ctx, repository.ListOptions{
+		Category:       query.Category,
+		Search:         query.Search,
+		IncludeDeleted: query.IncludeDeleted,
+	})
+}
+
+// exportBatchSize is how many items are read from the database at a time during an export
+const exportBatchSize = 500
+
+// ExportItems passes every item matching the query filters to fn in batches, in ID order.
+// XLSX exports larger than the policy allows are rejected before fn is called.
+func (s *inventoryService) ExportItems(ctx context.Context, query *models.ExportItemsQuery, fn func(items []models.Item) error) error {
+	opts := repository.ListOptions{
+		Category: query.Category,
+		Search:   query.Search,
+	}
+
+	if query.Format == export.FormatXLSX && s.policy.MaxXLSXExportRows > 0 {
+		count, err := s.repo.Count(ctx, opts)
+		if err != nil {
+			return err
+		}
+		if count > int64(s.policy.MaxXLSXExportRows) {
+			return &ValidationError{Message: fmt.Sprintf("XLSX exports are limited to %d items; narrow the filters or export as CSV", s.policy.MaxXLSXExportRows)}
+		}
+	}
+
+	return s.repo.FindInBatches(ctx, opts, exportBatchSize, fn)
+}
+
+// GetCategorySummary retrieves each category with its item count
+func (s *inventoryService) GetCategorySummary(ctx context.Context) ([]models.CategoryCount, error) {
+	return s.repo.CountByCategory(ctx, repository.ListOptions{})
+}
+
+// GetFacets retrieves the category counts for items matching a search.
+// It uses the same filter as GetAllItems so the counts match the list results.
+func (s *inventoryService) GetFacets(ctx context.Context, query *models.FacetsQuery) (*models.Facets, error) {
+	categories, err := s.repo.CountByCategory(ctx, repository.ListOptions{Search: query.Search})
+	if err != nil {
+		return nil, err
+	}
+	return &models.Facets{Categories: categories}, nil
 }
 
-// GetAllItems retrieves all inventory items
-func (s *inventoryService) GetAllItems() ([]models.Item, error) {
-	return s.repo.FindAll()
+// GetMarginReport retrieves the margin of the stock held per category and in total
+func (s *inventoryService) GetMarginReport(ctx context.Context) (*models.MarginReport, error) {
+	categories, err := s.repo.MarginByCategory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.MarginReport{Categories: categories}
+	for _, category := range categories {
+		report.Total += category.Margin
+	}
+	return report, nil
 }
 
 // GetItemByID retrieves an item by ID
-func (s *inventoryService) GetItemByID(id uint) (*models.Item, error) {
-	item, err := s.repo.FindByID(id)
+func (s *inventoryService) GetItemByID(ctx context.Context, id uint, includeDeleted bool) (*models.Item, error) {
+	find := s.repo.FindByID
+	if includeDeleted {
+		find = s.repo.FindByIDWithDeleted
+	}
+	item, err := find(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	if item == nil {
-		return nil, errors.New("item not found")
+		return nil, itemNotFound(id)
 	}
 	return item, nil
 }
 
-// UpdateItem updates an existing item
-func (s *inventoryService) UpdateItem(id uint, req *models.UpdateItemRequest) (*models.Item, error) {
-	// Find existing item
-	item, err := s.repo.FindByID(id)
+// GetItemsByIDs retrieves the items matching the given IDs in one query.
+// IDs that do not exist are simply absent from the result.
+func (s *inventoryService) GetItemsByIDs(ctx context.Context, ids []uint) ([]models.Item, error) {
+	if s.policy.MaxBatchGetIDs > 0 && len(ids) > s.policy.MaxBatchGetIDs {
+		return nil, &ValidationError{Message: fmt.Sprintf("Field 'IDs' must contain at most %d IDs", s.policy.MaxBatchGetIDs)}
+	}
+	return s.repo.FindByIDs(ctx, ids)
+}
+
+// UpdateItem updates an existing item. When ifMatch is not empty it must match the
+// item's current ETag, otherwise ErrPreconditionFailed is returned and nothing changes.
+func (s *inventoryService) UpdateItem(ctx context.Context, id, userID uint, req *models.UpdateItemRequest, ifMatch string) (*models.Item, error) {
+	// Find existing item, locking it while the ETag is checked so a concurrent
+	// update cannot slip in between the check and the save
+	find := s.repo.FindByID
+	if ifMatch != "" {
+		find = s.repo.FindByIDForUpdate
+	}
+	item, err := find(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	if item == nil {
-		return nil, errors.New("item not found")
+		return nil, itemNotFound(id)
+	}
+	if ifMatch != "" && !etagMatches(ifMatch, item.ETag()) {
+		return nil, ErrPreconditionFailed
+	}
+	if req.SKU != nil {
+		sku := s.normalizeSKU(*req.SKU)
+		req.SKU = &sku
+	}
+	if err := s.checkImmutableFields(item, req); err != nil {
+		return nil, err
 	}
 
 	// Check if SKU is being updated and if it already exists
 	if req.SKU != nil && *req.SKU != item.SKU {
-		existingItem, err := s.repo.FindBySKU(*req.SKU)
+		existingItem, err := s.repo.FindBySKU(ctx, *req.SKU)
 		if err != nil {
 			return nil, err
 		}
 		if existingItem != nil {
-			return nil, fmt.Errorf("item with SKU '%s' already exists", *req.SKU)
+			return nil, fmt.Errorf("%w: '%s'", ErrSKUExists, *req.SKU)
 		}
 		item.SKU = *req.SKU
 	}
@@ -102,33 +665,265 @@ func (s *inventoryService) UpdateItem(id uint, req *models.UpdateItemRequest) (*
 		item.Description = *req.Description
 	}
 	if req.Quantity != nil {
-		item.Quantity = *req.Quantity
+		item.Quantity = models.RoundQuantity(*req.Quantity)
 	}
-	if req.Price != nil {
+	if req.Unit != nil {
+		item.Unit = *req.Unit
+	}
+	if err := validateQuantity(item.Unit, item.Quantity); err != nil {
+		return nil, err
+	}
+	var priceChange *models.PriceHistory
+	if req.Price != nil && *req.Price != item.Price {
+		priceChange = &models.PriceHistory{
+			ItemID:   item.ID,
+			OldPrice: item.Price,
+			NewPrice: *req.Price,
+			Reason:   "manual update",
+			UserID:   userID,
+		}
 		item.Price = *req.Price
 	}
+	if req.CostPrice != nil {
+		item.CostPrice = *req.CostPrice
+	}
+	// An item may keep a category that has since been removed from the allowed list
+	policy := s.policy
+	if req.Category == nil || *req.Category == item.Category {
+		policy.Categories = nil
+	}
 	if req.Category != nil {
 		item.Category = *req.Category
 	}
 
-	// Save updated item
-	if err := s.repo.Update(item); err != nil {
+	if err := policy.validate(item.Category, item.Price); err != nil {
 		return nil, err
 	}
 
+	// Save updated item
+	if err := s.repo.Update(ctx, item, priceChange); err != nil {
+		return nil, itemConflictError(err)
+	}
+
 	return item, nil
 }
 
+// etagMatches reports whether an If-Match header value matches etag. The header may
+// list several tags or be "*"; weak tags never match, as If-Match uses strong comparison.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // DeleteItem deletes an item by ID
-func (s *inventoryService) DeleteItem(id uint) error {
+func (s *inventoryService) DeleteItem(ctx context.Context, id uint) error {
 	// Check if item exists
-	item, err := s.repo.FindByID(id)
+	item, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
 	if item == nil {
-		return errors.New("item not found")
+		return itemNotFound(id)
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// DeleteItemsByCategory deletes every active item in a category in one statement.
+// Unlike deleting the category itself it ignores the category delete behavior, and a
+// category without items deletes nothing rather than failing.
+func (s *inventoryService) DeleteItemsByCategory(ctx context.Context, category string) (*models.DeleteItemsResponse, error) {
+	deleted, err := s.repo.DeleteByCategory(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DeleteItemsResponse{Category: category, Deleted: deleted}, nil
+}
+
+// BulkUpdatePrices changes the price of every item in a category in one statement,
+// recording price history for each item whose price changed
+func (s *inventoryService) BulkUpdatePrices(ctx context.Context, userID uint, req *models.BulkPriceUpdateRequest) (*models.BulkPriceUpdateResponse, error) {
+	if req.Operation == models.PriceOperationPercent && *req.Value <= -100 {
+		return nil, &ValidationError{Message: "Field 'Value' must be greater than -100 for a percent change"}
 	}
 
-	return s.repo.Delete(id)
+	updated, err := s.repo.BulkUpdatePrices(ctx, repository.PriceUpdate{
+		Category:  req.Category,
+		Operation: req.Operation,
+		Value:     *req.Value,
+		MinPrice:  s.policy.MinPrice,
+		Reason:    fmt.Sprintf("bulk %s %g", req.Operation, *req.Value),
+		UserID:    userID,
+	})
+	if errors.Is(err, repository.ErrPriceBelowMinimum) {
+		return nil, &ValidationError{Message: fmt.Sprintf("Price update would take some prices below %.2f", s.policy.MinPrice)}
+	}
+	if err != nil {
+		return nil, itemConflictError(err)
+	}
+	return &models.BulkPriceUpdateResponse{Updated: updated}, nil
+}
+
+// AdjustStock atomically changes an item's quantity by a delta, or sets it to an
+// absolute value, and records a stock movement
+func (s *inventoryService) AdjustStock(ctx context.Context, id, userID uint, req *models.AdjustStockRequest) (*models.Item, error) {
+	movement := &models.StockMovement{
+		Type:   models.MovementTypeAdjustment,
+		Reason: req.Reason,
+		UserID: userID,
+	}
+
+	var item *models.Item
+	var err error
+	switch {
+	case req.Delta != nil && req.Set != nil:
+		return nil, &ValidationError{Message: "Fields 'Delta' and 'Set' are mutually exclusive"}
+	case req.Delta != nil:
+		movement.Delta = *req.Delta
+		item, err = s.repo.AdjustQuantity(ctx, id, movement, s.policy.AllowNegativeStock)
+	case req.Set != nil:
+		item, err = s.repo.SetQuantity(ctx, id, *req.Set, movement)
+	default:
+		return nil, &ValidationError{Message: "One of fields 'Delta' or 'Set' is required"}
+	}
+	if errors.Is(err, repository.ErrFractionalQuantity) {
+		return nil, &ValidationError{Message: "Quantity must stay a whole number for items counted individually"}
+	}
+	if err != nil {
+		return nil, itemConflictError(err)
+	}
+	if item == nil {
+		return nil, itemNotFound(id)
+	}
+	return item, nil
+}
+
+// MergeItems folds a duplicate source item into the target in one transaction. The source's
+// quantity is added to the target with a merge movement, everything referring to the source
+// is moved to the target, and the source is deleted. Both items must share a unit.
+func (s *inventoryService) MergeItems(ctx context.Context, userID uint, req *models.MergeItemsRequest) (*models.Item, error) {
+	var target *models.Item
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Lock in ID order so two merges of the same pair cannot deadlock
+		locked := make(map[uint]*models.Item, 2)
+		for _, id := range []uint{min(req.SourceID, req.TargetID), max(req.SourceID, req.TargetID)} {
+			item, err := s.repo.FindByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if item == nil {
+				return itemNotFound(id)
+			}
+			locked[id] = item
+		}
+		source := locked[req.SourceID]
+		if source.Unit != locked[req.TargetID].Unit {
+			return &ValidationError{Message: fmt.Sprintf("Cannot merge an item counted in '%s' into one counted in '%s'", source.Unit, locked[req.TargetID].Unit)}
+		}
+
+		if err := s.repo.MergeReferences(ctx, source.ID, locked[req.TargetID]); err != nil {
+			return itemConflictError(err)
+		}
+		movement := &models.StockMovement{
+			Type:   models.MovementTypeMerge,
+			Delta:  source.Quantity,
+			Reason: fmt.Sprintf("merged from item %d (SKU %s)", source.ID, source.SKU),
+			UserID: userID,
+		}
+		var err error
+		target, err = s.repo.AdjustQuantity(ctx, req.TargetID, movement, true)
+		if err != nil {
+			return itemConflictError(err)
+		}
+		// The source is kept even with DB_DELETE_MODE=hard, so the merge movement that
+		// names it can still be traced back to it
+		return s.repo.SoftDelete(ctx, source.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// AdjustStockBySKU resolves an item by SKU and adjusts its stock like AdjustStock
+func (s *inventoryService) AdjustStockBySKU(ctx context.Context, sku string, userID uint, req *models.AdjustStockRequest) (*models.Item, error) {
+	item, err := s.repo.FindBySKU(ctx, s.normalizeSKU(sku))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, &NotFoundError{Resource: "item", Key: "sku", Value: sku, err: ErrItemNotFound}
+	}
+	return s.AdjustStock(ctx, item.ID, userID, req)
+}
+
+// Reconcile sets each counted item to its counted quantity and records a reconciliation
+// movement with the difference. Either every item is corrected or, if any SKU is unknown
+// or any count is invalid, none is.
+func (s *inventoryService) Reconcile(ctx context.Context, userID uint, req *models.ReconcileRequest) (*models.ReconcileResponse, error) {
+	seen := make(map[string]bool, len(req.Items))
+	var duplicates []string
+	for i := range req.Items {
+		count := &req.Items[i]
+		count.SKU = s.normalizeSKU(count.SKU)
+		if seen[count.SKU] {
+			duplicates = append(duplicates, count.SKU)
+		}
+		seen[count.SKU] = true
+	}
+	if len(duplicates) > 0 {
+		return nil, &DuplicateSKUsError{SKUs: duplicates}
+	}
+
+	result := &models.ReconcileResponse{Adjustments: make([]models.ReconcileAdjustment, 0, len(req.Items))}
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i, count := range req.Items {
+			item, err := s.repo.FindBySKU(ctx, count.SKU)
+			if err != nil {
+				return err
+			}
+			if item == nil {
+				return &NotFoundError{Resource: "item", Key: "sku", Value: count.SKU, err: ErrItemNotFound}
+			}
+
+			movement := &models.StockMovement{
+				Type:   models.MovementTypeReconciliation,
+				Reason: req.Reason,
+				UserID: userID,
+			}
+			item, err = s.repo.SetQuantity(ctx, item.ID, *count.CountedQuantity, movement)
+			if errors.Is(err, repository.ErrFractionalQuantity) {
+				return &ValidationError{Message: fmt.Sprintf("items[%d]: Field 'CountedQuantity' must be a whole number for unit '%s'", i, models.UnitEach)}
+			}
+			if err != nil {
+				return itemConflictError(err)
+			}
+			if item == nil {
+				return &NotFoundError{Resource: "item", Key: "sku", Value: count.SKU, err: ErrItemNotFound}
+			}
+
+			if movement.Delta == 0 {
+				result.Unchanged++
+			} else {
+				result.Adjusted++
+			}
+			result.Adjustments = append(result.Adjustments, models.ReconcileAdjustment{
+				ItemID:           item.ID,
+				SKU:              item.SKU,
+				PreviousQuantity: models.RoundQuantity(movement.QuantityAfter - movement.Delta),
+				CountedQuantity:  movement.QuantityAfter,
+				Delta:            movement.Delta,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
@@ -0,0 +1,607 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+// newTestInventory returns an inventory service over an in-memory repository holding items
+func newTestInventory(policy InventoryPolicy, items ...models.Item) (*inventoryService, *memItems) {
+	repo := newMemItems(items...)
+	return NewInventoryService(repo, policy).(*inventoryService), repo
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestSyncItemsKeepsOmittedFields(t *testing.T) {
+	s, repo := newTestInventory(InventoryPolicy{}, models.Item{
+		ID: 1, Name: "Flour", SKU: "FLOUR", Description: "Plain", Quantity: 2.5, Unit: models.UnitKg,
+		Price: 3, CostPrice: 1.2, Category: "Baking",
+	})
+
+	result, err := s.SyncItems(context.Background(), 9, &models.SyncItemsRequest{Items: []models.SyncItemRequest{
+		{Name: "Plain flour", SKU: "FLOUR"},
+		{Name: "Sugar", SKU: "SUGAR", Price: ptr(2.0)},
+	}})
+	if err != nil {
+		t.Fatalf("SyncItems: %v", err)
+	}
+	if result.Created != 1 || result.Updated != 1 {
+		t.Errorf("result = %+v, want 1 created and 1 updated", result)
+	}
+
+	flour := repo.get(1)
+	want := models.Item{ID: 1, Name: "Plain flour", SKU: "FLOUR", Description: "Plain", Quantity: 2.5, Unit: models.UnitKg, Price: 3, CostPrice: 1.2, Category: "Baking"}
+	if *flour != want {
+		t.Errorf("updated item = %+v, want %+v", *flour, want)
+	}
+	sugar, _ := repo.FindBySKU(context.Background(), "SUGAR")
+	if sugar == nil || sugar.Unit != models.UnitEach || sugar.Quantity != 0 || sugar.Price != 2 {
+		t.Errorf("created item = %+v, want unit each, quantity 0 and price 2", sugar)
+	}
+	if len(repo.movements) != 0 || len(repo.prices) != 0 {
+		t.Errorf("recorded %d movements and %d price changes for a sync changing neither", len(repo.movements), len(repo.prices))
+	}
+}
+
+func TestSyncItemsRecordsChanges(t *testing.T) {
+	s, repo := newTestInventory(InventoryPolicy{}, models.Item{ID: 1, Name: "Bolt", SKU: "BOLT", Quantity: 10, Unit: models.UnitEach, Price: 0.5})
+
+	_, err := s.SyncItems(context.Background(), 9, &models.SyncItemsRequest{Items: []models.SyncItemRequest{
+		{Name: "Bolt", SKU: "BOLT", Quantity: ptr(25.0), Price: ptr(0.75)},
+	}})
+	if err != nil {
+		t.Fatalf("SyncItems: %v", err)
+	}
+
+	if item := repo.get(1); item.Quantity != 25 || item.Price != 0.75 {
+		t.Errorf("item = %+v, want quantity 25 and price 0.75", item)
+	}
+	if len(repo.movements) != 1 {
+		t.Fatalf("recorded %d movements, want 1", len(repo.movements))
+	}
+	movement := repo.movements[0]
+	if movement.Type != models.MovementTypeSync || movement.Delta != 15 || movement.QuantityAfter != 25 || movement.UserID != 9 {
+		t.Errorf("movement = %+v, want a sync movement of 15 to 25 by user 9", movement)
+	}
+	if len(repo.prices) != 1 {
+		t.Fatalf("recorded %d price changes, want 1", len(repo.prices))
+	}
+	if change := repo.prices[0]; change.OldPrice != 0.5 || change.NewPrice != 0.75 || change.UserID != 9 {
+		t.Errorf("price change = %+v, want 0.5 to 0.75 by user 9", change)
+	}
+}
+
+// lateItems misses the items it holds on the first misses SKU lookups, as when another
+// request inserts them after a create or sync looked for them but before it inserts them
+// itself
+type lateItems struct {
+	*memItems
+	misses int
+}
+
+func (m *lateItems) FindBySKUs(ctx context.Context, skus []string) ([]models.Item, error) {
+	if m.misses > 0 {
+		m.misses--
+		return nil, nil
+	}
+	return m.memItems.FindBySKUs(ctx, skus)
+}
+
+func (m *lateItems) FindBySKU(ctx context.Context, sku string) (*models.Item, error) {
+	if m.misses > 0 {
+		m.misses--
+		return nil, nil
+	}
+	return m.memItems.FindBySKU(ctx, sku)
+}
+
+func TestSyncItemsConcurrentCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		misses  int
+		wantErr error
+	}{
+		{"created by another request once", 1, nil},
+		{"created by another request on every attempt", syncAttempts, ErrSKUExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &lateItems{memItems: newMemItems(models.Item{ID: 1, Name: "Bolt", SKU: "BOLT", Quantity: 10, Unit: models.UnitEach}), misses: tt.misses}
+			s := NewInventoryService(repo, InventoryPolicy{})
+
+			result, err := s.SyncItems(context.Background(), 9, &models.SyncItemsRequest{Items: []models.SyncItemRequest{
+				{Name: "Nut", SKU: "NUT", Quantity: ptr(4.0)},
+				{Name: "Bolt", SKU: "BOLT", Quantity: ptr(12.0)},
+			}})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			nuts := repo.find(func(item *models.Item) bool { return item.SKU == "NUT" })
+			if err != nil {
+				if len(nuts) != 0 || repo.get(1).Quantity != 10 {
+					t.Errorf("failed sync left %d new items and quantity %v", len(nuts), repo.get(1).Quantity)
+				}
+				return
+			}
+			if result.Created != 1 || result.Updated != 1 {
+				t.Errorf("result = %+v, want 1 created and 1 updated", result)
+			}
+			if len(nuts) != 1 || repo.get(1).Quantity != 12 {
+				t.Errorf("%d items with the new SKU and quantity %v, want 1 and 12", len(nuts), repo.get(1).Quantity)
+			}
+			if len(repo.movements) != 1 {
+				t.Errorf("recorded %d movements, want only the one of the retry", len(repo.movements))
+			}
+		})
+	}
+}
+
+func TestSyncItemsValidatesAgainstExistingUnit(t *testing.T) {
+	s, _ := newTestInventory(InventoryPolicy{}, models.Item{ID: 1, Name: "Cable", SKU: "CABLE", Quantity: 3, Unit: models.UnitEach})
+
+	tests := []struct {
+		name    string
+		entry   models.SyncItemRequest
+		wantErr bool
+	}{
+		{"fraction of an item counted individually", models.SyncItemRequest{Name: "Cable", SKU: "CABLE", Quantity: ptr(1.5)}, true},
+		{"fraction with a new unit", models.SyncItemRequest{Name: "Cable", SKU: "CABLE", Quantity: ptr(1.5), Unit: ptr(models.UnitMeter)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.SyncItems(context.Background(), 9, &models.SyncItemsRequest{Items: []models.SyncItemRequest{tt.entry}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrValidation) {
+				t.Errorf("error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestCreateItemRecreatesDeletedSKU(t *testing.T) {
+	s, _ := newTestInventory(InventoryPolicy{})
+	ctx := context.Background()
+	req := func() *models.CreateItemRequest {
+		return &models.CreateItemRequest{Name: "Lamp", SKU: "LAMP-1", Quantity: 1}
+	}
+
+	first, _, err := s.CreateItem(ctx, 1, req(), "")
+	if err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	if _, _, err := s.CreateItem(ctx, 1, req(), ""); !errors.Is(err, ErrSKUExists) {
+		t.Fatalf("creating an active SKU: error = %v, want %v", err, ErrSKUExists)
+	}
+	if err := s.DeleteItem(ctx, first.ID); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	second, created, err := s.CreateItem(ctx, 1, req(), "")
+	if err != nil || !created {
+		t.Fatalf("re-creating a deleted SKU = %v, %v; want it created", created, err)
+	}
+	if second.ID == first.ID {
+		t.Errorf("re-created item reused ID %d", first.ID)
+	}
+}
+
+func TestItemWriteErrors(t *testing.T) {
+	s, _ := newTestInventory(InventoryPolicy{},
+		models.Item{ID: 1, Name: "Widget", SKU: "W-1", Unit: models.UnitEach},
+		models.Item{ID: 2, Name: "Gadget", SKU: "G-1", Unit: models.UnitEach},
+	)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{"update a missing item", func() error {
+			_, err := s.UpdateItem(ctx, 9, 1, &models.UpdateItemRequest{Name: ptr("Thing")}, "")
+			return err
+		}, ErrItemNotFound},
+		{"update to a SKU in use", func() error {
+			_, err := s.UpdateItem(ctx, 1, 1, &models.UpdateItemRequest{SKU: ptr("G-1")}, "")
+			return err
+		}, ErrSKUExists},
+		{"update to a fraction of an item counted individually", func() error {
+			_, err := s.UpdateItem(ctx, 1, 1, &models.UpdateItemRequest{Quantity: ptr(0.5)}, "")
+			return err
+		}, ErrValidation},
+		{"delete a missing item", func() error { return s.DeleteItem(ctx, 9) }, ErrItemNotFound},
+		{"create with a SKU in use", func() error {
+			_, _, err := s.CreateItem(ctx, 1, &models.CreateItemRequest{Name: "Widget", SKU: "W-1"}, "")
+			return err
+		}, ErrSKUExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAdjustStockNegativeAllowance(t *testing.T) {
+	tests := []struct {
+		name            string
+		allowNegative   bool
+		wantErr         error
+		wantQuantity    float64
+		wantBackordered bool
+	}{
+		{"rejected by default", false, ErrInsufficientStock, 3, false},
+		{"backordered when allowed", true, nil, -2, true},
+	}
+	for _, tt := range tests {
+		for _, bySKU := range []bool{false, true} {
+			name := tt.name
+			if bySKU {
+				name += " by SKU"
+			}
+			t.Run(name, func(t *testing.T) {
+				s, repo := newTestInventory(InventoryPolicy{AllowNegativeStock: tt.allowNegative},
+					models.Item{ID: 1, Name: "Widget", SKU: "W-1", Quantity: 3, Unit: models.UnitEach})
+				req := &models.AdjustStockRequest{Delta: ptr(-5.0)}
+
+				var err error
+				if bySKU {
+					_, err = s.AdjustStockBySKU(context.Background(), "W-1", 1, req)
+				} else {
+					_, err = s.AdjustStock(context.Background(), 1, 1, req)
+				}
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				if item := repo.get(1); item.Quantity != tt.wantQuantity || item.Backordered != tt.wantBackordered {
+					t.Errorf("item quantity %v, backordered %v; want %v, %v", item.Quantity, item.Backordered, tt.wantQuantity, tt.wantBackordered)
+				}
+			})
+		}
+	}
+}
+
+func TestUnknownCategoryRejected(t *testing.T) {
+	policy := InventoryPolicy{Categories: []string{"Parts", "Tools"}}
+	existing := models.Item{ID: 1, Name: "Widget", SKU: "W-1", Unit: models.UnitEach, Category: "Retired"}
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		category string
+		write    func(s *inventoryService, category string) error
+	}{
+		{"create", "Prats", func(s *inventoryService, category string) error {
+			_, _, err := s.CreateItem(ctx, 1, &models.CreateItemRequest{Name: "Bolt", SKU: "B-1", Category: category}, "")
+			return err
+		}},
+		{"bulk create", "Prats", func(s *inventoryService, category string) error {
+			_, err := s.BulkCreateItems(ctx, &models.BulkCreateItemsRequest{Items: []models.CreateItemRequest{
+				{Name: "Bolt", SKU: "B-1", Category: "Parts"},
+				{Name: "Nut", SKU: "N-1", Category: category},
+			}})
+			return err
+		}},
+		{"update", "Prats", func(s *inventoryService, category string) error {
+			_, err := s.UpdateItem(ctx, 1, 1, &models.UpdateItemRequest{Category: &category}, "")
+			return err
+		}},
+		{"sync new item", "Prats", func(s *inventoryService, category string) error {
+			_, err := s.SyncItems(ctx, 1, &models.SyncItemsRequest{Items: []models.SyncItemRequest{{Name: "Bolt", SKU: "B-1", Category: &category}}})
+			return err
+		}},
+		{"sync existing item", "Prats", func(s *inventoryService, category string) error {
+			_, err := s.SyncItems(ctx, 1, &models.SyncItemsRequest{Items: []models.SyncItemRequest{{Name: "Widget", SKU: "W-1", Category: &category}}})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestInventory(policy, existing)
+			err := tt.write(s, tt.category)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("error = %v, want a ValidationError", err)
+			}
+			if want := "Field 'Category' must be one of: Parts, Tools"; !strings.HasSuffix(validationErr.Message, want) {
+				t.Errorf("message = %q, want it to list the categories: %q", validationErr.Message, want)
+			}
+
+			s, _ = newTestInventory(policy, existing)
+			if err := tt.write(s, "Tools"); err != nil {
+				t.Errorf("writing a known category: %v", err)
+			}
+		})
+	}
+}
+
+func TestRetiredCategoryKept(t *testing.T) {
+	s, repo := newTestInventory(InventoryPolicy{Categories: []string{"Parts"}},
+		models.Item{ID: 1, Name: "Widget", SKU: "W-1", Unit: models.UnitEach, Category: "Retired"})
+	ctx := context.Background()
+
+	if _, err := s.UpdateItem(ctx, 1, 1, &models.UpdateItemRequest{Name: ptr("Widget 2")}, ""); err != nil {
+		t.Fatalf("updating an item in a retired category: %v", err)
+	}
+	if _, err := s.UpdateItem(ctx, 1, 1, &models.UpdateItemRequest{Category: ptr("Retired")}, ""); err != nil {
+		t.Fatalf("resending a retired category: %v", err)
+	}
+	if item := repo.get(1); item.Category != "Retired" || item.Name != "Widget 2" {
+		t.Errorf("item = %+v, want it renamed and still in Retired", item)
+	}
+}
+
+func TestAdjustStockMixedUnits(t *testing.T) {
+	s, repo := newTestInventory(InventoryPolicy{},
+		models.Item{ID: 1, Name: "Bolt", SKU: "BOLT", Quantity: 10, Unit: models.UnitEach},
+		models.Item{ID: 2, Name: "Flour", SKU: "FLOUR", Quantity: 2.5, Unit: models.UnitKg},
+	)
+	ctx := context.Background()
+
+	steps := []struct {
+		name         string
+		id           uint
+		req          models.AdjustStockRequest
+		wantErr      error
+		wantQuantity float64
+		wantDelta    float64 // of the recorded movement, when the step succeeds
+	}{
+		{"whole delta on each", 1, models.AdjustStockRequest{Delta: ptr(-3.0)}, nil, 7, -3},
+		{"fractional delta on each", 1, models.AdjustStockRequest{Delta: ptr(0.5)}, ErrValidation, 7, 0},
+		{"fractional set on each", 1, models.AdjustStockRequest{Set: ptr(4.25)}, ErrValidation, 7, 0},
+		{"whole set on each", 1, models.AdjustStockRequest{Set: ptr(12.0)}, nil, 12, 5},
+		{"decimal delta on kg", 2, models.AdjustStockRequest{Delta: ptr(0.125)}, nil, 2.625, 0.125},
+		{"decimal removal on kg", 2, models.AdjustStockRequest{Delta: ptr(-1.5)}, nil, 1.125, -1.5},
+		{"repeated tenths on kg", 2, models.AdjustStockRequest{Delta: ptr(0.1)}, nil, 1.225, 0.1},
+		{"decimal set on kg", 2, models.AdjustStockRequest{Set: ptr(0.3)}, nil, 0.3, -0.925},
+		{"below zero on kg", 2, models.AdjustStockRequest{Delta: ptr(-0.301)}, ErrInsufficientStock, 0.3, 0},
+		{"delta and set", 2, models.AdjustStockRequest{Delta: ptr(1.0), Set: ptr(1.0)}, ErrValidation, 0.3, 0},
+		{"neither delta nor set", 2, models.AdjustStockRequest{}, ErrValidation, 0.3, 0},
+	}
+	for _, step := range steps {
+		movements := len(repo.movements)
+		_, err := s.AdjustStock(ctx, step.id, 1, &step.req)
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: error = %v, want %v", step.name, err, step.wantErr)
+		}
+		if got := repo.get(step.id).Quantity; got != step.wantQuantity {
+			t.Errorf("%s: quantity = %v, want %v", step.name, got, step.wantQuantity)
+		}
+		if step.wantErr != nil {
+			if len(repo.movements) != movements {
+				t.Errorf("%s: a failed adjustment recorded a movement", step.name)
+			}
+			continue
+		}
+		if movement := repo.movements[len(repo.movements)-1]; movement.Delta != step.wantDelta || movement.QuantityAfter != step.wantQuantity {
+			t.Errorf("%s: movement delta %v, quantity after %v; want %v, %v", step.name, movement.Delta, movement.QuantityAfter, step.wantDelta, step.wantQuantity)
+		}
+	}
+}
+
+func TestCreateItemOnConflict(t *testing.T) {
+	existing := models.Item{ID: 1, Name: "Bolt", SKU: "BOLT", Quantity: 10, Unit: models.UnitEach, Price: 1, Category: "Parts"}
+	req := func() *models.CreateItemRequest {
+		return &models.CreateItemRequest{Name: "Bolt M8", SKU: "BOLT", Quantity: 25, Price: 2, Category: "Parts"}
+	}
+
+	tests := []struct {
+		onConflict  string
+		wantErr     error
+		wantItem    *models.Item // returned, and stored under ID 1
+		wantHistory int          // price changes recorded
+	}{
+		{"", ErrSKUExists, nil, 0},
+		{models.OnConflictError, ErrSKUExists, nil, 0},
+		{models.OnConflictIgnore, nil, &existing, 0},
+		{models.OnConflictUpdate, nil, &models.Item{ID: 1, Name: "Bolt M8", SKU: "BOLT", Quantity: 25, Unit: models.UnitEach, Price: 2, Category: "Parts"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run("on_conflict="+tt.onConflict, func(t *testing.T) {
+			s, repo := newTestInventory(InventoryPolicy{}, existing)
+			item, created, err := s.CreateItem(context.Background(), 1, req(), tt.onConflict)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if created {
+				t.Error("CreateItem reported a conflicting SKU as created")
+			}
+			if n := len(repo.find(func(*models.Item) bool { return true })); n != 1 {
+				t.Errorf("%d items stored, want 1", n)
+			}
+			stored := repo.get(1)
+			want := tt.wantItem
+			if want == nil {
+				want = &existing
+			} else if item == nil || item.ID != want.ID || item.Name != want.Name || item.Quantity != want.Quantity || item.Price != want.Price {
+				t.Errorf("returned item = %+v, want %+v", item, want)
+			}
+			if stored.Name != want.Name || stored.Quantity != want.Quantity || stored.Price != want.Price {
+				t.Errorf("stored item = %+v, want %+v", stored, want)
+			}
+			if len(repo.prices) != tt.wantHistory {
+				t.Errorf("%d price changes recorded, want %d", len(repo.prices), tt.wantHistory)
+			}
+		})
+	}
+}
+
+func TestCreateItemOnConflictNewSKU(t *testing.T) {
+	for _, onConflict := range []string{models.OnConflictError, models.OnConflictIgnore, models.OnConflictUpdate} {
+		s, _ := newTestInventory(InventoryPolicy{}, models.Item{ID: 1, Name: "Bolt", SKU: "BOLT", Unit: models.UnitEach})
+		item, created, err := s.CreateItem(context.Background(), 1, &models.CreateItemRequest{Name: "Nut", SKU: "NUT", Quantity: 5}, onConflict)
+		if err != nil || !created || item.ID == 1 {
+			t.Errorf("on_conflict=%s: CreateItem = %+v, %v, %v; want a new item created", onConflict, item, created, err)
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	stock := []models.Item{
+		{ID: 1, Name: "Bolt", SKU: "BOLT", Quantity: 10, Unit: models.UnitEach},
+		{ID: 2, Name: "Nut", SKU: "NUT", Quantity: 5, Unit: models.UnitEach},
+		{ID: 3, Name: "Flour", SKU: "FLOUR", Quantity: 1.5, Unit: models.UnitKg},
+	}
+	count := func(sku string, quantity float64) models.ReconcileCount {
+		return models.ReconcileCount{SKU: sku, CountedQuantity: ptr(quantity)}
+	}
+
+	tests := []struct {
+		name           string
+		counts         []models.ReconcileCount
+		wantErr        error
+		wantQuantities []float64 // of the stock afterwards, by ID
+		wantDeltas     []float64 // of the recorded movements
+	}{
+		{"corrections", []models.ReconcileCount{count("bolt", 7), count("NUT", 5), count("FLOUR", 1.75)},
+			nil, []float64{7, 5, 1.75}, []float64{-3, 0, 0.25}},
+		{"duplicate SKUs", []models.ReconcileCount{count("BOLT", 7), count("bolt", 8)},
+			ErrValidation, []float64{10, 5, 1.5}, nil},
+		{"unknown SKU", []models.ReconcileCount{count("BOLT", 7), count("WASHER", 3)},
+			ErrItemNotFound, []float64{10, 5, 1.5}, nil},
+		{"fractional count of an each item", []models.ReconcileCount{count("FLOUR", 2), count("NUT", 4.5)},
+			ErrValidation, []float64{10, 5, 1.5}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestInventory(InventoryPolicy{SKUCase: SKUCaseUpper}, stock...)
+			result, err := s.Reconcile(context.Background(), 1, &models.ReconcileRequest{Items: tt.counts, Reason: "stock take"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			for i, want := range tt.wantQuantities {
+				if got := repo.get(uint(i + 1)).Quantity; got != want {
+					t.Errorf("item %d quantity = %v, want %v", i+1, got, want)
+				}
+			}
+			if len(repo.movements) != len(tt.wantDeltas) {
+				t.Fatalf("%d movements recorded, want %d", len(repo.movements), len(tt.wantDeltas))
+			}
+			for i, movement := range repo.movements {
+				if movement.Type != models.MovementTypeReconciliation || movement.Delta != tt.wantDeltas[i] {
+					t.Errorf("movement %d = %s %v, want %s %v", i, movement.Type, movement.Delta, models.MovementTypeReconciliation, tt.wantDeltas[i])
+				}
+			}
+			if err != nil {
+				return
+			}
+			if result.Adjusted != 2 || result.Unchanged != 1 {
+				t.Errorf("adjusted %d, unchanged %d; want 2, 1", result.Adjusted, result.Unchanged)
+			}
+			if first := result.Adjustments[0]; first.SKU != "BOLT" || first.PreviousQuantity != 10 || first.CountedQuantity != 7 {
+				t.Errorf("first adjustment = %+v, want BOLT from 10 to 7", first)
+			}
+		})
+	}
+}
+
+// hardDeletes removes items outright on Delete, as with DB_DELETE_MODE=hard
+type hardDeletes struct {
+	*memItems
+}
+
+func (m hardDeletes) Delete(_ context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, id)
+	return nil
+}
+
+func TestMergeItems(t *testing.T) {
+	stock := []models.Item{
+		{ID: 1, Name: "Bolt", SKU: "BOLT", Quantity: 10, Unit: models.UnitEach},
+		{ID: 2, Name: "Bolt (dup)", SKU: "BOLT-2", Quantity: -2, Unit: models.UnitEach},
+		{ID: 3, Name: "Flour", SKU: "FLOUR", Quantity: 1.5, Unit: models.UnitKg},
+	}
+
+	tests := []struct {
+		name         string
+		source       uint
+		target       uint
+		wantErr      error
+		wantQuantity float64 // of the target afterwards
+	}{
+		{"into the lower ID", 2, 1, nil, 8},
+		{"into the higher ID", 1, 2, nil, 8},
+		{"different units", 3, 1, ErrValidation, 10},
+		{"missing source", 9, 1, ErrItemNotFound, 10},
+		{"missing target", 1, 9, ErrItemNotFound, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemItems(stock...)
+			s := NewInventoryService(hardDeletes{repo}, InventoryPolicy{})
+			target, err := s.MergeItems(context.Background(), 1, &models.MergeItemsRequest{SourceID: tt.source, TargetID: tt.target})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if len(repo.merged) != 0 || len(repo.movements) != 0 || len(repo.deleted) != 0 {
+					t.Errorf("failed merge left references %v, movements %v, deletions %v", repo.merged, repo.movements, repo.deleted)
+				}
+				return
+			}
+
+			if target.ID != tt.target || target.Quantity != tt.wantQuantity {
+				t.Errorf("target = item %d with %v, want item %d with %v", target.ID, target.Quantity, tt.target, tt.wantQuantity)
+			}
+			if !repo.deleted[tt.source] || repo.deleted[tt.target] {
+				t.Errorf("deleted = %v, want only the source %d", repo.deleted, tt.source)
+			}
+			if repo.get(tt.source) == nil {
+				t.Errorf("source %d was removed, want it kept soft-deleted", tt.source)
+			}
+			if repo.merged[tt.source] != tt.target {
+				t.Errorf("references of %d moved to %d, want %d", tt.source, repo.merged[tt.source], tt.target)
+			}
+			if len(repo.movements) != 1 || repo.movements[0].Type != models.MovementTypeMerge || repo.movements[0].ItemID != tt.target {
+				t.Errorf("movements = %+v, want one merge onto %d", repo.movements, tt.target)
+			}
+		})
+	}
+}
+
+func TestCreateItemConcurrentConflict(t *testing.T) {
+	tests := []struct {
+		onConflict string
+		wantErr    error
+		wantName   string // of the item returned and stored
+	}{
+		{models.OnConflictError, ErrSKUExists, "Bolt"},
+		{models.OnConflictIgnore, nil, "Bolt"},
+		{models.OnConflictUpdate, nil, "Bolt M8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.onConflict, func(t *testing.T) {
+			repo := &lateItems{memItems: newMemItems(models.Item{ID: 1, Name: "Bolt", SKU: "BOLT", Quantity: 10, Unit: models.UnitEach}), misses: 1}
+			s := NewInventoryService(repo, InventoryPolicy{})
+
+			item, created, err := s.CreateItem(context.Background(), 1, &models.CreateItemRequest{Name: "Bolt M8", SKU: "BOLT", Quantity: 10}, tt.onConflict)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if created {
+				t.Error("CreateItem reported an item another request created as created")
+			}
+			if err == nil && (item.ID != 1 || item.Name != tt.wantName) {
+				t.Errorf("returned item = %+v, want item 1 named %q", item, tt.wantName)
+			}
+			if n := len(repo.find(func(*models.Item) bool { return true })); n != 1 {
+				t.Errorf("%d items stored, want 1", n)
+			}
+			if name := repo.get(1).Name; name != tt.wantName {
+				t.Errorf("stored name = %q, want %q", name, tt.wantName)
+			}
+		})
+	}
+}
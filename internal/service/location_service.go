@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// ErrLocationNotFound is returned when a location does not exist
+var ErrLocationNotFound = errors.New("location not found")
+
+// LocationService handles location business logic
+type LocationService interface {
+	GetStock(ctx context.Context, locationID uint, query *models.LocationStockQuery) ([]models.LocationStock, error)
+}
+
+type locationService struct {
+	repo repository.LocationRepository
+}
+
+// NewLocationService creates a new location service
+func NewLocationService(repo repository.LocationRepository) LocationService {
+	return &locationService{repo: repo}
+}
+
+// GetStock retrieves a page of the stock levels held at a location, in item ID order
+func (s *locationService) GetStock(ctx context.Context, locationID uint, query *models.LocationStockQuery) ([]models.LocationStock, error) {
+	location, err := s.repo.FindByID(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, &NotFoundError{Resource: "location", Key: "id", Value: locationID, err: ErrLocationNotFound}
+	}
+	return s.repo.FindStock(ctx, locationID, repository.ListOptions{
+		Category: query.Category,
+		Search:   query.Search,
+		Offset:   query.Offset(),
+		Limit:    query.PageSize,
+	})
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// PreferencesService handles user preference business logic
+type PreferencesService interface {
+	GetPreferences(ctx context.Context, userID uint) (*models.UserPreferences, error)
+	UpdatePreferences(ctx context.Context, userID uint, req *models.UpdatePreferencesRequest) (*models.UserPreferences, error)
+}
+
+type preferencesService struct {
+	repo repository.PreferencesRepository
+}
+
+// NewPreferencesService creates a new preferences service
+func NewPreferencesService(repo repository.PreferencesRepository) PreferencesService {
+	return &preferencesService{repo: repo}
+}
+
+// GetPreferences retrieves a user's preferences. A user who has never set any gets
+// empty preferences, so the system defaults apply.
+func (s *preferencesService) GetPreferences(ctx context.Context, userID uint) (*models.UserPreferences, error) {
+	prefs, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		prefs = &models.UserPreferences{UserID: userID}
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences changes the preferences present in the request and keeps the rest
+func (s *preferencesService) UpdatePreferences(ctx context.Context, userID uint, req *models.UpdatePreferencesRequest) (*models.UserPreferences, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PageSize != nil {
+		prefs.PageSize = *req.PageSize
+	}
+	if req.Currency != nil {
+		prefs.Currency = *req.Currency
+	}
+	if req.DefaultCategory != nil {
+		prefs.DefaultCategory = *req.DefaultCategory
+	}
+
+	if err := s.repo.Save(ctx, prefs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
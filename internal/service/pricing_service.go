@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// PricingService handles scheduled price changes and price history
+type PricingService interface {
+	SchedulePrice(ctx context.Context, itemID, userID uint, req *models.SchedulePriceRequest) (*models.ScheduledPrice, error)
+	GetPendingPrices(ctx context.Context, itemID uint) ([]models.ScheduledPrice, error)
+	CancelScheduledPrice(ctx context.Context, id uint) error
+	ApplyDuePrices(ctx context.Context) (int, error)
+	GetPriceHistory(ctx context.Context, itemID uint) ([]models.PriceHistory, error)
+}
+
+type pricingService struct {
+	priceRepo     repository.PriceRepository
+	inventoryRepo repository.InventoryRepository
+}
+
+// NewPricingService creates a new pricing service
+func NewPricingService(priceRepo repository.PriceRepository, inventoryRepo repository.InventoryRepository) PricingService {
+	return &pricingService{
+		priceRepo:     priceRepo,
+		inventoryRepo: inventoryRepo,
+	}
+}
+
+// SchedulePrice schedules a price change for an item at a future time
+func (s *pricingService) SchedulePrice(ctx context.Context, itemID, userID uint, req *models.SchedulePriceRequest) (*models.ScheduledPrice, error) {
+	if !req.EffectiveAt.After(time.Now()) {
+		return nil, &ValidationError{Message: "Field 'EffectiveAt' must be in the future"}
+	}
+
+	if err := s.ensureItemExists(ctx, itemID); err != nil {
+		return nil, err
+	}
+
+	scheduled := &models.ScheduledPrice{
+		ItemID:      itemID,
+		Price:       *req.Price,
+		EffectiveAt: req.EffectiveAt.UTC(),
+		Status:      models.ScheduledPricePending,
+		UserID:      userID,
+	}
+	if err := s.priceRepo.CreateScheduled(ctx, scheduled); err != nil {
+		return nil, err
+	}
+
+	return scheduled, nil
+}
+
+// GetPendingPrices retrieves an item's pending price changes
+func (s *pricingService) GetPendingPrices(ctx context.Context, itemID uint) ([]models.ScheduledPrice, error) {
+	if err := s.ensureItemExists(ctx, itemID); err != nil {
+		return nil, err
+	}
+	return s.priceRepo.FindPendingByItem(ctx, itemID)
+}
+
+// CancelScheduledPrice cancels a pending price change
+func (s *pricingService) CancelScheduledPrice(ctx context.Context, id uint) error {
+	scheduled, err := s.priceRepo.FindScheduledByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if scheduled == nil {
+		return &NotFoundError{Resource: "scheduled_price", Key: "id", Value: id, err: ErrScheduledPriceNotFound}
+	}
+
+	cancelled, err := s.priceRepo.CancelScheduled(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return &ValidationError{Message: "only pending price changes can be cancelled"}
+	}
+	return nil
+}
+
+// ApplyDuePrices applies every pending price change that has become effective
+func (s *pricingService) ApplyDuePrices(ctx context.Context) (int, error) {
+	return s.priceRepo.ApplyDue(ctx, time.Now().UTC())
+}
+
+// GetPriceHistory retrieves an item's price history
+func (s *pricingService) GetPriceHistory(ctx context.Context, itemID uint) ([]models.PriceHistory, error) {
+	if err := s.ensureItemExists(ctx, itemID); err != nil {
+		return nil, err
+	}
+	return s.priceRepo.FindHistoryByItem(ctx, itemID)
+}
+
+// ensureItemExists returns a not-found error if the item does not exist
+func (s *pricingService) ensureItemExists(ctx context.Context, itemID uint) error {
+	item, err := s.inventoryRepo.FindByID(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return itemNotFound(itemID)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// Purchase order errors
+var (
+	ErrPurchaseOrderNotFound = errors.New("purchase order not found")
+
+	// ErrOverReceipt is returned when a receipt would take a line past its ordered quantity
+	ErrOverReceipt = errors.New("received quantity exceeds the quantity ordered")
+)
+
+// OverReceiptError reports a receipt line larger than what remains to be received
+type OverReceiptError struct {
+	SKU       string
+	Quantity  float64
+	Remaining float64
+}
+
+// Error implements the error interface
+func (e *OverReceiptError) Error() string {
+	return fmt.Sprintf("receiving %g of SKU '%s' exceeds the %g remaining on the order", e.Quantity, e.SKU, e.Remaining)
+}
+
+// Unwrap allows errors.Is(err, ErrOverReceipt)
+func (e *OverReceiptError) Unwrap() error {
+	return ErrOverReceipt
+}
+
+// Details returns the SKU and quantities for the error response
+func (e *OverReceiptError) Details() interface{} {
+	return map[string]interface{}{"sku": e.SKU, "quantity": e.Quantity, "remaining_quantity": e.Remaining}
+}
+
+// PurchaseOrderService handles purchase order business logic
+type PurchaseOrderService interface {
+	CreatePurchaseOrder(ctx context.Context, userID uint, req *models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error)
+	GetPurchaseOrder(ctx context.Context, id uint) (*models.PurchaseOrder, error)
+	Receive(ctx context.Context, id, userID uint, req *models.ReceivePurchaseOrderRequest) (*models.PurchaseOrder, error)
+}
+
+type purchaseOrderService struct {
+	repo          repository.PurchaseOrderRepository
+	inventoryRepo repository.InventoryRepository
+
+	// skuCase normalizes SKUs like the inventory service does (see SKUCaseUpper and SKUCaseLower)
+	skuCase string
+	// allowOverReceipt accepts receipts beyond the quantity ordered
+	allowOverReceipt bool
+}
+
+// NewPurchaseOrderService creates a new purchase order service
+func NewPurchaseOrderService(repo repository.PurchaseOrderRepository, inventoryRepo repository.InventoryRepository, skuCase string, allowOverReceipt bool) PurchaseOrderService {
+	return &purchaseOrderService{repo: repo, inventoryRepo: inventoryRepo, skuCase: skuCase, allowOverReceipt: allowOverReceipt}
+}
+
+// CreatePurchaseOrder places an order for existing items. Every SKU must belong to an
+// active item and appear only once.
+func (s *purchaseOrderService) CreatePurchaseOrder(ctx context.Context, userID uint, req *models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	skus := make([]string, len(req.Lines))
+	for i := range req.Lines {
+		skus[i] = applySKUCase(s.skuCase, req.Lines[i].SKU)
+	}
+	if duplicates := duplicateSKUs(skus); len(duplicates) > 0 {
+		return nil, &DuplicateSKUsError{SKUs: duplicates}
+	}
+
+	items, err := s.inventoryRepo.FindBySKUs(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+	bySKU := make(map[string]*models.Item, len(items))
+	for i := range items {
+		bySKU[items[i].SKU] = &items[i]
+	}
+
+	po := &models.PurchaseOrder{Supplier: req.Supplier, CreatedBy: userID}
+	for i, line := range req.Lines {
+		item, ok := bySKU[skus[i]]
+		if !ok {
+			return nil, &NotFoundError{Resource: "item", Key: "sku", Value: skus[i], err: ErrItemNotFound}
+		}
+		if !models.ValidQuantity(item.Unit, line.OrderedQuantity) {
+			return nil, &ValidationError{Message: fmt.Sprintf("lines[%d]: Field 'OrderedQuantity' must be a whole number for unit '%s'", i, item.Unit)}
+		}
+		po.Lines = append(po.Lines, models.PurchaseOrderLine{
+			ItemID:          item.ID,
+			SKU:             item.SKU,
+			OrderedQuantity: models.RoundQuantity(line.OrderedQuantity),
+		})
+	}
+	po.Refresh()
+
+	if err := s.repo.Create(ctx, po); err != nil {
+		return nil, err
+	}
+	return po, nil
+}
+
+// GetPurchaseOrder retrieves a purchase order with what remains to be received on each line
+func (s *purchaseOrderService) GetPurchaseOrder(ctx context.Context, id uint) (*models.PurchaseOrder, error) {
+	po, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if po == nil {
+		return nil, purchaseOrderNotFound(id)
+	}
+	po.Refresh()
+	return po, nil
+}
+
+// Receive books the quantities that arrived against a purchase order. Each line's stock is
+// incremented with a receipt movement referencing the order, and the order tracks what is
+// still to come. Either every line is received or, on any error, none is.
+func (s *purchaseOrderService) Receive(ctx context.Context, id, userID uint, req *models.ReceivePurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	skus := make([]string, len(req.Lines))
+	for i := range req.Lines {
+		skus[i] = applySKUCase(s.skuCase, req.Lines[i].SKU)
+	}
+	if duplicates := duplicateSKUs(skus); len(duplicates) > 0 {
+		return nil, &DuplicateSKUsError{SKUs: duplicates}
+	}
+
+	var po *models.PurchaseOrder
+	err := s.inventoryRepo.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		po, err = s.repo.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if po == nil {
+			return purchaseOrderNotFound(id)
+		}
+		po.Refresh()
+
+		lines := make(map[string]*models.PurchaseOrderLine, len(po.Lines))
+		for i := range po.Lines {
+			lines[po.Lines[i].SKU] = &po.Lines[i]
+		}
+
+		for i, receipt := range req.Lines {
+			line, ok := lines[skus[i]]
+			if !ok {
+				return &ValidationError{Message: fmt.Sprintf("lines[%d]: SKU '%s' is not on purchase order %d", i, skus[i], po.ID)}
+			}
+			quantity := models.RoundQuantity(receipt.Quantity)
+			if quantity > line.RemainingQuantity && !s.allowOverReceipt {
+				return &OverReceiptError{SKU: line.SKU, Quantity: quantity, Remaining: line.RemainingQuantity}
+			}
+
+			movement := &models.StockMovement{
+				Type:            models.MovementTypeReceipt,
+				Delta:           quantity,
+				Reason:          req.Reason,
+				UserID:          userID,
+				PurchaseOrderID: &po.ID,
+			}
+			item, err := s.inventoryRepo.AdjustQuantity(ctx, line.ItemID, movement, false)
+			if errors.Is(err, repository.ErrFractionalQuantity) {
+				return &ValidationError{Message: fmt.Sprintf("lines[%d]: Field 'Quantity' must be a whole number for unit '%s'", i, models.UnitEach)}
+			}
+			if err != nil {
+				return itemConflictError(err)
+			}
+			if item == nil {
+				return &NotFoundError{Resource: "item", Key: "sku", Value: line.SKU, err: ErrItemNotFound}
+			}
+			line.ReceivedQuantity = models.RoundQuantity(line.ReceivedQuantity + quantity)
+		}
+
+		po.Refresh()
+		return s.repo.UpdateReceived(ctx, po)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return po, nil
+}
+
+// purchaseOrderNotFound returns the error for a missing purchase order with the given ID
+func purchaseOrderNotFound(id uint) error {
+	return &NotFoundError{Resource: "purchase_order", Key: "id", Value: id, err: ErrPurchaseOrderNotFound}
+}
+
+// duplicateSKUs returns the SKUs that appear more than once, in order of their repeat
+func duplicateSKUs(skus []string) []string {
+	seen := make(map[string]bool, len(skus))
+	var duplicates []string
+	for _, sku := range skus {
+		if seen[sku] {
+			duplicates = append(duplicates, sku)
+		}
+		seen[sku] = true
+	}
+	return duplicates
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// RetentionOptions configures how long history is kept
+type RetentionOptions struct {
+	// Periods maps a table (see repository.RetentionStockMovements and
+	// repository.RetentionPriceHistory) to how long its rows are kept.
+	// Tables that are missing or mapped to zero are kept forever.
+	Periods map[string]time.Duration
+
+	// BatchSize is how many rows each delete statement removes
+	BatchSize int
+}
+
+// RetentionService deletes history older than its retention period
+type RetentionService interface {
+	Purge(ctx context.Context) (map[string]int64, error)
+}
+
+type retentionService struct {
+	repo repository.RetentionRepository
+	opts RetentionOptions
+}
+
+// NewRetentionService creates a new retention service
+func NewRetentionService(repo repository.RetentionRepository, opts RetentionOptions) RetentionService {
+	return &retentionService{repo: repo, opts: opts}
+}
+
+// Purge deletes the rows of every table with a retention period that are older than it,
+// in batches, and returns how many rows it deleted per table. It stops between batches
+// when ctx is cancelled; the counts then cover what was deleted so far.
+func (s *retentionService) Purge(ctx context.Context) (map[string]int64, error) {
+	tables := make([]string, 0, len(s.opts.Periods))
+	for table, period := range s.opts.Periods {
+		if period > 0 {
+			tables = append(tables, table)
+		}
+	}
+	sort.Strings(tables)
+
+	purged := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		cutoff := time.Now().Add(-s.opts.Periods[table])
+		for {
+			if err := ctx.Err(); err != nil {
+				return purged, err
+			}
+			n, err := s.repo.PurgeBatch(ctx, table, cutoff, s.opts.BatchSize)
+			purged[table] += n
+			if err != nil {
+				return purged, err
+			}
+			if n < int64(s.opts.BatchSize) {
+				break
+			}
+		}
+	}
+	return purged, nil
+}
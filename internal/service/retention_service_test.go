@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// memHistory is an in-memory RetentionRepository holding the creation times of the rows
+// of each table. It records the size of every batch it deletes.
+type memHistory struct {
+	rows    map[string][]time.Time
+	batches map[string][]int64
+	err     error
+	onBatch func() // called after each batch, to cancel a purge part way
+}
+
+func (m *memHistory) PurgeBatch(_ context.Context, table string, before time.Time, limit int) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	rows := m.rows[table]
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Before(rows[j]) })
+	var deleted int64
+	kept := rows[:0]
+	for _, created := range rows {
+		if created.Before(before) && deleted < int64(limit) {
+			deleted++
+			continue
+		}
+		kept = append(kept, created)
+	}
+	m.rows[table] = kept
+	m.batches[table] = append(m.batches[table], deleted)
+	if m.onBatch != nil {
+		m.onBatch()
+	}
+	return deleted, nil
+}
+
+// history returns count rows created age ago, spread a minute apart
+func history(age time.Duration, count int) []time.Time {
+	rows := make([]time.Time, count)
+	for i := range rows {
+		rows[i] = time.Now().Add(-age - time.Duration(i)*time.Minute)
+	}
+	return rows
+}
+
+func TestRetentionPurge(t *testing.T) {
+	const day = 24 * time.Hour
+	repo := &memHistory{
+		rows: map[string][]time.Time{
+			repository.RetentionStockMovements: append(history(400*day, 7), history(day, 3)...),
+			repository.RetentionPriceHistory:   append(history(40*day, 2), history(10*day, 4)...),
+		},
+		batches: make(map[string][]int64),
+	}
+	s := NewRetentionService(repo, RetentionOptions{
+		Periods: map[string]time.Duration{
+			repository.RetentionStockMovements: 365 * day,
+			repository.RetentionPriceHistory:   30 * day,
+		},
+		BatchSize: 3,
+	})
+
+	purged, err := s.Purge(context.Background())
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	want := map[string]int64{repository.RetentionStockMovements: 7, repository.RetentionPriceHistory: 2}
+	if !reflect.DeepEqual(purged, want) {
+		t.Errorf("purged %v, want %v", purged, want)
+	}
+	if kept := len(repo.rows[repository.RetentionStockMovements]); kept != 3 {
+		t.Errorf("%d stock movements kept, want the 3 recent ones", kept)
+	}
+	if kept := len(repo.rows[repository.RetentionPriceHistory]); kept != 4 {
+		t.Errorf("%d price changes kept, want the 4 recent ones", kept)
+	}
+	// Batches continue while full and stop at the first short one
+	if got, want := repo.batches[repository.RetentionStockMovements], []int64{3, 3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stock movement batches %v, want %v", got, want)
+	}
+	if got, want := repo.batches[repository.RetentionPriceHistory], []int64{2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("price history batches %v, want %v", got, want)
+	}
+}
+
+func TestRetentionKeepsTablesWithoutPeriod(t *testing.T) {
+	repo := &memHistory{
+		rows:    map[string][]time.Time{repository.RetentionPriceHistory: history(1000*24*time.Hour, 5)},
+		batches: make(map[string][]int64),
+	}
+	s := NewRetentionService(repo, RetentionOptions{
+		Periods:   map[string]time.Duration{repository.RetentionPriceHistory: 0},
+		BatchSize: 10,
+	})
+
+	purged, err := s.Purge(context.Background())
+	if err != nil || len(purged) != 0 {
+		t.Errorf("Purge = %v, %v; want nothing purged", purged, err)
+	}
+	if len(repo.batches) != 0 {
+		t.Errorf("deleted batches %v from a table kept forever", repo.batches)
+	}
+}
+
+func TestRetentionPurgeCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repo := &memHistory{
+		rows:    map[string][]time.Time{repository.RetentionStockMovements: history(48*time.Hour, 10)},
+		batches: make(map[string][]int64),
+		onBatch: cancel,
+	}
+	s := NewRetentionService(repo, RetentionOptions{
+		Periods:   map[string]time.Duration{repository.RetentionStockMovements: time.Hour},
+		BatchSize: 4,
+	})
+
+	purged, err := s.Purge(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want %v", err, context.Canceled)
+	}
+	if purged[repository.RetentionStockMovements] != 4 {
+		t.Errorf("purged %v, want the one batch of 4 deleted before cancellation", purged)
+	}
+}
+
+func TestRetentionPurgeError(t *testing.T) {
+	failure := errors.New("lock timeout")
+	s := NewRetentionService(&memHistory{err: failure, rows: map[string][]time.Time{}, batches: map[string][]int64{}}, RetentionOptions{
+		Periods:   map[string]time.Duration{repository.RetentionStockMovements: time.Hour},
+		BatchSize: 4,
+	})
+	if _, err := s.Purge(context.Background()); !errors.Is(err, failure) {
+		t.Errorf("error = %v, want %v", err, failure)
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+	"github.com/nielwyn/inventory-system/internal/storage"
+)
+
+// selfTestPrefix namespaces everything the self-test writes, so stray rows or objects are recognizable
+const selfTestPrefix = "__selftest__"
+
+// errSelfTestRollback rolls back the database check's transaction once the row has been read back
+var errSelfTestRollback = errors.New("self-test rollback")
+
+// errSelfTestSkipped marks a check whose dependency is not configured
+var errSelfTestSkipped = errors.New("not configured")
+
+// SelfTestService actively exercises the service's dependencies
+type SelfTestService interface {
+	Run(ctx context.Context) *models.SelfTestReport
+}
+
+type selfTestService struct {
+	repo  repository.InventoryRepository
+	store storage.Storage
+}
+
+// NewSelfTestService creates a new self-test service. store may be nil when no
+// object storage is configured, in which case its check is skipped.
+func NewSelfTestService(repo repository.InventoryRepository, store storage.Storage) SelfTestService {
+	return &selfTestService{repo: repo, store: store}
+}
+
+// Run runs every check in turn and reports each outcome
+func (s *selfTestService) Run(ctx context.Context) *models.SelfTestReport {
+	report := &models.SelfTestReport{Healthy: true}
+	checks := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{"database", s.checkDatabase},
+		{"storage", s.checkStorage},
+	}
+	for _, check := range checks {
+		result := models.SelfTestCheck{Name: check.name, Status: models.SelfTestPass}
+		start := time.Now()
+		switch err := check.run(ctx); {
+		case errors.Is(err, errSelfTestSkipped):
+			result.Status = models.SelfTestSkipped
+		case err != nil:
+			result.Status = models.SelfTestFail
+			result.Error = err.Error()
+			report.Healthy = false
+		}
+		result.DurationMs = time.Since(start).Milliseconds()
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+// checkDatabase writes a throwaway item and reads it back inside a transaction that is
+// always rolled back, so nothing is left behind
+func (s *selfTestService) checkDatabase(ctx context.Context) error {
+	sku := fmt.Sprintf("%s-%d", selfTestPrefix, time.Now().UnixNano())
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, &models.Item{Name: selfTestPrefix, SKU: sku, Unit: models.UnitEach}); err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+		item, err := s.repo.FindBySKU(ctx, sku)
+		if err != nil {
+			return fmt.Errorf("read: %w", err)
+		}
+		if item == nil {
+			return errors.New("read: written row not found")
+		}
+		return errSelfTestRollback
+	})
+	if errors.Is(err, errSelfTestRollback) {
+		return nil
+	}
+	return err
+}
+
+// checkStorage writes a throwaway object, finds it in a listing and deletes it
+func (s *selfTestService) checkStorage(ctx context.Context) error {
+	if s.store == nil {
+		return errSelfTestSkipped
+	}
+	prefix := selfTestPrefix + "/"
+	key := fmt.Sprintf("%s%d.txt", prefix, time.Now().UnixNano())
+	if err := s.store.Put(ctx, key, bytes.NewBufferString("self-test")); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	keys, err := s.store.List(ctx, prefix)
+	if err == nil && !containsKey(keys, key) {
+		err = errors.New("written object not listed")
+	}
+	if err != nil {
+		s.store.Delete(ctx, key)
+		return fmt.Errorf("list: %w", err)
+	}
+	if err := s.store.Delete(ctx, key); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	return nil
+}
+
+// containsKey reports whether key is in keys
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+// Session errors
+var (
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrSessionRevoked is returned when a token belongs to a session that was revoked
+	ErrSessionRevoked = errors.New("session has been revoked")
+)
+
+// sessionTouchInterval is how stale a session's last use may be before a request records it again
+const sessionTouchInterval = time.Minute
+
+// startSession records a session for user, ending when its token expires
+func (s *authService) startSession(ctx context.Context, user *models.User, client models.SessionClient, expiresAt time.Time) (*models.Session, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("generate session ID: %w", err)
+	}
+	now := time.Now()
+	session := &models.Session{
+		ID:         hex.EncodeToString(id),
+		UserID:     user.ID,
+		UserAgent:  truncate(client.UserAgent, 255),
+		IPAddress:  client.IPAddress,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  expiresAt,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// CheckSession verifies that the session of token, which belongs to userID, has not been
+// revoked, and records its use. It returns the session ID, or "" for tokens issued
+// before sessions were recorded, which carry none.
+func (s *authService) CheckSession(ctx context.Context, token *jwt.Token, userID uint) (string, error) {
+	id, err := s.activeSession(ctx, token, userID)
+	if err != nil || id == "" {
+		return id, err
+	}
+	if err := s.sessionRepo.Touch(ctx, id, time.Now(), sessionTouchInterval); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// activeSession is CheckSession without recording the use
+func (s *authService) activeSession(ctx context.Context, token *jwt.Token, userID uint) (string, error) {
+	id, ok := StringClaim(token, "sid")
+	if !ok {
+		return "", nil
+	}
+	session, err := s.sessionRepo.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if session == nil || session.UserID != userID || session.RevokedAt != nil {
+		return "", ErrSessionRevoked
+	}
+	return id, nil
+}
+
+// ListSessions lists a user's active sessions, marking currentID as the current one
+func (s *authService) ListSessions(ctx context.Context, userID uint, currentID string) ([]models.Session, error) {
+	sessions, err := s.sessionRepo.ListActive(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes one of a user's active sessions; its token is rejected from then on
+func (s *authService) RevokeSession(ctx context.Context, userID uint, id string) error {
+	revoked, err := s.sessionRepo.Revoke(ctx, userID, id, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return &NotFoundError{Resource: "session", Key: "id", Value: id, err: ErrSessionNotFound}
+	}
+	return nil
+}
+
+// truncate shortens s to at most n bytes, dropping a character cut in half
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return strings.ToValidUTF8(s[:n], "")
+	}
+	return s
+}
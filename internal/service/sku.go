@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// SKU case normalization modes. SKUCasePreserve stores SKUs exactly as sent.
+const (
+	SKUCasePreserve = ""
+	SKUCaseUpper    = "upper"
+	SKUCaseLower    = "lower"
+)
+
+// Defaults for generated SKUs
+const (
+	DefaultSKUFormat     = "{prefix}-{seq}"
+	defaultSKUPrefix     = "ITM"
+	skuPrefixLength      = 3
+	defaultSKUSeqPadding = 6
+)
+
+// generateSKU builds a SKU for an item created without one.
+// The sequence value comes from a database sequence, so SKUs stay unique across
+// concurrent requests and replicas. The format supports the {prefix} placeholder,
+// derived from the category, and {seq}, the zero-padded sequence value.
+func (s *inventoryService) generateSKU(ctx context.Context, category string) (string, error) {
+	seq, err := s.repo.NextSKUSequence(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate SKU: %w", err)
+	}
+
+	format := s.policy.SKUFormat
+	if format == "" {
+		format = DefaultSKUFormat
+	}
+
+	replacer := strings.NewReplacer(
+		"{prefix}", skuPrefix(category),
+		"{seq}", fmt.Sprintf("%0*d", defaultSKUSeqPadding, seq),
+	)
+	return replacer.Replace(format), nil
+}
+
+// skuPrefix derives an uppercase prefix from the first letters and digits of a category
+func skuPrefix(category string) string {
+	var b strings.Builder
+	for _, r := range category {
+		if b.Len() == skuPrefixLength {
+			break
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	if b.Len() == 0 {
+		return defaultSKUPrefix
+	}
+	return b.String()
+}
+
+// ErrSKUGeneration is returned when every generated SKU tried for a new item was taken
+var ErrSKUGeneration = errors.New("could not generate an unused SKU")
+
+// createWithGeneratedSKU creates an item under a generated SKU. Sequence values are never
+// handed out twice, but the SKU built from one can still be taken, by an item whose SKU was
+// chosen by hand or generated under another format. The insert then fails on the unique
+// index and is retried with the next sequence value, up to SKUGenerationAttempts times.
+func (s *inventoryService) createWithGeneratedSKU(ctx context.Context, req *models.CreateItemRequest) (*models.Item, error) {
+	attempts := max(s.policy.SKUGenerationAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		sku, err := s.generateSKU(ctx, req.Category)
+		if err != nil {
+			return nil, err
+		}
+		req.SKU = s.normalizeSKU(sku)
+
+		// Each attempt runs in a savepoint, so a failed insert does not abort the
+		// request's transaction
+		item := newItem(req)
+		err = s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+			return s.repo.Create(ctx, item)
+		})
+		if err == nil {
+			return item, nil
+		}
+		if !errors.Is(err, repository.ErrDuplicateKey) {
+			return nil, itemConflictError(err)
+		}
+		if attempt == attempts {
+			return nil, fmt.Errorf("%w after %d attempts; the last was %s", ErrSKUGeneration, attempts, req.SKU)
+		}
+	}
+}
+
+// resolveSKU fills in a generated SKU when the request has none and applies the
+// configured SKU case
+func (s *inventoryService) resolveSKU(ctx context.Context, req *models.CreateItemRequest) error {
+	if req.SKU != "" {
+		req.SKU = s.normalizeSKU(req.SKU)
+		return nil
+	}
+	if !s.policy.AutoGenerateSKU {
+		return &ValidationError{Message: "Field 'SKU' is required"}
+	}
+
+	sku, err := s.generateSKU(ctx, req.Category)
+	if err != nil {
+		return err
+	}
+	req.SKU = s.normalizeSKU(sku)
+	return nil
+}
+
+// normalizeSKU converts sku to the configured case. It is applied to every SKU
+// before it is looked up or stored, so SKUs differing only in case resolve to one item.
+func (s *inventoryService) normalizeSKU(sku string) string {
+	return applySKUCase(s.policy.SKUCase, sku)
+}
+
+// applySKUCase converts sku to skuCase (SKUCaseUpper or SKUCaseLower); any other
+// value leaves it unchanged
+func applySKUCase(skuCase, sku string) string {
+	switch skuCase {
+	case SKUCaseUpper:
+		return strings.ToUpper(sku)
+	case SKUCaseLower:
+		return strings.ToLower(sku)
+	default:
+		return sku
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+func TestApplySKUCase(t *testing.T) {
+	tests := []struct {
+		skuCase string
+		sku     string
+		want    string
+	}{
+		{SKUCaseUpper, "abc-123x", "ABC-123X"},
+		{SKUCaseLower, "ABC-123x", "abc-123x"},
+		{SKUCasePreserve, "Abc-123", "Abc-123"},
+		{"title", "Abc-123", "Abc-123"},
+	}
+	for _, tt := range tests {
+		if got := applySKUCase(tt.skuCase, tt.sku); got != tt.want {
+			t.Errorf("applySKUCase(%q, %q) = %q, want %q", tt.skuCase, tt.sku, got, tt.want)
+		}
+	}
+}
+
+func TestSKUCaseInsensitiveLookups(t *testing.T) {
+	tests := []struct {
+		skuCase string
+		stored  string
+	}{
+		{SKUCaseUpper, "ABC-123"},
+		{SKUCaseLower, "abc-123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.skuCase, func(t *testing.T) {
+			s, _ := newTestInventory(InventoryPolicy{SKUCase: tt.skuCase})
+			ctx := context.Background()
+
+			item, _, err := s.CreateItem(ctx, 1, &models.CreateItemRequest{Name: "Widget", SKU: "Abc-123", Quantity: 5}, "")
+			if err != nil {
+				t.Fatalf("CreateItem: %v", err)
+			}
+			if item.SKU != tt.stored {
+				t.Errorf("stored SKU = %q, want %q", item.SKU, tt.stored)
+			}
+
+			for _, sku := range []string{"abc-123", "ABC-123", "aBc-123"} {
+				if _, _, err := s.CreateItem(ctx, 1, &models.CreateItemRequest{Name: "Widget", SKU: sku}, ""); !errors.Is(err, ErrSKUExists) {
+					t.Errorf("creating %q: error = %v, want %v", sku, err, ErrSKUExists)
+				}
+				found, err := s.AdjustStockBySKU(ctx, sku, 1, &models.AdjustStockRequest{Delta: ptr(0.0)})
+				if err != nil || found.ID != item.ID {
+					t.Errorf("AdjustStockBySKU(%q) = %v, %v; want item %d", sku, found, err, item.ID)
+				}
+			}
+
+			updated, err := s.UpdateItem(ctx, item.ID, 1, &models.UpdateItemRequest{SKU: ptr("Xyz-9")}, "")
+			if err != nil {
+				t.Fatalf("UpdateItem: %v", err)
+			}
+			if want := applySKUCase(tt.skuCase, "Xyz-9"); updated.SKU != want {
+				t.Errorf("updated SKU = %q, want %q", updated.SKU, want)
+			}
+		})
+	}
+}
+
+func TestSKUCasePreserved(t *testing.T) {
+	s, _ := newTestInventory(InventoryPolicy{})
+	ctx := context.Background()
+	for _, sku := range []string{"abc-123", "ABC-123"} {
+		if _, created, err := s.CreateItem(ctx, 1, &models.CreateItemRequest{Name: "Widget", SKU: sku}, ""); err != nil || !created {
+			t.Errorf("creating %q = %v, %v; want it created", sku, created, err)
+		}
+	}
+}
+
+func TestSKUPrefix(t *testing.T) {
+	tests := []struct {
+		category string
+		want     string
+	}{
+		{"Tools", "TOO"},
+		{"hand tools", "HAN"},
+		{"3D printers", "3DP"},
+		{"A-1", "A1"},
+		{"Économie", "CON"},
+		{"", "ITM"},
+		{"---", "ITM"},
+	}
+	for _, tt := range tests {
+		if got := skuPrefix(tt.category); got != tt.want {
+			t.Errorf("skuPrefix(%q) = %q, want %q", tt.category, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratedSKUsConcurrent(t *testing.T) {
+	// Items with SKUs chosen by hand take some of the SKUs the sequence will produce
+	s, repo := newTestInventory(InventoryPolicy{AutoGenerateSKU: true, SKUGenerationAttempts: 5},
+		models.Item{Name: "Taken", SKU: "TOO-000002", Category: "Tools"},
+		models.Item{Name: "Taken", SKU: "TOO-000005", Category: "Tools"},
+		models.Item{Name: "Taken", SKU: "TOO-000006", Category: "Tools"},
+	)
+
+	const creates = 50
+	var wg sync.WaitGroup
+	errs := make([]error, creates)
+	items := make([]*models.Item, creates)
+	for i := 0; i < creates; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			items[i], _, errs[i] = s.CreateItem(context.Background(), 1, &models.CreateItemRequest{Name: "Hammer", Category: "Tools"}, "")
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("create %d: %v", i, err)
+		}
+		if seen[items[i].SKU] {
+			t.Errorf("SKU %s generated twice", items[i].SKU)
+		}
+		seen[items[i].SKU] = true
+	}
+	for _, taken := range []string{"TOO-000002", "TOO-000005", "TOO-000006"} {
+		if seen[taken] {
+			t.Errorf("generated SKU %s, which was already taken", taken)
+		}
+	}
+	if count, _ := repo.Count(context.Background(), repository.ListOptions{Category: "Tools"}); count != creates+3 {
+		t.Errorf("%d items in Tools, want %d", count, creates+3)
+	}
+}
+
+func TestGeneratedSKUAttempts(t *testing.T) {
+	taken := []models.Item{
+		{Name: "Taken", SKU: "ITM-000001"},
+		{Name: "Taken", SKU: "ITM-000002"},
+		{Name: "Taken", SKU: "ITM-000003"},
+	}
+	tests := []struct {
+		attempts int
+		wantErr  error
+		wantSKU  string
+	}{
+		{1, ErrSKUGeneration, ""},
+		{3, ErrSKUGeneration, ""},
+		{4, nil, "ITM-000004"},
+	}
+	for _, tt := range tests {
+		s, _ := newTestInventory(InventoryPolicy{AutoGenerateSKU: true, SKUGenerationAttempts: tt.attempts}, taken...)
+		item, _, err := s.CreateItem(context.Background(), 1, &models.CreateItemRequest{Name: "Widget"}, "")
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%d attempts: error = %v, want %v", tt.attempts, err, tt.wantErr)
+		}
+		if err == nil && item.SKU != tt.wantSKU {
+			t.Errorf("%d attempts: SKU = %s, want %s", tt.attempts, item.SKU, tt.wantSKU)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+	"github.com/nielwyn/inventory-system/internal/storage"
+)
+
+// snapshotBatchSize is how many items are read from the database at a time while writing a snapshot
+const snapshotBatchSize = 500
+
+// SnapshotService handles point-in-time exports of the inventory
+type SnapshotService interface {
+	CreateSnapshot(ctx context.Context) (string, error)
+}
+
+// SnapshotOptions holds where snapshots are written and how many are kept
+type SnapshotOptions struct {
+	Prefix    string
+	Retention int
+}
+
+type snapshotService struct {
+	repo  repository.InventoryRepository
+	store storage.Storage
+	opts  SnapshotOptions
+}
+
+// NewSnapshotService creates a new snapshot service
+func NewSnapshotService(repo repository.InventoryRepository, store storage.Storage, opts SnapshotOptions) SnapshotService {
+	return &snapshotService{repo: repo, store: store, opts: opts}
+}
+
+// CreateSnapshot writes every item, including its cost price, as a gzip-compressed JSON
+// array to storage, then prunes the oldest snapshots beyond the retention count. It
+// returns the key written.
+func (s *snapshotService) CreateSnapshot(ctx context.Context) (string, error) {
+	var buf bytes.Buffer
+	if err := s.writeItems(ctx, &buf); err != nil {
+		return "", err
+	}
+
+	key := s.keyPrefix() + time.Now().UTC().Format("20060102T150405Z") + ".json.gz"
+	if err := s.store.Put(ctx, key, &buf); err != nil {
+		return "", err
+	}
+
+	if err := s.prune(ctx); err != nil {
+		return key, err
+	}
+	return key, nil
+}
+
+// writeItems streams all items into w as a compressed JSON array
+func (s *snapshotService) writeItems(ctx context.Context, w *bytes.Buffer) error {
+	gz := gzip.NewWriter(w)
+	encoder := json.NewEncoder(gz)
+
+	if _, err := gz.Write([]byte("[")); err != nil {
+		return err
+	}
+	first := true
+	err := s.repo.FindInBatches(ctx, repository.ListOptions{}, snapshotBatchSize, func(items []models.Item) error {
+		for i := range items {
+			if !first {
+				if _, err := gz.Write([]byte(",")); err != nil {
+					return err
+				}
+			}
+			first = false
+			if err := encoder.Encode(items[i].WithCost()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := gz.Write([]byte("]")); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// prune deletes the oldest snapshots so that at most Retention remain.
+// Snapshot keys embed a UTC timestamp, so lexical order is chronological.
+func (s *snapshotService) prune(ctx context.Context) error {
+	if s.opts.Retention <= 0 {
+		return nil
+	}
+
+	keys, err := s.store.List(ctx, s.keyPrefix())
+	if err != nil {
+		return err
+	}
+	for len(keys) > s.opts.Retention {
+		if err := s.store.Delete(ctx, keys[0]); err != nil {
+			return err
+		}
+		keys = keys[1:]
+	}
+	return nil
+}
+
+func (s *snapshotService) keyPrefix() string {
+	return s.opts.Prefix + "inventory-"
+}
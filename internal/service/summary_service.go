@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// SummaryService provides the admin dashboard figures
+type SummaryService interface {
+	GetAdminSummary(ctx context.Context) (*models.AdminSummary, error)
+	GetInventoryValue(ctx context.Context) (float64, error)
+	GetBusinessReport(ctx context.Context) (*models.BusinessReport, error)
+}
+
+// reportLowStockLimit caps the low-stock list of the business report; low_stock_count
+// still counts every low-stocked item
+const reportLowStockLimit = 500
+
+// SummaryOptions configures how the admin summary is computed and cached
+type SummaryOptions struct {
+	// LowStockThreshold is the quantity at or below which an item counts as low on stock
+	LowStockThreshold float64
+	// ActivityWindow is how far back activity counts as recent
+	ActivityWindow time.Duration
+	// CacheTTL is how long a computed summary is served before it is recomputed (0 disables caching)
+	CacheTTL time.Duration
+}
+
+type summaryService struct {
+	repo repository.SummaryRepository
+	opts SummaryOptions
+
+	// mu guards the cached summary and is held while computing it, so concurrent
+	// dashboard loads share a single query
+	mu      sync.Mutex
+	cached  *models.AdminSummary
+	expires time.Time
+
+	// reportMu guards the cached business report in the same way
+	reportMu      sync.Mutex
+	cachedReport  *models.BusinessReport
+	reportExpires time.Time
+}
+
+// NewSummaryService creates a new summary service
+func NewSummaryService(repo repository.SummaryRepository, opts SummaryOptions) SummaryService {
+	return &summaryService{repo: repo, opts: opts}
+}
+
+// GetAdminSummary returns the dashboard figures, from cache while they are fresh
+func (s *summaryService) GetAdminSummary(ctx context.Context) (*models.AdminSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.cached != nil && now.Before(s.expires) {
+		return s.cached, nil
+	}
+
+	summary, err := s.repo.AdminSummary(ctx, s.opts.LowStockThreshold, now.Add(-s.opts.ActivityWindow))
+	if err != nil {
+		return nil, err
+	}
+	summary.GeneratedAt = now
+	s.cached, s.expires = summary, now.Add(s.opts.CacheTTL)
+	return summary, nil
+}
+
+// GetInventoryValue computes the total value of active stock with one aggregate query.
+// It is not cached; callers such as the metrics job decide how often to ask.
+func (s *summaryService) GetInventoryValue(ctx context.Context) (float64, error) {
+	return s.repo.InventoryValue(ctx)
+}
+
+// GetBusinessReport returns the business report and when it expires from cache
+func (s *summaryService) GetBusinessReport(ctx context.Context) (*models.BusinessReport, error) {
+	s.reportMu.Lock()
+	defer s.reportMu.Unlock()
+
+	now := time.Now()
+	if s.cachedReport != nil && now.Before(s.reportExpires) {
+		return s.cachedReport, nil
+	}
+
+	report, err := s.repo.BusinessReport(ctx, s.opts.LowStockThreshold, reportLowStockLimit)
+	if err != nil {
+		return nil, err
+	}
+	report.GeneratedAt = now
+	s.cachedReport, s.reportExpires = report, now.Add(s.opts.CacheTTL)
+	return report, nil
+}
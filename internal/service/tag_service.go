@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// ErrTagNotFound is returned when a tag does not exist
+var ErrTagNotFound = errors.New("tag not found")
+
+// TagService handles tag business logic
+type TagService interface {
+	AssignTag(ctx context.Context, tag string, req *models.TagItemsRequest) (*models.TagItemsResponse, error)
+	UnassignTag(ctx context.Context, tag string, req *models.TagItemsRequest) (*models.TagItemsResponse, error)
+}
+
+type tagService struct {
+	repo repository.TagRepository
+}
+
+// NewTagService creates a new tag service
+func NewTagService(repo repository.TagRepository) TagService {
+	return &tagService{repo: repo}
+}
+
+// AssignTag attaches a tag to several items, creating the tag on first use
+func (s *tagService) AssignTag(ctx context.Context, tag string, req *models.TagItemsRequest) (*models.TagItemsResponse, error) {
+	name, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := s.repo.Assign(ctx, name, req.ItemIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TagItemsResponse{Tag: name, Requested: len(req.ItemIDs), Affected: affected}, nil
+}
+
+// UnassignTag removes a tag from several items
+func (s *tagService) UnassignTag(ctx context.Context, tag string, req *models.TagItemsRequest) (*models.TagItemsResponse, error) {
+	name, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, &NotFoundError{Resource: "tag", Key: "name", Value: name, err: ErrTagNotFound}
+	}
+
+	affected, err := s.repo.Unassign(ctx, existing.ID, req.ItemIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TagItemsResponse{Tag: name, Requested: len(req.ItemIDs), Affected: affected}, nil
+}
+
+// normalizeTag trims and lower-cases a tag name so "Summer-Sale" and "summer-sale" are one tag
+func normalizeTag(tag string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(tag))
+	if name == "" {
+		return "", &ValidationError{Message: "Tag name is required"}
+	}
+	if len(name) > models.MaxTagNameLength {
+		return "", &ValidationError{Message: fmt.Sprintf("Tag name must be at most %d characters", models.MaxTagNameLength)}
+	}
+	return name, nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+// ClaimsHook returns extra claims to add to a token issued for user
+type ClaimsHook func(user *models.User) map[string]interface{}
+
+// userAttributes are the user fields UserClaims can copy into tokens, by claim name
+var userAttributes = map[string]func(user *models.User) interface{}{
+	"username": func(user *models.User) interface{} { return user.Username },
+	"email":    func(user *models.User) interface{} { return user.Email },
+}
+
+// UserClaims returns a ClaimsHook that adds the named attributes of the user (username,
+// email) to their tokens, or nil when none are named
+func UserClaims(names []string) (ClaimsHook, error) {
+	for _, name := range names {
+		if userAttributes[name] == nil {
+			return nil, fmt.Errorf("unknown user attribute %q (expected username or email)", name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return func(user *models.User) map[string]interface{} {
+		claims := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			claims[name] = userAttributes[name](user)
+		}
+		return claims
+	}, nil
+}
+
+// reservedClaims are the claims set by the server, which extra claims cannot replace:
+// those the API reads back and the other registered claims of RFC 7519
+var reservedClaims = map[string]bool{
+	"sub":             true,
+	"user_id":         true,
+	"role":            true,
+	"token_type":      true,
+	"sid":             true,
+	"impersonated_by": true,
+	"exp":             true,
+	"iat":             true,
+	"iss":             true,
+	"aud":             true,
+	"nbf":             true,
+	"jti":             true,
+}
+
+// ValidateExtraClaims checks that no configured extra claim is a reserved claim
+func ValidateExtraClaims(claims map[string]string) error {
+	for name := range claims {
+		if reservedClaims[name] {
+			return fmt.Errorf("claim %q is set by the server and cannot be overridden", name)
+		}
+	}
+	return nil
+}
+
+// mergeExtraClaims adds the configured claims and then those of the hook to claims.
+// Reserved claims are never overwritten.
+func (s *authService) mergeExtraClaims(claims jwt.MapClaims, user *models.User) {
+	for name, value := range s.opts.ExtraClaims {
+		if !reservedClaims[name] {
+			claims[name] = value
+		}
+	}
+	if s.opts.ClaimsHook == nil {
+		return
+	}
+	for name, value := range s.opts.ClaimsHook(user) {
+		if !reservedClaims[name] {
+			claims[name] = value
+		}
+	}
+}
+
+// StringClaim returns the named claim of token if it is a string
+func StringClaim(token *jwt.Token, name string) (string, bool) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", false
+	}
+	value, ok := claims[name].(string)
+	return value, ok
+}
+
+// StringSliceClaim returns the named claim of token if it is a list of strings.
+// A parsed token holds lists as []interface{}, so every element is checked.
+func StringSliceClaim(token *jwt.Token, name string) ([]string, bool) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, false
+	}
+	switch value := claims[name].(type) {
+	case []string:
+		return value, true
+	case []interface{}:
+		values := make([]string, len(value))
+		for i, element := range value {
+			s, ok := element.(string)
+			if !ok {
+				return nil, false
+			}
+			values[i] = s
+		}
+		return values, true
+	}
+	return nil, false
+}
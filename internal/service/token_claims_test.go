@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nielwyn/inventory-system/internal/models"
+)
+
+func TestExtraClaimsMerge(t *testing.T) {
+	s, _, _ := newTestAuth(func(opts *AuthOptions) {
+		opts.ExtraClaims = map[string]string{"tenant": "acme", "region": "eu", "role": "admin", "sub": "99"}
+		opts.ClaimsHook = func(user *models.User) map[string]interface{} {
+			return map[string]interface{}{
+				"tenant":      "acme-" + user.Username,
+				"permissions": []string{"items:read", "items:write"},
+				"exp":         0,
+				"sid":         "forged",
+			}
+		}
+	})
+	user := mustRegister(t, s, "frank", "frank-pass")
+
+	login, err := s.Login(context.Background(), &models.LoginRequest{Username: "frank", Password: "frank-pass"}, models.SessionClient{})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	token := mustValidate(t, s, login.Token)
+	claims := token.Claims.(jwt.MapClaims)
+
+	stringClaims := []struct {
+		name string
+		want string
+	}{
+		{"tenant", "acme-frank"}, // the hook is merged after the configured claims
+		{"region", "eu"},
+		{"role", user.Role},
+		{"sub", "1"},
+		{"token_type", TokenTypeAccess},
+	}
+	for _, tt := range stringClaims {
+		if got, ok := StringClaim(token, tt.name); !ok || got != tt.want {
+			t.Errorf("claim %s = %q, %v; want %q", tt.name, got, ok, tt.want)
+		}
+	}
+	if sid, _ := StringClaim(token, "sid"); sid == "forged" {
+		t.Error("the hook replaced the session ID")
+	}
+	if exp, _ := claims.GetExpirationTime(); exp == nil || exp.Unix() == 0 {
+		t.Errorf("exp = %v, want the server-set expiry", exp)
+	}
+	permissions, ok := StringSliceClaim(token, "permissions")
+	if want := []string{"items:read", "items:write"}; !ok || !reflect.DeepEqual(permissions, want) {
+		t.Errorf("permissions = %v, %v; want %v", permissions, ok, want)
+	}
+}
+
+func TestValidateExtraClaims(t *testing.T) {
+	tests := []struct {
+		claims  map[string]string
+		wantErr bool
+	}{
+		{nil, false},
+		{map[string]string{"tenant": "acme", "department": "ops"}, false},
+		{map[string]string{"tenant": "acme", "role": "admin"}, true},
+		{map[string]string{"exp": "0"}, true},
+		{map[string]string{"impersonated_by": "1"}, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateExtraClaims(tt.claims); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateExtraClaims(%v) error = %v, want error %v", tt.claims, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUserClaims(t *testing.T) {
+	user := &models.User{ID: 4, Username: "grace", Email: "grace@example.com", Role: models.RoleUser}
+	tests := []struct {
+		names   []string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{nil, nil, false},
+		{[]string{"email"}, map[string]interface{}{"email": "grace@example.com"}, false},
+		{[]string{"username", "email"}, map[string]interface{}{"username": "grace", "email": "grace@example.com"}, false},
+		{[]string{"email", "password"}, nil, true},
+	}
+	for _, tt := range tests {
+		hook, err := UserClaims(tt.names)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UserClaims(%v) error = %v, want error %v", tt.names, err, tt.wantErr)
+			continue
+		}
+		var got map[string]interface{}
+		if hook != nil {
+			got = hook(user)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("UserClaims(%v) adds %v, want %v", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestTypedClaims(t *testing.T) {
+	s, _, _ := newTestAuth(nil)
+	token := mustValidate(t, s, signTestToken(t, jwt.MapClaims{
+		"sub":     "1",
+		"tenant":  "acme",
+		"level":   3,
+		"groups":  []string{"a", "b"},
+		"mixed":   []interface{}{"a", 1},
+		"nothing": nil,
+	}))
+
+	tests := []struct {
+		name       string
+		wantString bool
+		wantSlice  bool
+	}{
+		{"tenant", true, false},
+		{"level", false, false},
+		{"groups", false, true},
+		{"mixed", false, false},
+		{"nothing", false, false},
+		{"missing", false, false},
+	}
+	for _, tt := range tests {
+		if _, ok := StringClaim(token, tt.name); ok != tt.wantString {
+			t.Errorf("StringClaim(%s) ok = %v, want %v", tt.name, ok, tt.wantString)
+		}
+		if _, ok := StringSliceClaim(token, tt.name); ok != tt.wantSlice {
+			t.Errorf("StringSliceClaim(%s) ok = %v, want %v", tt.name, ok, tt.wantSlice)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+// UserService handles user management business logic
+type UserService interface {
+	SearchUsers(ctx context.Context, query *models.SearchUsersQuery) ([]models.User, error)
+}
+
+type userService struct {
+	repo repository.UserRepository
+}
+
+// NewUserService creates a new user service
+func NewUserService(repo repository.UserRepository) UserService {
+	return &userService{repo: repo}
+}
+
+// SearchUsers retrieves a page of users whose username or email starts with the search term
+func (s *userService) SearchUsers(ctx context.Context, query *models.SearchUsersQuery) ([]models.User, error) {
+	return s.repo.Search(ctx, repository.UserSearch{
+		Prefix:     query.Search,
+		NewestLast: query.Sort == "created_at",
+		Offset:     query.Offset(),
+		Limit:      query.PageSize,
+	})
+}
@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Storage is an object store that files can be written to, listed and removed from
+type Storage interface {
+	// Put writes the contents of body under key, replacing any existing object
+	Put(ctx context.Context, key string, body io.Reader) error
+	// List returns the keys starting with prefix in lexical order
+	List(ctx context.Context, prefix string) ([]string, error)
+	// Delete removes the object stored under key
+	Delete(ctx context.Context, key string) error
+}
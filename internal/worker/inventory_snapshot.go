@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// InventorySnapshot exports the full inventory to object storage
+type InventorySnapshot struct {
+	snapshotService service.SnapshotService
+}
+
+// NewInventorySnapshot creates a new inventory snapshot job
+func NewInventorySnapshot(snapshotService service.SnapshotService) *InventorySnapshot {
+	return &InventorySnapshot{snapshotService: snapshotService}
+}
+
+// Name returns the job name used in logs
+func (j *InventorySnapshot) Name() string {
+	return "inventory_snapshot"
+}
+
+// Run writes a new snapshot and prunes old ones
+func (j *InventorySnapshot) Run(ctx context.Context) error {
+	key, err := j.snapshotService.CreateSnapshot(ctx)
+	if key != "" {
+		logger.Info("Wrote inventory snapshot", zap.String("key", key))
+	}
+	return err
+}
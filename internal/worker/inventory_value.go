@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/nielwyn/inventory-system/internal/metrics"
+	"github.com/nielwyn/inventory-system/internal/service"
+)
+
+// InventoryValue refreshes the inventory value metric, so scrapes of /metrics read a
+// stored figure instead of scanning every item
+type InventoryValue struct {
+	summaryService service.SummaryService
+}
+
+// NewInventoryValue creates a new inventory value job
+func NewInventoryValue(summaryService service.SummaryService) *InventoryValue {
+	return &InventoryValue{summaryService: summaryService}
+}
+
+// Name returns the job name used in logs
+func (j *InventoryValue) Name() string {
+	return "inventory_value"
+}
+
+// Run recomputes the inventory value with one aggregate query and publishes it
+func (j *InventoryValue) Run(ctx context.Context) error {
+	value, err := j.summaryService.GetInventoryValue(ctx)
+	if err != nil {
+		return err
+	}
+	metrics.SetInventoryValue(value)
+	return nil
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// stubSummary answers inventory value requests with value and err
+type stubSummary struct {
+	service.SummaryService
+	value float64
+	err   error
+	calls int
+}
+
+func (s *stubSummary) GetInventoryValue(context.Context) (float64, error) {
+	s.calls++
+	return s.value, s.err
+}
+
+// inventoryValueGauge reads the inventory_value gauge from the default registry
+func inventoryValueGauge(t *testing.T) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gathering metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == "inventory_value" {
+			return family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	t.Fatal("inventory_value is not registered")
+	return 0
+}
+
+func TestInventoryValueJob(t *testing.T) {
+	ctx := context.Background()
+	summary := &stubSummary{value: 1234.5}
+	job := NewInventoryValue(summary)
+
+	if err := job.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := inventoryValueGauge(t); got != 1234.5 {
+		t.Errorf("inventory_value = %v, want 1234.5", got)
+	}
+
+	summary.value = 99
+	if err := job.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := inventoryValueGauge(t); got != 99 {
+		t.Errorf("inventory_value after a second run = %v, want 99", got)
+	}
+
+	summary.err = errors.New("database unavailable")
+	if err := job.Run(ctx); !errors.Is(err, summary.err) {
+		t.Errorf("Run error = %v, want %v", err, summary.err)
+	}
+	if got := inventoryValueGauge(t); got != 99 {
+		t.Errorf("inventory_value after a failed run = %v, want the last value 99", got)
+	}
+	if summary.calls != 3 {
+		t.Errorf("computed the value %d times, want once per run", summary.calls)
+	}
+}
@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// PriceScheduler applies scheduled price changes once they become effective
+type PriceScheduler struct {
+	pricingService service.PricingService
+}
+
+// NewPriceScheduler creates a new price scheduler job
+func NewPriceScheduler(pricingService service.PricingService) *PriceScheduler {
+	return &PriceScheduler{pricingService: pricingService}
+}
+
+// Name returns the job name used in logs
+func (j *PriceScheduler) Name() string {
+	return "price_scheduler"
+}
+
+// Run applies all due price changes
+func (j *PriceScheduler) Run(ctx context.Context) error {
+	applied, err := j.pricingService.ApplyDuePrices(ctx)
+	if err != nil {
+		return err
+	}
+	if applied > 0 {
+		logger.Info("Applied scheduled price changes", zap.Int("count", applied))
+	}
+	return nil
+}
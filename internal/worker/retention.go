@@ -0,0 +1,37 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Retention deletes stock movements and price history older than their retention period
+type Retention struct {
+	retentionService service.RetentionService
+}
+
+// NewRetention creates a new retention purge job
+func NewRetention(retentionService service.RetentionService) *Retention {
+	return &Retention{retentionService: retentionService}
+}
+
+// Name returns the job name used in logs
+func (j *Retention) Name() string {
+	return "retention_purge"
+}
+
+// Run purges expired rows and logs how many were deleted from each table, including
+// when the run was cut short
+func (j *Retention) Run(ctx context.Context) error {
+	purged, err := j.retentionService.Purge(ctx)
+	for table, rows := range purged {
+		logger.Info("Purged expired rows", zap.String("table", table), zap.Int64("rows", rows))
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// stubRetention answers purges with purged and err
+type stubRetention struct {
+	service.RetentionService
+	purged map[string]int64
+	err    error
+}
+
+func (s stubRetention) Purge(context.Context) (map[string]int64, error) {
+	return s.purged, s.err
+}
+
+func TestRetentionRun(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	failure := errors.New("lock timeout")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		err     error
+		wantErr error
+	}{
+		{"completed", context.Background(), nil, nil},
+		{"failed", context.Background(), failure, failure},
+		{"shut down", cancelled, context.Canceled, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			t.Cleanup(logger.Replace(zap.New(core)))
+			job := NewRetention(stubRetention{purged: map[string]int64{"stock_movements": 1200, "price_history": 3}, err: tt.err})
+
+			if err := job.Run(tt.ctx); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Run error = %v, want %v", err, tt.wantErr)
+			}
+			// What was purged is logged however the run ended
+			rows := make(map[string]int64)
+			for _, entry := range logs.FilterMessage("Purged expired rows").All() {
+				fields := entry.ContextMap()
+				rows[fields["table"].(string)] = fields["rows"].(int64)
+			}
+			if rows["stock_movements"] != 1200 || rows["price_history"] != 3 {
+				t.Errorf("logged purged rows %v, want 1200 stock movements and 3 price changes", rows)
+			}
+		})
+	}
+}
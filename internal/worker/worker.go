@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Job is a unit of background work run on a fixed interval
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// Start runs job every interval in a new goroutine until ctx is cancelled.
+// The goroutine is tracked by wg so shutdown can wait for an in-flight run to finish.
+func Start(ctx context.Context, wg *sync.WaitGroup, job Job, interval time.Duration) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		logger.Info("Background job started", zap.String("job", job.Name()), zap.Duration("interval", interval))
+
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Info("Background job stopped", zap.String("job", job.Name()))
+				return
+			case <-ticker.C:
+				if err := job.Run(ctx); err != nil {
+					logger.Error("Background job failed", zap.String("job", job.Name()), zap.Error(err))
+				}
+			}
+		}
+	}()
+}
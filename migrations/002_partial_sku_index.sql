@@ -0,0 +1,8 @@
+-- Partial SKU uniqueness
+-- Soft-deleted items must not reserve their SKU, so uniqueness is enforced only
+-- across rows where deleted_at IS NULL. A deleted SKU can then be re-created.
+
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_sku_key;
+DROP INDEX IF EXISTS idx_items_sku;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_items_sku_active ON items(sku) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+-- Sequence backing server-generated SKUs
+-- Used when INVENTORY_SKU_AUTOGENERATE is enabled and an item is created without a SKU
+
+CREATE SEQUENCE IF NOT EXISTS item_sku_seq;
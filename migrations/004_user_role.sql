@@ -0,0 +1,5 @@
+-- User roles
+-- Existing users default to the "user" role; promote administrators manually:
+--   UPDATE users SET role = 'admin' WHERE username = '<name>';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
@@ -0,0 +1,4 @@
+-- Item cost price
+-- Existing items start with a cost of zero until one is recorded.
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS cost_price DECIMAL(10, 2) NOT NULL DEFAULT 0.00;
@@ -0,0 +1,9 @@
+-- Units of measure
+-- Existing items are counted individually ("each"). Quantities become decimals
+-- so items sold by weight, volume or length can hold fractional amounts.
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS unit VARCHAR(20) NOT NULL DEFAULT 'each';
+ALTER TABLE items ALTER COLUMN quantity TYPE DECIMAL(12, 3);
+
+ALTER TABLE stock_movements ALTER COLUMN delta TYPE DECIMAL(12, 3);
+ALTER TABLE stock_movements ALTER COLUMN quantity_after TYPE DECIMAL(12, 3);
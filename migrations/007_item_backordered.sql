@@ -0,0 +1,5 @@
+-- Backorders
+-- Items flagged as backordered have a quantity below zero, which is only
+-- possible when INVENTORY_ALLOW_NEGATIVE_STOCK is enabled.
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS backordered BOOLEAN NOT NULL DEFAULT FALSE;
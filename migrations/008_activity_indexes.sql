@@ -0,0 +1,5 @@
+-- Activity feed indexes
+-- Support listing a user's stock movements and price changes newest first.
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_user_created ON stock_movements (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_price_history_user_created ON price_history (user_id, created_at);
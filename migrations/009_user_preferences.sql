@@ -0,0 +1,11 @@
+-- User preferences
+-- Per-user defaults for list endpoints. Zero values mean no preference.
+
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    page_size BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR(3),
+    default_category VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
@@ -0,0 +1,7 @@
+-- User search indexes
+-- Admin user search matches a case-insensitive prefix of the username or email
+-- with LOWER(column) LIKE 'prefix%'. text_pattern_ops lets these indexes serve
+-- LIKE prefix matches regardless of the database collation.
+
+CREATE INDEX IF NOT EXISTS idx_users_username_lower ON users (LOWER(username) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email) text_pattern_ops);
@@ -0,0 +1,12 @@
+-- Item check constraints
+-- Backs up the service's validation so a direct write or a bug cannot store a
+-- negative price, or a negative quantity on an item that is not backordered.
+-- Existing rows that break these must be fixed before running this migration:
+--   SELECT id, sku, quantity, price FROM items
+--   WHERE price < 0 OR (quantity < 0 AND NOT backordered);
+
+ALTER TABLE items DROP CONSTRAINT IF EXISTS chk_items_quantity_nonnegative;
+ALTER TABLE items ADD CONSTRAINT chk_items_quantity_nonnegative CHECK (quantity >= 0 OR backordered);
+
+ALTER TABLE items DROP CONSTRAINT IF EXISTS chk_items_price_nonnegative;
+ALTER TABLE items ADD CONSTRAINT chk_items_price_nonnegative CHECK (price >= 0);
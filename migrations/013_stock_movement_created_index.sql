@@ -0,0 +1,4 @@
+-- Audit export index
+-- Supports exporting every stock movement in a date range.
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_created_at ON stock_movements (created_at);
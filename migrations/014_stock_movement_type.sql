@@ -0,0 +1,5 @@
+-- Stock movement types
+-- Distinguishes stock-take reconciliations from ordinary adjustments.
+-- Existing movements are all adjustments.
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'adjustment';
@@ -0,0 +1,22 @@
+-- Locations
+-- Places stock is held, and the quantity of each item held at each one.
+-- The (location_id, item_id) primary key serves a location's stock listing.
+
+CREATE TABLE IF NOT EXISTS locations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_locations_name ON locations(name);
+
+CREATE TABLE IF NOT EXISTS stock_levels (
+    location_id BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    item_id BIGINT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    quantity DECIMAL(12,3) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (location_id, item_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_levels_item_id ON stock_levels(item_id);
@@ -0,0 +1,26 @@
+-- Purchase orders
+-- Orders placed with suppliers and the quantity received on each line. Stock
+-- movements recorded for a receipt reference their purchase order.
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id SERIAL PRIMARY KEY,
+    supplier VARCHAR(200) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    created_by BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id BIGINT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    item_id BIGINT NOT NULL,
+    sku VARCHAR(100) NOT NULL,
+    ordered_quantity DECIMAL(12,3) NOT NULL,
+    received_quantity DECIMAL(12,3) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_purchase_order_id ON purchase_order_lines(purchase_order_id);
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS purchase_order_id BIGINT;
+CREATE INDEX IF NOT EXISTS idx_stock_movements_purchase_order_id ON stock_movements(purchase_order_id);
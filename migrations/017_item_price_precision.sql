@@ -0,0 +1,8 @@
+-- Item price precision
+-- Prices are validated to two decimal places and at most 99999999.99. Databases
+-- whose items table was created by AutoMigrate store price as an unbounded numeric;
+-- this gives them the DECIMAL(10, 2) column of the initial schema. Prices with more
+-- decimal places are rounded. Rows that would overflow must be fixed first:
+--   SELECT id, sku, price FROM items WHERE price >= 100000000;
+
+ALTER TABLE items ALTER COLUMN price TYPE DECIMAL(10, 2);
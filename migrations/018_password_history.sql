@@ -0,0 +1,12 @@
+-- Password history
+-- Hashes of the passwords users have replaced, so a password change can refuse
+-- one used recently. Only the newest AUTH_PASSWORD_HISTORY - 1 per user are kept.
+
+CREATE TABLE IF NOT EXISTS password_history (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    hash TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user_created ON password_history(user_id, created_at);
@@ -0,0 +1,10 @@
+-- Normalize user emails
+-- Emails are now trimmed and lower-cased on registration and lookup, so the unique
+-- index on email applies to the normalized form. This brings existing rows into that
+-- form. Accounts whose emails differ only in case or whitespace collide and must be
+-- merged or renamed first:
+--   SELECT LOWER(TRIM(email)) AS email, COUNT(*) FROM users
+--   GROUP BY LOWER(TRIM(email)) HAVING COUNT(*) > 1;
+
+UPDATE users SET email = LOWER(TRIM(email)) WHERE email <> LOWER(TRIM(email));
+UPDATE users SET username = TRIM(username) WHERE username <> TRIM(username);
@@ -0,0 +1,24 @@
+-- Bundles
+-- Kits made up of other items. A bundle holds no stock of its own; stock movements
+-- that take its components out of stock reference it.
+
+CREATE TABLE IF NOT EXISTS bundles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    created_by BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS bundle_lines (
+    id SERIAL PRIMARY KEY,
+    bundle_id BIGINT NOT NULL REFERENCES bundles(id) ON DELETE CASCADE,
+    item_id BIGINT NOT NULL,
+    sku VARCHAR(100) NOT NULL,
+    quantity DECIMAL(12,3) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_bundle_lines_bundle_id ON bundle_lines(bundle_id);
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS bundle_id BIGINT;
+CREATE INDEX IF NOT EXISTS idx_stock_movements_bundle_id ON stock_movements(bundle_id);
@@ -0,0 +1,5 @@
+-- Price history retention index
+-- Supports the retention purge, which deletes price changes older than
+-- RETENTION_PRICE_HISTORY oldest first. Stock movements already have one (013).
+
+CREATE INDEX IF NOT EXISTS idx_price_history_created_at ON price_history (created_at);
@@ -0,0 +1,7 @@
+-- Impersonation in the audit trail
+-- Records the admin behind a stock movement or price change made with an
+-- impersonation token; user_id remains the impersonated user. Changes made
+-- without one leave it NULL.
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS impersonator_id BIGINT;
+ALTER TABLE price_history ADD COLUMN IF NOT EXISTS impersonator_id BIGINT;
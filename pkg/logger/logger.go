@@ -1,14 +1,33 @@
 package logger
 
 import (
+	"sync"
+	"sync/atomic"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var log *zap.Logger
+var (
+	// log holds the global logger; it is read without locking on every log call
+	log atomic.Pointer[zap.Logger]
+
+	// mu serializes Init and the creation of the fallback logger
+	mu          sync.Mutex
+	initialized bool
+)
 
-// Init initializes the global logger
+// Init initializes the global logger.
+// It is safe to call concurrently; only the first call configures the logger and
+// later calls are no-ops. Init replaces the fallback logger if Get was called first.
 func Init(level, encoding string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if initialized {
+		return nil
+	}
+
 	var config zap.Config
 
 	if encoding == "json" {
@@ -25,22 +44,47 @@ func Init(level, encoding string) error {
 	}
 	config.Level = zap.NewAtomicLevelAt(zapLevel)
 
-	var err error
-	log, err = config.Build()
+	built, err := config.Build()
 	if err != nil {
 		return err
 	}
 
+	log.Store(built)
+	initialized = true
 	return nil
 }
 
-// Get returns the global logger
+// Get returns the global logger.
+// If Init has not been called yet, a production logger is created as a fallback.
+// Its entries carry "logger_fallback": true and a warning is logged when it is
+// created, so logging before Init shows up instead of silently using the wrong settings.
 func Get() *zap.Logger {
-	if log == nil {
-		// Fallback to a default logger if not initialized
-		log, _ = zap.NewProduction()
+	if l := log.Load(); l != nil {
+		return l
 	}
-	return log
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if l := log.Load(); l != nil {
+		return l
+	}
+	fallback, err := zap.NewProduction()
+	if err != nil {
+		fallback = zap.NewNop()
+	}
+	fallback = fallback.With(zap.Bool("logger_fallback", true))
+	fallback.Warn("Logger used before Init; using default production logger")
+	log.Store(fallback)
+	return fallback
+}
+
+// Replace sets the global logger to l, as tests do to capture log entries, and
+// returns a function that restores the previous one
+func Replace(l *zap.Logger) (restore func()) {
+	previous := Get()
+	log.Store(l)
+	return func() { log.Store(previous) }
 }
 
 // Info logs an info message
@@ -70,8 +114,8 @@ func Fatal(msg string, fields ...zap.Field) {
 
 // Sync flushes any buffered log entries
 func Sync() error {
-	if log != nil {
-		return log.Sync()
+	if l := log.Load(); l != nil {
+		return l.Sync()
 	}
 	return nil
 }
@@ -0,0 +1,116 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSON key casings
+const (
+	KeyCaseSnake = "snake" // page_size, as in the struct tags
+	KeyCaseCamel = "camel" // pageSize
+)
+
+// keyCase is the casing of the keys in response bodies
+var keyCase = KeyCaseSnake
+
+// SetKeyCase sets the casing of the keys in every response body sent by this package.
+// It must be called before the server starts handling requests.
+func SetKeyCase(c string) {
+	keyCase = c
+}
+
+// KeyCase returns the casing set by SetKeyCase
+func KeyCase() string {
+	return keyCase
+}
+
+// send writes body as JSON, with its keys in the configured casing
+func send(c *gin.Context, statusCode int, body Response) {
+	if keyCase == KeyCaseCamel {
+		if converted, err := convertKeys(body, snakeToCamel); err == nil {
+			c.JSON(statusCode, converted)
+			return
+		}
+	}
+	c.JSON(statusCode, body)
+}
+
+// SnakeKeys rewrites the object keys of a JSON document to snake_case, leaving keys that
+// already are unchanged, so request bodies may use either casing
+func SnakeKeys(data []byte) ([]byte, error) {
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(renameKeys(doc, camelToSnake))
+}
+
+// convertKeys round-trips v through JSON and renames its object keys with rename
+func convertKeys(v interface{}, rename func(string) string) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return renameKeys(doc, rename), nil
+}
+
+// renameKeys renames the keys of every object in a decoded JSON document
+func renameKeys(doc interface{}, rename func(string) string) interface{} {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			renamed[rename(key)] = renameKeys(value, rename)
+		}
+		return renamed
+	case []interface{}:
+		for i := range v {
+			v[i] = renameKeys(v[i], rename)
+		}
+		return v
+	default:
+		return doc
+	}
+}
+
+// snakeToCamel converts "quantity_after" to "quantityAfter"
+func snakeToCamel(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// camelToSnake converts "quantityAfter" to "quantity_after" and "itemID" to "item_id"
+func camelToSnake(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			acronymEnd := i > 0 && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || acronymEnd {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -1,6 +1,9 @@
 package response
 
 import (
+	"net/http"
+	"reflect"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -8,22 +11,90 @@ import (
 type Response struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message"`
+	Code    string      `json:"code,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
+	Meta    interface{} `json:"meta,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Pagination describes the page returned by a list endpoint
+type Pagination struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
 }
 
 // Success sends a successful response
 func Success(c *gin.Context, statusCode int, message string, data interface{}) {
-	c.JSON(statusCode, Response{
+	send(c, statusCode, Response{
 		Success: true,
 		Message: message,
-		Data:    data,
+		Data:    emptyList(data),
 	})
 }
 
+// SuccessWithMeta sends a successful response with additional metadata such as pagination
+func SuccessWithMeta(c *gin.Context, statusCode int, message string, data, meta interface{}) {
+	send(c, statusCode, Response{
+		Success: true,
+		Message: message,
+		Data:    emptyList(data),
+		Meta:    meta,
+	})
+}
+
+// deleteNoContent makes Deleted answer with 204 and no body
+var deleteNoContent bool
+
+// SetDeleteNoContent sets whether Deleted answers with 204 and no body rather than 200
+// with a message. It must be called before the server starts handling requests.
+func SetDeleteNoContent(enabled bool) {
+	deleteNoContent = enabled
+}
+
+// Deleted sends the response to a successful delete: 200 with the message and data, or
+// 204 with no body when configured by SetDeleteNoContent
+func Deleted(c *gin.Context, message string, data interface{}) {
+	if deleteNoContent {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	Success(c, http.StatusOK, message, data)
+}
+
 // Error sends an error response
 func Error(c *gin.Context, statusCode int, message string) {
-	c.JSON(statusCode, Response{
+	send(c, statusCode, Response{
 		Success: false,
 		Message: message,
 	})
 }
+
+// ErrorWithCode sends an error response with a machine-readable error code
+func ErrorWithCode(c *gin.Context, statusCode int, code, message string) {
+	send(c, statusCode, Response{
+		Success: false,
+		Message: message,
+		Code:    code,
+	})
+}
+
+// ErrorWithDetails sends an error response with a code and structured details about the failure
+func ErrorWithDetails(c *gin.Context, statusCode int, code, message string, details interface{}) {
+	send(c, statusCode, Response{
+		Success: false,
+		Message: message,
+		Code:    code,
+		Details: details,
+	})
+}
+
+// emptyList replaces a nil slice with an empty one of the same type, so a list with no
+// results is sent as [] rather than null. Repositories return nil slices when nothing
+// matches, and some clients cannot handle a null list.
+func emptyList(data interface{}) interface{} {
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return data
+}
@@ -0,0 +1,65 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// render sends a response through respond and returns the body
+func render(t *testing.T, respond func(c *gin.Context)) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	respond(c)
+	return w.Body.String()
+}
+
+func TestEmptyListsSentAsArrays(t *testing.T) {
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	tests := []struct {
+		name     string
+		data     interface{}
+		wantData string // the encoded data field, or empty when it is left out
+	}{
+		{"nil slice", []item(nil), `"data":[]`},
+		{"empty slice", []item{}, `"data":[]`},
+		{"nil slice of pointers", []*item(nil), `"data":[]`},
+		{"slice", []item{{ID: 1}}, `"data":[{"id":1}]`},
+		{"object", item{ID: 1}, `"data":{"id":1}`},
+		{"no data", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, camel := range []bool{false, true} {
+				if camel {
+					SetKeyCase(KeyCaseCamel)
+				}
+				bodies := []string{
+					render(t, func(c *gin.Context) { Success(c, http.StatusOK, "ok", tt.data) }),
+					render(t, func(c *gin.Context) {
+						SuccessWithMeta(c, http.StatusOK, "ok", tt.data, Pagination{Page: 1, PageSize: 20})
+					}),
+				}
+				SetKeyCase(KeyCaseSnake)
+
+				for _, body := range bodies {
+					if tt.wantData == "" {
+						if strings.Contains(body, `"data"`) {
+							t.Errorf("camel %v: body %s has a data field, want none", camel, body)
+						}
+					} else if !strings.Contains(body, tt.wantData) {
+						t.Errorf("camel %v: body %s, want it to contain %s", camel, body, tt.wantData)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package schema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect emitted by this package
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema represents a JSON Schema document
+type Schema struct {
+	Schema           string             `json:"$schema,omitempty"`
+	Title            string             `json:"title,omitempty"`
+	Type             string             `json:"type,omitempty"`
+	Format           string             `json:"format,omitempty"`
+	Properties       map[string]*Schema `json:"properties,omitempty"`
+	Required         []string           `json:"required,omitempty"`
+	Items            *Schema            `json:"items,omitempty"`
+	Enum             []string           `json:"enum,omitempty"`
+	MinLength        *int               `json:"minLength,omitempty"`
+	MaxLength        *int               `json:"maxLength,omitempty"`
+	MinItems         *int               `json:"minItems,omitempty"`
+	MaxItems         *int               `json:"maxItems,omitempty"`
+	Minimum          *float64           `json:"minimum,omitempty"`
+	Maximum          *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64           `json:"exclusiveMinimum,omitempty"`
+}
+
+// Generate builds a JSON Schema for a struct value from its json and binding tags
+func Generate(title string, v interface{}) *Schema {
+	s := forType(reflect.TypeOf(v))
+	s.Schema = Draft
+	s.Title = title
+	return s
+}
+
+// forType builds the schema for a Go type without any validation rules applied
+func forType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return forStruct(t)
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: forType(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		min := 0.0
+		return &Schema{Type: "integer", Minimum: &min}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{}
+	}
+}
+
+// forStruct builds an object schema from the exported, JSON-visible fields of a struct
+func forStruct(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+
+		prop := forType(field.Type)
+		if applyRules(prop, field.Tag.Get("binding")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+
+	return s
+}
+
+// jsonName returns the JSON key for a field, or "" if the field is not serialized
+func jsonName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// applyRules translates binding rules into schema constraints and reports whether
+// the field is required. Rules after "dive" apply to the array elements.
+func applyRules(s *Schema, binding string) bool {
+	if binding == "" {
+		return false
+	}
+
+	required := false
+	target := s
+	for _, rule := range strings.Split(binding, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if target == s {
+				required = true
+			}
+		case "dive":
+			if target.Items != nil {
+				target = target.Items
+			}
+		case "email":
+			target.Format = "email"
+		case "oneof":
+			target.Enum = strings.Fields(param)
+		case "positive":
+			zero := 0.0
+			target.ExclusiveMinimum = &zero
+		case "non_negative":
+			zero := 0.0
+			target.Minimum = &zero
+		case "min", "gte":
+			applyBound(target, param, true)
+		case "max", "lte":
+			applyBound(target, param, false)
+		}
+	}
+
+	return required
+}
+
+// applyBound sets the lower or upper bound appropriate for the schema type
+func applyBound(s *Schema, param string, lower bool) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch s.Type {
+	case "string":
+		length := int(n)
+		if lower {
+			s.MinLength = &length
+		} else {
+			s.MaxLength = &length
+		}
+	case "array":
+		count := int(n)
+		if lower {
+			s.MinItems = &count
+		} else {
+			s.MaxItems = &count
+		}
+	default:
+		if lower {
+			s.Minimum = &n
+		} else {
+			s.Maximum = &n
+		}
+	}
+}
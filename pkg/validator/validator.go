@@ -2,20 +2,77 @@ package validator
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
-// RegisterCustomValidations registers custom validation rules
-func RegisterCustomValidations() {
+// Decimal places kept by the "quantity" and "price" rules, matching their columns
+const (
+	quantityDecimals = 3
+	priceDecimals    = 2
+)
+
+// Limits holds the configurable bounds of the custom validation rules
+type Limits struct {
+	// MaxBulkSize is the most entries a field tagged "bulk" may hold
+	MaxBulkSize int
+	// MaxQuantity bounds the magnitude of fields tagged "quantity"
+	MaxQuantity float64
+	// MaxPrice bounds fields tagged "price"
+	MaxPrice float64
+}
+
+// limits is set once by RegisterCustomValidations
+var limits Limits
+
+// RegisterCustomValidations registers custom validation rules enforcing the given limits
+func RegisterCustomValidations(l Limits) {
+	limits = l
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("positive", validatePositive)
 		v.RegisterValidation("non_negative", validateNonNegative)
+		v.RegisterValidation("bulk", validateBulk)
+		v.RegisterValidation("quantity", validateQuantity)
+		v.RegisterValidation("price", validatePrice)
 	}
 }
 
+// MaxBulkSize returns the most entries a bulk request may hold
+func MaxBulkSize() int {
+	return limits.MaxBulkSize
+}
+
+// validateBulk validates that a slice holds no more than MaxBulkSize entries.
+// Tag it before dive so an oversized request fails before its entries are checked.
+func validateBulk(fl validator.FieldLevel) bool {
+	return fl.Field().Len() <= limits.MaxBulkSize
+}
+
+// validateQuantity validates that a quantity or quantity change is at most MaxQuantity
+// either way and fits the three decimal places quantities are stored with. Sign is left
+// to other rules.
+func validateQuantity(fl validator.FieldLevel) bool {
+	q := fl.Field().Float()
+	return math.Abs(q) <= limits.MaxQuantity && hasDecimals(q, quantityDecimals)
+}
+
+// validatePrice validates that a price is between zero and MaxPrice with at most
+// two decimal places
+func validatePrice(fl validator.FieldLevel) bool {
+	p := fl.Field().Float()
+	return p >= 0 && p <= limits.MaxPrice && hasDecimals(p, priceDecimals)
+}
+
+// hasDecimals reports whether v has no more than the given number of decimal places,
+// allowing for the error of its binary representation
+func hasDecimals(v float64, decimals int) bool {
+	scaled := v * math.Pow10(decimals)
+	return math.Abs(scaled-math.Round(scaled)) < 1e-6
+}
+
 // validatePositive validates that a number is positive
 func validatePositive(fl validator.FieldLevel) bool {
 	switch v := fl.Field().Interface().(type) {
@@ -69,10 +126,24 @@ func getErrorMessage(e validator.FieldError) string {
 		return fmt.Sprintf("must be at least %s", e.Param())
 	case "max":
 		return fmt.Sprintf("must be at most %s", e.Param())
+	case "bulk":
+		return fmt.Sprintf("must contain at most %d entries", limits.MaxBulkSize)
+	case "quantity":
+		return fmt.Sprintf("must be at most %.0f with at most %d decimal places", limits.MaxQuantity, quantityDecimals)
+	case "price":
+		return fmt.Sprintf("must be between 0 and %.2f with at most %d decimal places", limits.MaxPrice, priceDecimals)
 	case "positive":
 		return "must be positive"
 	case "non_negative":
 		return "must be non-negative"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", e.Param())
+	case "iso4217":
+		return "must be an ISO 4217 currency code"
+	case "ne":
+		return fmt.Sprintf("must not be %s", e.Param())
+	case "nefield":
+		return fmt.Sprintf("must differ from %s", e.Param())
 	default:
 		return fmt.Sprintf("failed validation '%s'", e.Tag())
 	}
@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestMain(m *testing.M) {
+	RegisterCustomValidations(Limits{MaxBulkSize: 3, MaxQuantity: 1000, MaxPrice: 99.99})
+	os.Exit(m.Run())
+}
+
+func TestBulk(t *testing.T) {
+	type request struct {
+		IDs []uint `binding:"required,min=1,bulk"`
+	}
+
+	tests := []struct {
+		size    int
+		wantErr bool
+	}{
+		{1, false},
+		{3, false},
+		{4, true},
+		{100, true},
+	}
+	for _, tt := range tests {
+		err := binding.Validator.ValidateStruct(&request{IDs: make([]uint, tt.size)})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%d entries: error = %v, want error %v", tt.size, err, tt.wantErr)
+		}
+		if want := "Field 'IDs' must contain at most 3 entries"; err != nil && FormatValidationError(err) != want {
+			t.Errorf("%d entries: message = %q, want %q", tt.size, FormatValidationError(err), want)
+		}
+	}
+}
+
+func TestQuantity(t *testing.T) {
+	type request struct {
+		Quantity float64 `binding:"quantity"`
+	}
+
+	tests := []struct {
+		quantity float64
+		wantErr  bool
+	}{
+		{0, false},
+		{1000, false},
+		{-1000, false},
+		{999.999, false},
+		{0.001, false},
+		{1000.001, true},
+		{-1000.001, true},
+		{1e12, true},
+		{0.0005, true},
+		{2.1234, true},
+	}
+	for _, tt := range tests {
+		err := binding.Validator.ValidateStruct(&request{Quantity: tt.quantity})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("quantity %v: error = %v, want error %v", tt.quantity, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPrice(t *testing.T) {
+	type request struct {
+		Price float64 `binding:"price"`
+	}
+
+	tests := []struct {
+		price   float64
+		wantErr bool
+	}{
+		{0, false},
+		{99.99, false},
+		{0.01, false},
+		{19.9, false},
+		{100, true},
+		{-0.01, true},
+		{0.001, true},
+		{9.999, true},
+	}
+	for _, tt := range tests {
+		err := binding.Validator.ValidateStruct(&request{Price: tt.price})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("price %v: error = %v, want error %v", tt.price, err, tt.wantErr)
+		}
+		if want := "Field 'Price' must be between 0 and 99.99 with at most 2 decimal places"; err != nil && FormatValidationError(err) != want {
+			t.Errorf("price %v: message = %q, want %q", tt.price, FormatValidationError(err), want)
+		}
+	}
+}
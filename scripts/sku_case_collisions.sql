@@ -0,0 +1,13 @@
+-- SKU case collisions
+-- Lists active SKUs that differ only in case. These must be merged or renamed
+-- before INVENTORY_SKU_CASE is enabled, otherwise only one of them can be found
+-- and converting the stored SKUs would violate the unique SKU index.
+
+SELECT UPPER(sku) AS normalized_sku,
+       COUNT(*) AS items,
+       STRING_AGG(sku || ' (id ' || id || ')', ', ' ORDER BY id) AS skus
+FROM items
+WHERE deleted_at IS NULL
+GROUP BY UPPER(sku)
+HAVING COUNT(*) > 1
+ORDER BY normalized_sku;
@@ -0,0 +1,62 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+func TestItemCheckConstraints(t *testing.T) {
+	tests := []struct {
+		name           string
+		item           models.Item
+		wantConstraint string
+	}{
+		{"negative quantity", models.Item{Quantity: -1}, "chk_items_quantity_nonnegative"},
+		{"negative price", models.Item{Price: -1}, "chk_items_price_nonnegative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reset(t)
+			repo := repository.NewInventoryRepository(db, repository.SoftDelete)
+			item := tt.item
+			item.Name, item.SKU, item.Unit = "Bolt", "BOLT-1", models.UnitEach
+
+			err := repo.Create(context.Background(), &item)
+			var checkErr *repository.CheckViolationError
+			if !errors.As(err, &checkErr) {
+				t.Fatalf("Create error = %v, want a check violation", err)
+			}
+			if checkErr.Constraint != tt.wantConstraint {
+				t.Errorf("constraint = %q, want %q", checkErr.Constraint, tt.wantConstraint)
+			}
+		})
+	}
+}
+
+func TestItemNumericOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		item models.Item
+	}{
+		{"quantity past DECIMAL(12,3)", models.Item{Quantity: 1e10}},
+		{"price past DECIMAL(10,2)", models.Item{Price: 1e9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reset(t)
+			repo := repository.NewInventoryRepository(db, repository.SoftDelete)
+			item := tt.item
+			item.Name, item.SKU, item.Unit = "Bolt", "BOLT-1", models.UnitEach
+
+			if err := repo.Create(context.Background(), &item); !errors.Is(err, repository.ErrNumericOverflow) {
+				t.Errorf("Create error = %v, want %v", err, repository.ErrNumericOverflow)
+			}
+		})
+	}
+}
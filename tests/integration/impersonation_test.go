@@ -0,0 +1,56 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+func TestImpersonatorInAuditTrail(t *testing.T) {
+	reset(t)
+	ctx := repository.ContextWithImpersonator(context.Background(), 1)
+	repo := repository.NewInventoryRepository(db, repository.SoftDelete)
+	item := createItem(t, "IMP-1", 5)
+
+	if _, err := repo.AdjustQuantity(ctx, item.ID, &models.StockMovement{Delta: 2, UserID: 7}, false); err != nil {
+		t.Fatalf("AdjustQuantity: %v", err)
+	}
+	if _, err := repo.AdjustQuantity(context.Background(), item.ID, &models.StockMovement{Delta: -1, UserID: 7}, false); err != nil {
+		t.Fatalf("AdjustQuantity: %v", err)
+	}
+	_, err := repo.BulkUpdatePrices(ctx, repository.PriceUpdate{Category: "Parts", Operation: models.PriceOperationAdd, Value: 1, UserID: 7})
+	if err != nil {
+		t.Fatalf("BulkUpdatePrices: %v", err)
+	}
+
+	activity, err := repository.NewActivityRepository(db).FindByUser(context.Background(), 7, 0, 10)
+	if err != nil {
+		t.Fatalf("FindByUser: %v", err)
+	}
+	impersonated := 0
+	for _, entry := range activity {
+		if entry.ImpersonatorID != nil && *entry.ImpersonatorID == 1 {
+			impersonated++
+		}
+	}
+	if len(activity) != 3 || impersonated != 2 {
+		t.Errorf("%d activity entries, %d made by the impersonator; want 3 and 2", len(activity), impersonated)
+	}
+
+	var exported []models.AuditRecord
+	err = repository.NewActivityRepository(db).StreamMovements(context.Background(), time.Now().Add(-time.Hour), time.Now().Add(time.Hour), func(record *models.AuditRecord) error {
+		exported = append(exported, *record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamMovements: %v", err)
+	}
+	if len(exported) != 2 || exported[0].ImpersonatorID == nil || *exported[0].ImpersonatorID != 1 || exported[1].ImpersonatorID != nil {
+		t.Errorf("exported movements = %+v, want the first made by impersonator 1 and the second not", exported)
+	}
+}
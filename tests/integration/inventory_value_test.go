@@ -0,0 +1,82 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+	"github.com/nielwyn/inventory-system/internal/service"
+	"github.com/nielwyn/inventory-system/internal/worker"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestInventoryValueMatchesRecompute(t *testing.T) {
+	reset(t)
+	ctx := context.Background()
+	repo := repository.NewInventoryRepository(db, repository.SoftDelete)
+	inventory := service.NewInventoryService(repo, service.InventoryPolicy{})
+	summary := service.NewSummaryService(repository.NewSummaryRepository(db), service.SummaryOptions{})
+
+	create := func(sku string, quantity, price float64) *models.Item {
+		t.Helper()
+		item, _, err := inventory.CreateItem(ctx, 1, &models.CreateItemRequest{Name: sku, SKU: sku, Quantity: quantity, Price: price}, "")
+		if err != nil {
+			t.Fatalf("creating %s: %v", sku, err)
+		}
+		return item
+	}
+	bolt := create("BOLT", 100, 0.25)
+	nut := create("NUT", 40, 0.1)
+	drill := create("DRILL", 3, 89.99)
+
+	if _, err := inventory.AdjustStock(ctx, bolt.ID, 1, &models.AdjustStockRequest{Delta: ptr(-30.0)}); err != nil {
+		t.Fatalf("adjusting bolts: %v", err)
+	}
+	if _, err := inventory.UpdateItem(ctx, drill.ID, 1, &models.UpdateItemRequest{Price: ptr(79.5)}, ""); err != nil {
+		t.Fatalf("repricing drills: %v", err)
+	}
+	if err := inventory.DeleteItem(ctx, nut.ID); err != nil {
+		t.Fatalf("deleting nuts: %v", err)
+	}
+	create("SAW", 2, 24.75)
+
+	var items []models.Item
+	if err := db.Find(&items).Error; err != nil {
+		t.Fatalf("listing items: %v", err)
+	}
+	var want float64
+	for _, item := range items {
+		want += item.Price * item.Quantity
+	}
+
+	got, err := summary.GetInventoryValue(ctx)
+	if err != nil {
+		t.Fatalf("GetInventoryValue: %v", err)
+	}
+	if math.Abs(got-want) > 0.005 {
+		t.Errorf("inventory value = %v, want %v recomputed from %d active items", got, want, len(items))
+	}
+
+	if err := worker.NewInventoryValue(summary).Run(ctx); err != nil {
+		t.Fatalf("running the inventory value job: %v", err)
+	}
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gathering metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == "inventory_value" {
+			if gauge := family.GetMetric()[0].GetGauge().GetValue(); math.Abs(gauge-want) > 0.005 {
+				t.Errorf("inventory_value gauge = %v, want %v", gauge, want)
+			}
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
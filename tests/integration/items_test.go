@@ -0,0 +1,147 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+	"github.com/nielwyn/inventory-system/internal/service"
+)
+
+func TestRecreateDeletedSKU(t *testing.T) {
+	reset(t)
+	ctx := context.Background()
+	repo := repository.NewInventoryRepository(db, repository.SoftDelete)
+
+	first := &models.Item{Name: "Lamp", SKU: "LAMP-1", Unit: models.UnitEach}
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("creating item: %v", err)
+	}
+	if err := repo.Delete(ctx, first.ID); err != nil {
+		t.Fatalf("deleting item: %v", err)
+	}
+
+	found, err := repo.FindBySKU(ctx, "LAMP-1")
+	if err != nil || found != nil {
+		t.Fatalf("FindBySKU after delete = %v, %v; want nothing", found, err)
+	}
+	second := &models.Item{Name: "Lamp", SKU: "LAMP-1", Unit: models.UnitEach}
+	if err := repo.Create(ctx, second); err != nil {
+		t.Fatalf("re-creating the SKU of a deleted item: %v", err)
+	}
+	if second.ID == first.ID {
+		t.Errorf("re-created item reused ID %d", first.ID)
+	}
+
+	third := &models.Item{Name: "Lamp", SKU: "LAMP-1", Unit: models.UnitEach}
+	if err := repo.Create(ctx, third); !errors.Is(err, repository.ErrDuplicateKey) {
+		t.Errorf("creating the SKU of an active item: error = %v, want %v", err, repository.ErrDuplicateKey)
+	}
+}
+
+func TestDeleteModes(t *testing.T) {
+	tests := []struct {
+		mode     repository.DeleteMode
+		wantKept bool
+	}{
+		{repository.SoftDelete, true},
+		{repository.HardDelete, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			reset(t)
+			ctx := context.Background()
+			repo := repository.NewInventoryRepository(db, tt.mode)
+			item := createItem(t, "DEL-1", 1)
+
+			if err := repo.Delete(ctx, item.ID); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if found, err := repo.FindByID(ctx, item.ID); err != nil || found != nil {
+				t.Errorf("FindByID after delete = %v, %v; want nothing", found, err)
+			}
+			kept, err := repo.FindByIDWithDeleted(ctx, item.ID)
+			if err != nil {
+				t.Fatalf("FindByIDWithDeleted: %v", err)
+			}
+			if (kept != nil) != tt.wantKept {
+				t.Errorf("row kept = %v, want %v", kept != nil, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestAdjustQuantityNegative(t *testing.T) {
+	tests := []struct {
+		allowNegative bool
+		wantErr       error
+		wantQuantity  float64
+	}{
+		{false, repository.ErrInsufficientStock, 3},
+		{true, nil, -2},
+	}
+	for _, tt := range tests {
+		reset(t)
+		ctx := context.Background()
+		repo := repository.NewInventoryRepository(db, repository.SoftDelete)
+		item := createItem(t, "NEG-1", 3)
+
+		movement := &models.StockMovement{Type: models.MovementTypeAdjustment, Delta: -5}
+		_, err := repo.AdjustQuantity(ctx, item.ID, movement, tt.allowNegative)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("allow negative %v: error = %v, want %v", tt.allowNegative, err, tt.wantErr)
+		}
+		stored, err := repo.FindByID(ctx, item.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if stored.Quantity != tt.wantQuantity || stored.Backordered != (tt.wantQuantity < 0) {
+			t.Errorf("allow negative %v: quantity %v, backordered %v; want %v", tt.allowNegative, stored.Quantity, stored.Backordered, tt.wantQuantity)
+		}
+	}
+}
+
+func TestCreateOnConflict(t *testing.T) {
+	tests := []struct {
+		onConflict   string
+		wantErr      error
+		wantQuantity float64
+	}{
+		{models.OnConflictError, service.ErrSKUExists, 3},
+		{models.OnConflictIgnore, nil, 3},
+		{models.OnConflictUpdate, nil, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.onConflict, func(t *testing.T) {
+			reset(t)
+			ctx := context.Background()
+			inventory := service.NewInventoryService(repository.NewInventoryRepository(db, repository.SoftDelete), service.InventoryPolicy{})
+			existing := createItem(t, "FEED-1", 3)
+
+			req := &models.CreateItemRequest{Name: "FEED-1", SKU: "FEED-1", Quantity: 8, Price: 10, Category: "Parts"}
+			item, created, err := inventory.CreateItem(ctx, 1, req, tt.onConflict)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (created || item.ID != existing.ID) {
+				t.Errorf("CreateItem = item %d, created %v; want existing item %d", item.ID, created, existing.ID)
+			}
+
+			var count int64
+			if err := db.Model(&models.Item{}).Where("sku = ?", "FEED-1").Count(&count).Error; err != nil || count != 1 {
+				t.Errorf("%d rows with the SKU (%v), want 1", count, err)
+			}
+			stored, err := inventory.GetItemByID(ctx, existing.ID, false)
+			if err != nil {
+				t.Fatalf("GetItemByID: %v", err)
+			}
+			if stored.Quantity != tt.wantQuantity {
+				t.Errorf("quantity = %v, want %v", stored.Quantity, tt.wantQuantity)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+//go:build integration
+
+// Package integration tests the repositories against a real Postgres database. The
+// connection comes from the same DB_* environment variables the API reads; the
+// database is migrated once and its tables are emptied before each test.
+package integration
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/config"
+	"github.com/nielwyn/inventory-system/internal/database"
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// db is the migrated test database
+var db *gorm.DB
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loading config: %v\n", err)
+		return 1
+	}
+	if err := logger.Init("error", "console"); err != nil {
+		fmt.Fprintf(os.Stderr, "initializing logger: %v\n", err)
+		return 1
+	}
+
+	testDB, err := database.New(cfg.Database.GetDSN(), cfg.Database.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "connecting: %v\n", err)
+		return 1
+	}
+	defer testDB.Close()
+	if err := testDB.AutoMigrate(0); err != nil {
+		fmt.Fprintf(os.Stderr, "migrating: %v\n", err)
+		return 1
+	}
+	db = testDB.DB
+	return m.Run()
+}
+
+// reset empties every table the tests write to
+func reset(t *testing.T) {
+	t.Helper()
+	err := db.Exec(`TRUNCATE items, stock_movements, price_history, scheduled_prices, tags, item_tags,
+		locations, stock_levels, purchase_orders, purchase_order_lines, bundles, bundle_lines,
+		users, password_history, user_preferences, sessions RESTART IDENTITY CASCADE`).Error
+	if err != nil {
+		t.Fatalf("resetting database: %v", err)
+	}
+}
+
+// createItem stores an item in stock with the given SKU
+func createItem(t *testing.T, sku string, quantity float64) *models.Item {
+	t.Helper()
+	item := &models.Item{Name: sku, SKU: sku, Quantity: quantity, Unit: models.UnitEach, Price: 10, Category: "Parts"}
+	if err := db.Create(item).Error; err != nil {
+		t.Fatalf("creating item %s: %v", sku, err)
+	}
+	return item
+}
@@ -0,0 +1,66 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+func TestMergeReferencesBundleLines(t *testing.T) {
+	reset(t)
+	ctx := context.Background()
+	source := createItem(t, "SRC-1", 5)
+	target := createItem(t, "TGT-1", 5)
+	other := createItem(t, "OTH-1", 5)
+
+	// One bundle holds only the source, the other holds both items
+	sourceOnly := &models.Bundle{Name: "source only", Lines: []models.BundleLine{
+		{ItemID: source.ID, SKU: source.SKU, Quantity: 2},
+		{ItemID: other.ID, SKU: other.SKU, Quantity: 1},
+	}}
+	both := &models.Bundle{Name: "both", Lines: []models.BundleLine{
+		{ItemID: source.ID, SKU: source.SKU, Quantity: 2},
+		{ItemID: target.ID, SKU: target.SKU, Quantity: 3},
+	}}
+	bundles := repository.NewBundleRepository(db)
+	for _, bundle := range []*models.Bundle{sourceOnly, both} {
+		if err := bundles.Create(ctx, bundle); err != nil {
+			t.Fatalf("creating bundle %q: %v", bundle.Name, err)
+		}
+	}
+
+	repo := repository.NewInventoryRepository(db, repository.SoftDelete)
+	if err := repo.MergeReferences(ctx, source.ID, target); err != nil {
+		t.Fatalf("MergeReferences: %v", err)
+	}
+
+	tests := []struct {
+		bundle *models.Bundle
+		want   map[uint]float64
+	}{
+		{sourceOnly, map[uint]float64{target.ID: 2, other.ID: 1}},
+		{both, map[uint]float64{target.ID: 5}},
+	}
+	for _, tt := range tests {
+		var lines []models.BundleLine
+		if err := db.Where("bundle_id = ?", tt.bundle.ID).Find(&lines).Error; err != nil {
+			t.Fatalf("loading lines of %q: %v", tt.bundle.Name, err)
+		}
+		if len(lines) != len(tt.want) {
+			t.Errorf("bundle %q has %d lines, want %d: %+v", tt.bundle.Name, len(lines), len(tt.want), lines)
+		}
+		for _, line := range lines {
+			want, ok := tt.want[line.ItemID]
+			if !ok || line.Quantity != want {
+				t.Errorf("bundle %q: line for item %d has quantity %v, want %v", tt.bundle.Name, line.ItemID, line.Quantity, tt.want[line.ItemID])
+			}
+			if line.ItemID == target.ID && line.SKU != target.SKU {
+				t.Errorf("bundle %q: line for the target has SKU %q, want %q", tt.bundle.Name, line.SKU, target.SKU)
+			}
+		}
+	}
+}
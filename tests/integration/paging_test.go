@@ -0,0 +1,57 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+func TestStablePagingWithEqualTimestamps(t *testing.T) {
+	reset(t)
+	ctx := context.Background()
+	repo := repository.NewInventoryRepository(db, repository.SoftDelete)
+
+	const total, pageSize = 10, 3
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < total; i++ {
+		sku := fmt.Sprintf("SAME-%02d", i)
+		item := &models.Item{Name: sku, SKU: sku, Unit: models.UnitEach, Category: "Parts", CreatedAt: created, UpdatedAt: created}
+		if err := db.Create(item).Error; err != nil {
+			t.Fatalf("creating item %s: %v", sku, err)
+		}
+	}
+
+	for _, sortBy := range []string{"created_at", "-created_at", "category,-updated_at"} {
+		t.Run(sortBy, func(t *testing.T) {
+			sort, err := repository.ParseSort(sortBy)
+			if err != nil {
+				t.Fatalf("ParseSort: %v", err)
+			}
+			seen := make(map[uint]bool)
+			for offset := 0; offset < total; offset += pageSize {
+				page, err := repo.FindAll(ctx, repository.ListOptions{Sort: sort, Offset: offset, Limit: pageSize})
+				if err != nil {
+					t.Fatalf("FindAll at offset %d: %v", offset, err)
+				}
+				for i, item := range page {
+					if seen[item.ID] {
+						t.Errorf("item %d repeated on the page at offset %d", item.ID, offset)
+					}
+					seen[item.ID] = true
+					if i > 0 && item.ID < page[i-1].ID {
+						t.Errorf("items %d and %d out of ID order at offset %d", page[i-1].ID, item.ID, offset)
+					}
+				}
+			}
+			if len(seen) != total {
+				t.Errorf("paging returned %d distinct items, want %d", len(seen), total)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+	"github.com/nielwyn/inventory-system/internal/service"
+)
+
+func TestDecimalQuantities(t *testing.T) {
+	reset(t)
+	ctx := context.Background()
+	inventory := service.NewInventoryService(repository.NewInventoryRepository(db, repository.SoftDelete), service.InventoryPolicy{})
+	summary := service.NewSummaryService(repository.NewSummaryRepository(db), service.SummaryOptions{})
+
+	create := func(sku, unit string, quantity float64) *models.Item {
+		t.Helper()
+		item, _, err := inventory.CreateItem(ctx, 1, &models.CreateItemRequest{Name: sku, SKU: sku, Quantity: quantity, Unit: unit, Price: 1}, "")
+		if err != nil {
+			t.Fatalf("creating %s: %v", sku, err)
+		}
+		return item
+	}
+	bolt := create("BOLT", models.UnitEach, 10)
+	flour := create("FLOUR", models.UnitKg, 1.5)
+	create("SUGAR", models.UnitKg, 0.25)
+
+	for i := 0; i < 3; i++ {
+		if _, err := inventory.AdjustStock(ctx, flour.ID, 1, &models.AdjustStockRequest{Delta: ptr(0.1)}); err != nil {
+			t.Fatalf("adding flour: %v", err)
+		}
+	}
+	if _, err := inventory.AdjustStock(ctx, bolt.ID, 1, &models.AdjustStockRequest{Delta: ptr(0.5)}); !errors.Is(err, service.ErrValidation) {
+		t.Errorf("half a bolt: error = %v, want a validation error", err)
+	}
+	if _, err := inventory.AdjustStock(ctx, bolt.ID, 1, &models.AdjustStockRequest{Delta: ptr(-4.0)}); err != nil {
+		t.Fatalf("removing bolts: %v", err)
+	}
+
+	stored, err := inventory.GetItemByID(ctx, flour.ID, false)
+	if err != nil {
+		t.Fatalf("reading flour: %v", err)
+	}
+	if stored.Quantity != 1.8 {
+		t.Errorf("flour quantity = %v, want exactly 1.8", stored.Quantity)
+	}
+
+	report, err := summary.GetBusinessReport(ctx)
+	if err != nil {
+		t.Fatalf("GetBusinessReport: %v", err)
+	}
+	want := map[string]float64{models.UnitEach: 6, models.UnitKg: 2.05}
+	if len(report.Quantities) != len(want) {
+		t.Fatalf("quantities = %+v, want %v", report.Quantities, want)
+	}
+	for _, q := range report.Quantities {
+		if q.Quantity != want[q.Unit] {
+			t.Errorf("%s quantity = %v, want %v", q.Unit, q.Quantity, want[q.Unit])
+		}
+	}
+}
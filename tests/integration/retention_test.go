@@ -0,0 +1,68 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+	"github.com/nielwyn/inventory-system/internal/service"
+)
+
+func TestRetentionPurgesOnlyOldRows(t *testing.T) {
+	reset(t)
+	item := createItem(t, "RET-1", 1)
+	const day = 24 * time.Hour
+	now := time.Now()
+
+	// 5 movements and 2 price changes past retention, and recent ones that must stay
+	var movements []models.StockMovement
+	for _, age := range []time.Duration{800 * day, 750 * day, 731 * day, 731 * day, 731 * day, 729 * day, day, 0} {
+		movements = append(movements, models.StockMovement{ItemID: item.ID, Delta: 1, QuantityAfter: 1, Type: models.MovementTypeAdjustment, CreatedAt: now.Add(-age)})
+	}
+	if err := db.Create(&movements).Error; err != nil {
+		t.Fatalf("creating movements: %v", err)
+	}
+	var prices []models.PriceHistory
+	for _, age := range []time.Duration{100 * day, 91 * day, 89 * day} {
+		prices = append(prices, models.PriceHistory{ItemID: item.ID, OldPrice: 1, NewPrice: 2, CreatedAt: now.Add(-age)})
+	}
+	if err := db.Create(&prices).Error; err != nil {
+		t.Fatalf("creating price history: %v", err)
+	}
+
+	s := service.NewRetentionService(repository.NewRetentionRepository(db), service.RetentionOptions{
+		Periods: map[string]time.Duration{
+			repository.RetentionStockMovements: 730 * day,
+			repository.RetentionPriceHistory:   90 * day,
+		},
+		BatchSize: 2,
+	})
+	purged, err := s.Purge(context.Background())
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if purged[repository.RetentionStockMovements] != 5 || purged[repository.RetentionPriceHistory] != 2 {
+		t.Errorf("purged %v, want 5 stock movements and 2 price changes", purged)
+	}
+
+	cutoffs := []struct {
+		table  string
+		cutoff time.Time
+		kept   int64
+	}{
+		{repository.RetentionStockMovements, now.Add(-730 * day), 3},
+		{repository.RetentionPriceHistory, now.Add(-90 * day), 1},
+	}
+	for _, c := range cutoffs {
+		var kept, expired int64
+		db.Table(c.table).Count(&kept)
+		db.Table(c.table).Where("created_at < ?", c.cutoff).Count(&expired)
+		if kept != c.kept || expired != 0 {
+			t.Errorf("%s keeps %d rows, %d of them expired; want %d recent rows", c.table, kept, expired, c.kept)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/repository"
+)
+
+func TestSearchWildcardsMatchLiterally(t *testing.T) {
+	reset(t)
+	for _, sku := range []string{"A_1", "AB1", "100%", "1000", `C\D`, "CD"} {
+		createItem(t, sku, 1)
+	}
+	repo := repository.NewInventoryRepository(db, repository.SoftDelete)
+
+	tests := []struct {
+		search string
+		want   []string
+	}{
+		{"_", []string{"A_1"}},
+		{"a_1", []string{"A_1"}},
+		{"%", []string{"100%"}},
+		{"0%", []string{"100%"}},
+		{`\`, []string{`C\D`}},
+		{"1", []string{"1000", "100%", "A_1", "AB1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			items, err := repo.FindAll(context.Background(), repository.ListOptions{Search: tt.search})
+			if err != nil {
+				t.Fatalf("FindAll: %v", err)
+			}
+			got := make([]string, len(items))
+			for i, item := range items {
+				got[i] = item.SKU
+			}
+			sort.Strings(got)
+			sort.Strings(tt.want)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("search %q matched %q, want %q", tt.search, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/nielwyn/inventory-system/internal/models"
+	"github.com/nielwyn/inventory-system/internal/repository"
+	"github.com/nielwyn/inventory-system/internal/service"
+)
+
+func TestGeneratedSKUsConcurrent(t *testing.T) {
+	reset(t)
+	repo := repository.NewInventoryRepository(db, repository.SoftDelete)
+	seq, err := repo.NextSKUSequence(context.Background())
+	if err != nil {
+		t.Fatalf("NextSKUSequence: %v", err)
+	}
+	// Hand-picked SKUs take some of the values the sequence hands out next
+	for _, sku := range []string{skuFor(seq + 2), skuFor(seq + 3), skuFor(seq + 7)} {
+		createItem(t, sku, 1)
+	}
+	inventory := service.NewInventoryService(repo, service.InventoryPolicy{
+		AutoGenerateSKU:       true,
+		SKUFormat:             service.DefaultSKUFormat,
+		SKUGenerationAttempts: 5,
+	})
+
+	const creates = 40
+	var wg sync.WaitGroup
+	errs := make([]error, creates)
+	skus := make([]string, creates)
+	for i := 0; i < creates; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			item, _, err := inventory.CreateItem(context.Background(), 1, &models.CreateItemRequest{Name: "Hammer", Category: "Parts"}, "")
+			errs[i] = err
+			if err == nil {
+				skus[i] = item.SKU
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("create %d: %v", i, err)
+			continue
+		}
+		if seen[skus[i]] {
+			t.Errorf("SKU %s generated twice", skus[i])
+		}
+		seen[skus[i]] = true
+	}
+	var count int64
+	if err := db.Model(&models.Item{}).Count(&count).Error; err != nil || count != creates+3 {
+		t.Errorf("%d items stored (%v), want %d", count, err, creates+3)
+	}
+}
+
+// skuFor returns the SKU generated for an item in Parts from sequence value seq
+func skuFor(seq int64) string {
+	return fmt.Sprintf("PAR-%06d", seq)
+}